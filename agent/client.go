@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/cybozu-go/meows/runner"
 )
@@ -36,7 +37,7 @@ var captions = map[string]string{
 	runner.JobResultUnknown:   "Finished(Unknown)",
 }
 
-func makePayload(result string, namespaceName, podName string, info *runner.JobInfo) *resultAPIPayload {
+func makePayload(result string, failedSteps []string, namespaceName, podName string, info *runner.JobInfo) *resultAPIPayload {
 	color, ok := colors[result]
 	if !ok {
 		color = colors[runner.JobResultUnknown]
@@ -49,13 +50,25 @@ func makePayload(result string, namespaceName, podName string, info *runner.JobI
 	var text, job, pod string
 	if info != nil {
 		text = fmt.Sprintf("%s: %s's CI job in <%s|%s>", head, info.Actor, info.RepositoryURL(), info.Repository)
-		job = fmt.Sprintf("<%s|%s #%d> [%s]", info.WorkflowURL(), info.WorkflowName, info.RunNumber, info.JobID)
+		if info.CommitSHA != "" {
+			text += fmt.Sprintf(" at <%s|%s>", info.CommitURL(), shortSHA(info.CommitSHA))
+		}
+		job = fmt.Sprintf("<%s|%s #%d> [%s]", info.JobOrWorkflowURL(), info.WorkflowName, info.RunNumber, info.JobID)
+		if info.RunAttempt > 1 {
+			job += fmt.Sprintf(" attempt %d", info.RunAttempt)
+		}
+		if info.EventName != "" {
+			job += " on " + info.EventName
+		}
 		pod = fmt.Sprintf("%s/%s", namespaceName, podName)
 	} else {
 		text = fmt.Sprintf("%s: (failed to get job status)", head)
 		job = "(unknown)"
 		pod = fmt.Sprintf("%s/%s", namespaceName, podName)
 	}
+	if len(failedSteps) != 0 {
+		text += "\nFailed steps: " + strings.Join(failedSteps, ", ")
+	}
 
 	return &resultAPIPayload{
 		Color: color,
@@ -65,6 +78,14 @@ func makePayload(result string, namespaceName, podName string, info *runner.JobI
 	}
 }
 
+// shortSHA returns the abbreviated commit SHA shown by GitHub.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
 // Client is a client for Slack agent.
 type Client struct {
 	serverURL *url.URL
@@ -106,8 +127,8 @@ func (c *Client) UpdateServerURL(serverURL string) error {
 }
 
 // PostResult sends a result of CI job to server.
-func (c *Client) PostResult(ctx context.Context, channel, result string, extend bool, namespaceName, podName string, info *runner.JobInfo) error {
-	payload := makePayload(result, namespaceName, podName, info)
+func (c *Client) PostResult(ctx context.Context, channel, result string, failedSteps []string, extend bool, namespaceName, podName string, info *runner.JobInfo) error {
+	payload := makePayload(result, failedSteps, namespaceName, podName, info)
 	payload.Channel = channel
 	payload.Extend = extend
 
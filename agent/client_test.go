@@ -29,13 +29,29 @@ func TestMakePayload(t *testing.T) {
 		WorkflowName:   "Work flow",
 	}
 
+	retriedJob := &runner.JobInfo{
+		Actor:          "user",
+		CommitSHA:      "0123456789abcdef0123456789abcdef01234567",
+		EventName:      "push",
+		GitRef:         "main",
+		JobID:          "job",
+		PullRequestNum: 0,
+		Repository:     "owner/repo",
+		RunAttempt:     2,
+		RunID:          123456789,
+		RunNumber:      987,
+		WorkflowName:   "Work flow",
+		JobURL:         "https://github.com/owner/repo/actions/runs/123456789/job/111",
+	}
+
 	testCases := []struct {
 		title string
 
-		inputResult    string
-		inputNamespace string
-		inputPod       string
-		inputJobInfo   *runner.JobInfo
+		inputResult      string
+		inputFailedSteps []string
+		inputNamespace   string
+		inputPod         string
+		inputJobInfo     *runner.JobInfo
 
 		expected *resultAPIPayload
 	}{
@@ -144,11 +160,42 @@ func TestMakePayload(t *testing.T) {
 				Pod:   "my-namespace/my-pod",
 			},
 		},
+		{
+			title: "failed steps",
+
+			inputResult:      "failure",
+			inputFailedSteps: []string{"Run make test", "Run make lint"},
+			inputNamespace:   "my-namespace",
+			inputPod:         "my-pod",
+			inputJobInfo:     pullRequestJob,
+
+			expected: &resultAPIPayload{
+				Color: colorRed,
+				Text:  "Failure: user's CI job in <https://github.com/owner/repo|owner/repo>\nFailed steps: Run make test, Run make lint",
+				Job:   "<https://github.com/owner/repo/actions/runs/123456789|Work flow #987> [job]",
+				Pod:   "my-namespace/my-pod",
+			},
+		},
+		{
+			title: "retried job with job url",
+
+			inputResult:    "failure",
+			inputNamespace: "my-namespace",
+			inputPod:       "my-pod",
+			inputJobInfo:   retriedJob,
+
+			expected: &resultAPIPayload{
+				Color: colorRed,
+				Text:  "Failure: user's CI job in <https://github.com/owner/repo|owner/repo> at <https://github.com/owner/repo/commit/0123456789abcdef0123456789abcdef01234567|0123456>",
+				Job:   "<https://github.com/owner/repo/actions/runs/123456789/job/111|Work flow #987> [job] attempt 2 on push",
+				Pod:   "my-namespace/my-pod",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.title, func(t *testing.T) {
-			actual := makePayload(tc.inputResult, tc.inputNamespace, tc.inputPod, tc.inputJobInfo)
+			actual := makePayload(tc.inputResult, tc.inputFailedSteps, tc.inputNamespace, tc.inputPod, tc.inputJobInfo)
 			if !cmp.Equal(tc.expected, actual) {
 				t.Error(tc.title, "| payload", cmp.Diff(tc.expected, actual))
 			}
@@ -46,7 +46,8 @@ type Server struct {
 }
 
 // NewServer creates slack agent server.
-func NewServer(logger logr.Logger, listenAddr string, defaultChannel string, appToken, botToken string, devMode bool, verbose bool) (*Server, error) {
+// runnerAPIKey is used to authenticate to the runner pods. If it is nil, the requests are sent without the tokens.
+func NewServer(logger logr.Logger, listenAddr string, defaultChannel string, appToken, botToken string, runnerAPIKey []byte, devMode bool, verbose bool) (*Server, error) {
 	apiClient := slack.New(
 		botToken,
 		slack.OptionAppLevelToken(appToken),
@@ -79,7 +80,7 @@ func NewServer(logger logr.Logger, listenAddr string, defaultChannel string, app
 		smClient:       smClient,
 		devMood:        devMode,
 		clientset:      clientset,
-		runnerClient:   runner.NewClient(),
+		runnerClient:   runner.NewClient(runnerAPIKey),
 	}, nil
 }
 
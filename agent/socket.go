@@ -147,7 +147,7 @@ func (s *Server) extendPod(ctx context.Context, channel, namespace, pod string)
 		return err
 	}
 
-	status, err := s.runnerClient.GetStatus(ctx, po.Status.PodIP)
+	status, err := s.runnerClient.GetStatus(ctx, po)
 	if err != nil {
 		s.log.Error(err, "failed to get runner status",
 			"name", pod,
@@ -190,7 +190,7 @@ func (s *Server) deletePod(ctx context.Context, channel, namespace, pod string)
 func (s *Server) putDeletionTime(ctx context.Context, channel, namespace, pod string, po *corev1.Pod, tm time.Time) error {
 	success := true
 	if !s.devMood {
-		err := s.runnerClient.PutDeletionTime(ctx, po.Status.PodIP, tm)
+		err := s.runnerClient.PutDeletionTime(ctx, po, tm)
 		if err != nil {
 			s.log.Error(err, "failed to update deletion time",
 				"name", pod,
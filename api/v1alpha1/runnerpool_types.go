@@ -5,22 +5,29 @@ package v1alpha1
 
 import (
 	"fmt"
+	"net/url"
+	"path"
+	"slices"
 	"strings"
 	"time"
 
 	constants "github.com/cybozu-go/meows"
+	"github.com/robfig/cron/v3"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 var reservedEnvNames = map[string]bool{
-	constants.PodNameEnvName:        true,
-	constants.PodNamespaceEnvName:   true,
-	constants.RunnerOrgEnvName:      true,
-	constants.RunnerRepoEnvName:     true,
-	constants.RunnerPoolNameEnvName: true,
-	constants.RunnerOptionEnvName:   true,
+	constants.PodNameEnvName:          true,
+	constants.PodNamespaceEnvName:     true,
+	constants.RunnerOrgEnvName:        true,
+	constants.RunnerRepoEnvName:       true,
+	constants.RunnerEnterpriseEnvName: true,
+	constants.RunnerPoolNameEnvName:   true,
+	constants.RunnerOptionEnvName:     true,
+	constants.RepositoryCachesEnvName: true,
 }
 
 // RunnerPoolSpec defines the desired state of RunnerPool
@@ -33,11 +40,53 @@ type RunnerPoolSpec struct {
 	// +optional
 	Organization string `json:"organization,omitempty"`
 
+	// Enterprise name. If this field is specified, meows registers pods as enterprise-level runners.
+	// +optional
+	Enterprise string `json:"enterprise,omitempty"`
+
 	// CredentialSecretName is a Secret name that contains a GitHub Credential.
 	// If this field is omitted or the empty string (`""`) is specified, meows uses the default secret name (`meows-github-cred`).
 	// +optional
 	CredentialSecretName string `json:"credentialSecretName,omitempty"`
 
+	// SharedCredentialName is the name of a shared credential configured in the controller.
+	// The shared credential is stored in the controller's namespace or in the controller's files,
+	// and can be used only from the namespaces permitted by the controller's configuration.
+	// This field cannot be set with credentialSecretName.
+	// +optional
+	SharedCredentialName string `json:"sharedCredentialName,omitempty"`
+
+	// CredentialProvider specifies the backend to read the GitHub credential from instead of a Secret.
+	// This field cannot be set with credentialSecretName or sharedCredentialName.
+	// +optional
+	CredentialProvider *CredentialProviderSpec `json:"credentialProvider,omitempty"`
+
+	// GitHubURL is the URL of the GitHub server such as a GitHub Enterprise Server (e.g. `https://github.example.com`).
+	// If this field is omitted, meows uses GitHub.com (`https://github.com`).
+	// +optional
+	GitHubURL string `json:"githubURL,omitempty"`
+
+	// Additional labels of the runners (e.g. `large`, `ubuntu-22.04`).
+	// The runners always have the label `<namespace>/<name>` of the RunnerPool in addition to these labels.
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// Name of the runner group to register the runners in. Defaults to the Default runner group.
+	// This field can be specified only for organization-level runners.
+	// +optional
+	RunnerGroup string `json:"runnerGroup,omitempty"`
+
+	// CreateRunnerGroup is a flag to create the runner group if it does not exist in the organization.
+	// The created runner group is not available for any repositories until the administrators of the organization select them.
+	// +optional
+	CreateRunnerGroup bool `json:"createRunnerGroup,omitempty"`
+
+	// JITConfig is a flag to register each runner with a just-in-time runner configuration.
+	// If this field is true, the controller registers a runner for each pod and delivers the configuration only to the pod,
+	// instead of sharing a registration token among the pods of the RunnerPool.
+	// +optional
+	JITConfig bool `json:"jitConfig,omitempty"`
+
 	// Number of desired runner pods to accept a new job. Defaults to 1.
 	// +kubebuilder:default=1
 	// +optional
@@ -49,15 +98,43 @@ type RunnerPoolSpec struct {
 	// +optional
 	MaxRunnerPods int32 `json:"maxRunnerPods,omitempty"`
 
+	// Configuration of the autoscaling.
+	// +optional
+	Autoscaling AutoscalingConfig `json:"autoscaling,omitempty"`
+
+	// Schedules to change the number of runner pods to accept a new job.
+	// The schedule started most recently is active, and its replicas overrides replicas,
+	// or autoscaling.minReplicas if the autoscaling is enabled.
+	// +optional
+	Schedules []ScheduleConfig `json:"schedules,omitempty"`
+
 	// WorkVolume is the volume source for the working directory.
 	// If pod is not given a volume definition, it uses an empty dir.
 	// +optional
 	WorkVolume *corev1.VolumeSource `json:"workVolume,omitempty"`
 
+	// WorkVolumeClaimTemplate is the template of the PersistentVolumeClaim for the working directory.
+	// A PersistentVolumeClaim is created for each runner pod, and deleted with the pod after the job is completed.
+	// This field cannot be set with workVolume.
+	// If containerMode is `kubernetes` and this field is omitted, meows uses a ReadWriteOnce claim of 10Gi with the default storage class.
+	// +optional
+	WorkVolumeClaimTemplate *WorkVolumeClaimTemplate `json:"workVolumeClaimTemplate,omitempty"`
+
+	// Caches are the volumes shared among the runner pods to keep the caches of the jobs (e.g. Go modules, npm packages),
+	// so that the repeated jobs start with the warm caches.
+	// +optional
+	Caches []CacheConfig `json:"caches,omitempty"`
+
 	// Command that runs when the runner pods will be created.
 	// +optional
 	SetupCommand []string `json:"setupCommand,omitempty"`
 
+	// Number of times to re-launch the runner listener exited with retryable errors. Defaults to 10.
+	// The listener is re-launched with an exponential backoff, and the runner pod fails and is recreated after the max retries.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxListenerRetries *int32 `json:"maxListenerRetries,omitempty"`
+
 	// Deadline for the Pod to be recreated.
 	// +kubebuilder:default="24h"
 	// +optional
@@ -71,11 +148,168 @@ type RunnerPoolSpec struct {
 	// +optional
 	Template RunnerPodTemplateSpec `json:"template,omitempty"`
 
+	// ContainerMode is the mode to run the containers and build the images in the jobs.
+	// `dind` injects a Docker daemon sidecar, and `rootless-buildkit` injects a rootless BuildKit daemon sidecar.
+	// `kubernetes` runs the job containers as separate pods with the runner container hooks.
+	// `none` injects nothing.
+	// +kubebuilder:validation:Enum=none;dind;rootless-buildkit;kubernetes
+	// +kubebuilder:default=none
+	// +optional
+	ContainerMode string `json:"containerMode,omitempty"`
+
+	// Sidecar is the configuration of the sidecar container injected by containerMode.
+	// +optional
+	Sidecar SidecarSpec `json:"sidecar,omitempty"`
+
 	// DenyDisruption protects busy runner Pods by PDB.
 	// +optional
 	DenyDisruption bool `json:"denyDisruption,omitempty"`
 }
 
+// Modes to run the containers in the jobs.
+const (
+	ContainerModeNone             = "none"
+	ContainerModeDind             = "dind"
+	ContainerModeRootlessBuildkit = "rootless-buildkit"
+	ContainerModeKubernetes       = "kubernetes"
+)
+
+type SidecarSpec struct {
+	// Image of the sidecar container.
+	// If this field is omitted, meows uses the default image of the container mode.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Image pull policy for the sidecar container.
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// Compute Resources required by the sidecar container.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+type WorkVolumeClaimTemplate struct {
+	// Standard object's metadata. Only `annotations` and `labels` are valid.
+	// +optional
+	ObjectMeta ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec of the PersistentVolumeClaim.
+	Spec corev1.PersistentVolumeClaimSpec `json:"spec"`
+}
+
+type CacheConfig struct {
+	// Name of the cache. The volume of the cache is named `cache-<name>`.
+	// +kubebuilder:validation:MaxLength=40
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// Path within the runner container at which the cache should be mounted.
+	MountPath string `json:"mountPath"`
+
+	// HostPath is the directory on the node to store the cache.
+	// The cache is shared only among the runner pods on the same node.
+	// +optional
+	HostPath string `json:"hostPath,omitempty"`
+
+	// ClaimName is the name of an existing PersistentVolumeClaim in the same namespace to store the cache.
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+
+	// VolumeClaimSpec is the spec of the PersistentVolumeClaim to store the cache.
+	// The controller creates the PersistentVolumeClaim `<RunnerPool name>-cache-<name>`, which is deleted with the RunnerPool.
+	// The access modes should contain `ReadWriteMany` because the claim is shared among the runner pods.
+	// +optional
+	VolumeClaimSpec *corev1.PersistentVolumeClaimSpec `json:"volumeClaimSpec,omitempty"`
+
+	// KeyByRepository is a flag to keep the cache for each repository.
+	// If this field is true, the cache of the repository running the job is linked to mountPath by the `job-started` command.
+	// +optional
+	KeyByRepository bool `json:"keyByRepository,omitempty"`
+
+	// SizeLimit is the size of the cache to start the eviction.
+	// If this field is set, the controller runs a CronJob which evicts the least recently used entries of the cache hourly.
+	// This field cannot be set with hostPath.
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+}
+
+// Types of the credential providers.
+const (
+	CredentialProviderFile  = "file"
+	CredentialProviderVault = "vault"
+)
+
+type CredentialProviderSpec struct {
+	// Type of the credential provider.
+	// `file` reads the files in the directory `<credential-dir>/<namespace>/<path>` of the controller.
+	// `vault` reads the KV secret `<vault-path-prefix>/<namespace>/<path>` from the HashiCorp Vault-compatible server configured in the controller.
+	// The files and the KV secret have the same keys as the credential secret.
+	// +kubebuilder:validation:Enum=file;vault
+	Type string `json:"type"`
+
+	// Path of the credential relative to the location of the RunnerPool's namespace.
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+}
+
+type AutoscalingConfig struct {
+	// Flag to toggle the autoscaling.
+	// If this field is true, the number of runner pods to accept a new job is scaled between minReplicas and maxRunnerPods
+	// according to the number of queued jobs, and replicas is ignored.
+	// +optional
+	Enable bool `json:"enable,omitempty"`
+
+	// Minimum number of runner pods to accept a new job. Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReplicas int32 `json:"minReplicas,omitempty"`
+
+	// Polling is a flag to count the queued jobs by polling the GitHub Actions API instead of receiving the webhook events.
+	// Use this for the clusters which cannot receive webhooks from GitHub. This field cannot be true for enterprise-level runners.
+	// For organization-level runners, only the repositories pushed within pollingWindow are polled,
+	// up to pollingRepositoryLimit of them in the order of the latest push.
+	// +optional
+	Polling bool `json:"polling,omitempty"`
+
+	// Maximum number of the repositories polled for organization-level runners. Defaults to 30.
+	// Each polled repository costs a few requests of the GitHub API rate limit in every polling.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=30
+	// +optional
+	PollingRepositoryLimit int32 `json:"pollingRepositoryLimit,omitempty"`
+
+	// Duration to look back on the pushes of the repositories polled for organization-level runners. Defaults to 24h.
+	// This value should be parseable with time.ParseDuration.
+	// +kubebuilder:default="24h"
+	// +optional
+	PollingWindow string `json:"pollingWindow,omitempty"`
+
+	// Duration to look back on the past decisions before scaling down.
+	// The runner pods are scaled down to the highest number decided within this window. Defaults to 5m.
+	// This value should be parseable with time.ParseDuration.
+	// +kubebuilder:default="5m"
+	// +optional
+	ScaleDownStabilizationWindow string `json:"scaleDownStabilizationWindow,omitempty"`
+}
+
+type ScheduleConfig struct {
+	// Name of the schedule.
+	Name string `json:"name"`
+
+	// Cron expression when the schedule starts, in the standard format (e.g. "0 9 * * 1-5").
+	// Descriptors such as "@daily" and "CRON_TZ=" prefixes are not allowed; use timeZone instead.
+	Cron string `json:"cron"`
+
+	// Time zone name of the cron expression (e.g. "Asia/Tokyo"). Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Number of desired runner pods to accept a new job while the schedule is active.
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas"`
+}
+
 type NotificationConfig struct {
 	// Configuration of the Slack notification.
 	// +optional
@@ -137,6 +371,55 @@ type RunnerPodTemplateSpec struct {
 	// If specified, the runner pod's tolerations.
 	// +optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// List of initialization containers run before the runner container.
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// List of additional containers run with the runner container, such as a Docker daemon sidecar.
+	// The containers cannot be named `runner`.
+	// They can mount the volume `work-dir` to share the working directory with the runner container.
+	// +optional
+	Containers []corev1.Container `json:"containers,omitempty"`
+
+	// If specified, the runner pod's scheduling constraints.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// TopologySpreadConstraints describes how the runner pods ought to spread across topology domains.
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// If specified, indicates the runner pod's priority.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// RuntimeClassName refers to a RuntimeClass object which should be used to run the runner pod.
+	// +optional
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// HostAliases is a list of hosts and IPs that will be injected into the runner pod's hosts file.
+	// +optional
+	HostAliases []corev1.HostAlias `json:"hostAliases,omitempty"`
+
+	// DNS policy for the runner pod. Defaults to `ClusterFirst`.
+	// +optional
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
+
+	// DNS parameters of the runner pod.
+	// +optional
+	DNSConfig *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+
+	// Pod-level security attributes of the runner pod.
+	// +optional
+	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
+
+	// TerminationGracePeriodSeconds is the duration for the runner pod to terminate gracefully, e.g. on eviction or node drain.
+	// The runner pod stops taking a new job, and waits for the running job to finish within this duration.
+	// Defaults to 30 seconds.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
 }
 
 type RunnerContainerSpec struct {
@@ -180,15 +463,77 @@ type ObjectMeta struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
+// Condition types of RunnerPool.
+const (
+	// ConditionCredentialReady indicates whether the GitHub credential is loaded.
+	ConditionCredentialReady = "CredentialReady"
+
+	// ConditionRegistrationTokenReady indicates whether the registration token for the runners is issued.
+	ConditionRegistrationTokenReady = "RegistrationTokenReady"
+
+	// ConditionDeploymentAvailable indicates whether the Deployment of the runner pods is available.
+	ConditionDeploymentAvailable = "DeploymentAvailable"
+
+	// ConditionGitHubReachable indicates whether the runners can be listed from GitHub.
+	ConditionGitHubReachable = "GitHubReachable"
+
+	// ConditionRunnerGroupReady indicates whether the runner group exists in the organization.
+	// This condition is set only when the runner group is specified.
+	ConditionRunnerGroupReady = "RunnerGroupReady"
+
+	// ConditionQueuedJobsPolled indicates whether all the repositories pushed within the polling window are polled.
+	// This condition is set only when the queued jobs of an organization are polled.
+	ConditionQueuedJobsPolled = "QueuedJobsPolled"
+)
+
 // RunnerPoolStatus defines status of RunnerPool
 type RunnerPoolStatus struct {
 	// Bound is true when the child Deployment is created.
 	// +optional
 	Bound bool `json:"bound,omitempty"`
+
+	// DesiredReplicas is the number of runner pods to accept a new job decided by the autoscaling.
+	// +optional
+	DesiredReplicas *int32 `json:"desiredReplicas,omitempty"`
+
+	// ActiveSchedule is the name of the active schedule.
+	// +optional
+	ActiveSchedule string `json:"activeSchedule,omitempty"`
+
+	// Conditions represent the latest available observations of the RunnerPool.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// OnlineRunners is the number of the online runners including the busy ones.
+	// +optional
+	OnlineRunners int32 `json:"onlineRunners"`
+
+	// BusyRunners is the number of the runners running a job.
+	// +optional
+	BusyRunners int32 `json:"busyRunners"`
+
+	// OfflineRunners is the number of the offline runners.
+	// +optional
+	OfflineRunners int32 `json:"offlineRunners"`
+
+	// DebuggingRunners is the number of the runner pods kept for debugging after a job.
+	// +optional
+	DebuggingRunners int32 `json:"debuggingRunners"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Online",type=integer,JSONPath=`.status.onlineRunners`
+//+kubebuilder:printcolumn:name="Busy",type=integer,JSONPath=`.status.busyRunners`
+//+kubebuilder:printcolumn:name="Offline",type=integer,JSONPath=`.status.offlineRunners`
+//+kubebuilder:printcolumn:name="Debugging",type=integer,JSONPath=`.status.debuggingRunners`
+//+kubebuilder:printcolumn:name="Credential",type=string,JSONPath=`.status.conditions[?(@.type=="CredentialReady")].status`
+//+kubebuilder:printcolumn:name="Token",type=string,JSONPath=`.status.conditions[?(@.type=="RegistrationTokenReady")].status`
+//+kubebuilder:printcolumn:name="Deployment",type=string,JSONPath=`.status.conditions[?(@.type=="DeploymentAvailable")].status`
+//+kubebuilder:printcolumn:name="GitHub",type=string,JSONPath=`.status.conditions[?(@.type=="GitHubReachable")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // RunnerPool is the Schema for the runnerpools API
 type RunnerPool struct {
@@ -230,6 +575,16 @@ func (s *RunnerPoolSpec) validateUpdate(old RunnerPoolSpec) field.ErrorList {
 		allErrs = append(allErrs, field.Forbidden(pp, "the field is immutable"))
 	}
 
+	if s.Enterprise != old.Enterprise {
+		pp := p.Child("enterprise")
+		allErrs = append(allErrs, field.Forbidden(pp, "the field is immutable"))
+	}
+
+	if s.GitHubURL != old.GitHubURL {
+		pp := p.Child("githubURL")
+		allErrs = append(allErrs, field.Forbidden(pp, "the field is immutable"))
+	}
+
 	return append(allErrs, s.validateCommon()...)
 }
 
@@ -237,8 +592,14 @@ func (s *RunnerPoolSpec) validateCommon() field.ErrorList {
 	var allErrs field.ErrorList
 	p := field.NewPath("spec")
 
-	if (s.Repository == "" && s.Organization == "") || (s.Repository != "" && s.Organization != "") {
-		allErrs = append(allErrs, field.Invalid(p, s.Repository, "only one of repository and organization can be set"))
+	var scopes int
+	for _, v := range []string{s.Repository, s.Organization, s.Enterprise} {
+		if v != "" {
+			scopes++
+		}
+	}
+	if scopes != 1 {
+		allErrs = append(allErrs, field.Invalid(p, s.Repository, "only one of repository, organization and enterprise can be set"))
 	}
 	if s.Repository != "" {
 		split := strings.Split(s.Repository, "/")
@@ -247,10 +608,168 @@ func (s *RunnerPoolSpec) validateCommon() field.ErrorList {
 		}
 	}
 
+	if s.CredentialSecretName != "" && s.SharedCredentialName != "" {
+		allErrs = append(allErrs, field.Invalid(p.Child("sharedCredentialName"), s.SharedCredentialName, "this value cannot be set with credentialSecretName"))
+	}
+	if s.CredentialProvider != nil {
+		pp := p.Child("credentialProvider")
+		if s.CredentialSecretName != "" || s.SharedCredentialName != "" {
+			allErrs = append(allErrs, field.Invalid(pp, s.CredentialProvider, "this value cannot be set with credentialSecretName or sharedCredentialName"))
+		}
+		cleaned := path.Clean(s.CredentialProvider.Path)
+		if path.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			allErrs = append(allErrs, field.Invalid(pp.Child("path"), s.CredentialProvider.Path, "this value should be a relative path in the location of the namespace"))
+		}
+	}
+
+	if s.WorkVolumeClaimTemplate != nil {
+		pp := p.Child("workVolumeClaimTemplate")
+		if s.WorkVolume != nil {
+			allErrs = append(allErrs, field.Invalid(pp, s.WorkVolumeClaimTemplate, "this value cannot be set with workVolume"))
+		}
+		if len(s.WorkVolumeClaimTemplate.Spec.AccessModes) == 0 {
+			allErrs = append(allErrs, field.Required(pp.Child("spec").Child("accessModes"), "this value should not be empty"))
+		}
+		if _, ok := s.WorkVolumeClaimTemplate.Spec.Resources.Requests[corev1.ResourceStorage]; !ok {
+			allErrs = append(allErrs, field.Required(pp.Child("spec").Child("resources").Child("requests").Child("storage"), "this value should be set"))
+		}
+	}
+
+	cacheNames := map[string]bool{}
+	for i, c := range s.Caches {
+		pp := p.Child("caches").Index(i)
+		if cacheNames[c.Name] {
+			allErrs = append(allErrs, field.Duplicate(pp.Child("name"), c.Name))
+		}
+		cacheNames[c.Name] = true
+		if !path.IsAbs(c.MountPath) {
+			allErrs = append(allErrs, field.Invalid(pp.Child("mountPath"), c.MountPath, "this value should be an absolute path"))
+		}
+
+		var sources int
+		if c.HostPath != "" {
+			sources++
+			if !path.IsAbs(c.HostPath) {
+				allErrs = append(allErrs, field.Invalid(pp.Child("hostPath"), c.HostPath, "this value should be an absolute path"))
+			}
+		}
+		if c.ClaimName != "" {
+			sources++
+		}
+		if c.VolumeClaimSpec != nil {
+			sources++
+			if !slices.Contains(c.VolumeClaimSpec.AccessModes, corev1.ReadWriteMany) {
+				allErrs = append(allErrs, field.Invalid(pp.Child("volumeClaimSpec").Child("accessModes"), c.VolumeClaimSpec.AccessModes, "this value should contain ReadWriteMany"))
+			}
+			if _, ok := c.VolumeClaimSpec.Resources.Requests[corev1.ResourceStorage]; !ok {
+				allErrs = append(allErrs, field.Required(pp.Child("volumeClaimSpec").Child("resources").Child("requests").Child("storage"), "this value should be set"))
+			}
+		}
+		if sources != 1 {
+			allErrs = append(allErrs, field.Invalid(pp, c.Name, "only one of hostPath, claimName and volumeClaimSpec can be set"))
+		}
+
+		if c.SizeLimit != nil {
+			if c.HostPath != "" {
+				allErrs = append(allErrs, field.Invalid(pp.Child("sizeLimit"), c.SizeLimit.String(), "this value cannot be set with hostPath"))
+			}
+			if c.SizeLimit.Sign() <= 0 {
+				allErrs = append(allErrs, field.Invalid(pp.Child("sizeLimit"), c.SizeLimit.String(), "this value should be greater than 0"))
+			}
+		}
+	}
+
+	if s.ContainerMode == ContainerModeKubernetes {
+		pp := p.Child("containerMode")
+		if s.WorkVolume != nil {
+			allErrs = append(allErrs, field.Invalid(pp, s.ContainerMode, "this value cannot be set with workVolume because the job pods mount the PersistentVolumeClaim of the working directory"))
+		}
+		if s.Template.ServiceAccountName != "" && s.Template.ServiceAccountName != "default" {
+			allErrs = append(allErrs, field.Invalid(pp, s.ContainerMode, "this value cannot be set with template.serviceAccountName because the controller creates the service account"))
+		}
+		if s.Template.AutomountServiceAccountToken != nil && !*s.Template.AutomountServiceAccountToken {
+			allErrs = append(allErrs, field.Invalid(pp, s.ContainerMode, "this value cannot be set with template.automountServiceAccountToken false because the runner creates the job pods"))
+		}
+		if s.CredentialSecretName != "" {
+			allErrs = append(allErrs, field.Invalid(pp, s.ContainerMode, "this value cannot be set with credentialSecretName because the job pods can mount the secrets in the namespace"))
+		}
+	}
+
+	if s.RunnerGroup != "" && s.Organization == "" {
+		allErrs = append(allErrs, field.Invalid(p.Child("runnerGroup"), s.RunnerGroup, "this value can be set only for organization-level runners"))
+	}
+	if s.Autoscaling.Polling && s.Enterprise != "" {
+		allErrs = append(allErrs, field.Invalid(p.Child("autoscaling").Child("polling"), s.Autoscaling.Polling, "this value cannot be true for enterprise-level runners"))
+	}
+	if s.CreateRunnerGroup && s.RunnerGroup == "" {
+		allErrs = append(allErrs, field.Required(p.Child("runnerGroup"), "this value should be set when createRunnerGroup is true"))
+	}
+
+	labels := map[string]bool{}
+	for i, l := range s.Labels {
+		pp := p.Child("labels").Index(i)
+		switch {
+		case l == "":
+			allErrs = append(allErrs, field.Required(pp, "this value should not be empty"))
+		case strings.ContainsAny(l, ",/"):
+			allErrs = append(allErrs, field.Invalid(pp, l, "this value should not contain ',' or '/'"))
+		case labels[strings.ToLower(l)]:
+			allErrs = append(allErrs, field.Duplicate(pp, l))
+		}
+		labels[strings.ToLower(l)] = true
+	}
+
+	if s.GitHubURL != "" {
+		u, err := url.Parse(s.GitHubURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(p.Child("githubURL"), s.GitHubURL, "this value should be an absolute URL of http or https"))
+		}
+	}
+
 	if !(s.MaxRunnerPods == 0 || s.Replicas <= s.MaxRunnerPods) {
 		allErrs = append(allErrs, field.Invalid(p.Child("maxRunnerPods"), s.MaxRunnerPods, "this value should be 0, or greater-than or equal-to replicas."))
 	}
 
+	if s.Autoscaling.Enable {
+		if s.MaxRunnerPods == 0 {
+			allErrs = append(allErrs, field.Invalid(p.Child("maxRunnerPods"), s.MaxRunnerPods, "this value should be greater than 0 when autoscaling is enabled."))
+		} else if s.Autoscaling.MinReplicas > s.MaxRunnerPods {
+			allErrs = append(allErrs, field.Invalid(p.Child("autoscaling").Child("minReplicas"), s.Autoscaling.MinReplicas, "this value should be less-than or equal-to maxRunnerPods."))
+		}
+		if s.Autoscaling.ScaleDownStabilizationWindow != "" {
+			_, err := time.ParseDuration(s.Autoscaling.ScaleDownStabilizationWindow)
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(p.Child("autoscaling").Child("scaleDownStabilizationWindow"), s.Autoscaling.ScaleDownStabilizationWindow, "this value should be able to parse using time.ParseDuration"))
+			}
+		}
+		if s.Autoscaling.PollingWindow != "" {
+			_, err := time.ParseDuration(s.Autoscaling.PollingWindow)
+			if err != nil {
+				allErrs = append(allErrs, field.Invalid(p.Child("autoscaling").Child("pollingWindow"), s.Autoscaling.PollingWindow, "this value should be able to parse using time.ParseDuration"))
+			}
+		}
+	}
+
+	names := map[string]bool{}
+	for i, sc := range s.Schedules {
+		pp := p.Child("schedules").Index(i)
+		if sc.Name == "" {
+			allErrs = append(allErrs, field.Required(pp.Child("name"), "this value should not be empty"))
+		} else if names[sc.Name] {
+			allErrs = append(allErrs, field.Duplicate(pp.Child("name"), sc.Name))
+		}
+		names[sc.Name] = true
+		if _, err := sc.ParseCron(); err != nil {
+			allErrs = append(allErrs, field.Invalid(pp.Child("cron"), sc.Cron, "this value should be a standard cron expression"))
+		}
+		if _, err := time.LoadLocation(sc.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(pp.Child("timeZone"), sc.TimeZone, "this value should be a time zone name"))
+		}
+		if s.MaxRunnerPods != 0 && sc.Replicas > s.MaxRunnerPods {
+			allErrs = append(allErrs, field.Invalid(pp.Child("replicas"), sc.Replicas, "this value should be less-than or equal-to maxRunnerPods."))
+		}
+	}
+
 	_, err := time.ParseDuration(s.RecreateDeadline)
 	if err != nil {
 		allErrs = append(allErrs, field.Invalid(p.Child("recreateDeadline"), s.RecreateDeadline, "this value should be able to parse using time.ParseDuration"))
@@ -263,6 +782,53 @@ func (s *RunnerPoolSpec) validateCommon() field.ErrorList {
 		}
 	}
 
+	reservedVolumes := map[string]bool{
+		constants.VarDirVolumeName:  true,
+		constants.WorkDirVolumeName: true,
+	}
+	for _, c := range s.Caches {
+		reservedVolumes["cache-"+c.Name] = true
+	}
+	reservedContainers := map[string]bool{
+		constants.RunnerContainerName: true,
+	}
+	switch s.ContainerMode {
+	case ContainerModeDind:
+		reservedVolumes[constants.DockerCertsVolumeName] = true
+		reservedVolumes[constants.DockerDataVolumeName] = true
+		reservedContainers[constants.DockerContainerName] = true
+	case ContainerModeRootlessBuildkit:
+		reservedVolumes[constants.BuildkitSocketVolumeName] = true
+		reservedVolumes[constants.BuildkitDataVolumeName] = true
+		reservedContainers[constants.BuildkitContainerName] = true
+	}
+	for i, v := range s.Template.Volumes {
+		if reservedVolumes[v.Name] {
+			allErrs = append(allErrs, field.Forbidden(p.Child("template").Child("volumes").Index(i).Child("name"),
+				fmt.Sprintf("using the volume name %s reserved by meows is forbidden", v.Name)))
+		}
+	}
+
+	containerNames := map[string]bool{}
+	for _, cs := range []struct {
+		name       string
+		containers []corev1.Container
+	}{
+		{name: "initContainers", containers: s.Template.InitContainers},
+		{name: "containers", containers: s.Template.Containers},
+	} {
+		for i, c := range cs.containers {
+			pp := p.Child("template").Child(cs.name).Index(i).Child("name")
+			switch {
+			case reservedContainers[c.Name]:
+				allErrs = append(allErrs, field.Forbidden(pp, fmt.Sprintf("using the container name %s reserved by meows is forbidden", c.Name)))
+			case containerNames[c.Name]:
+				allErrs = append(allErrs, field.Duplicate(pp, c.Name))
+			}
+			containerNames[c.Name] = true
+		}
+	}
+
 	for i, e := range s.Template.RunnerContainer.Env {
 		if reservedEnvNames[e.Name] {
 			allErrs = append(allErrs, field.Forbidden(p.Child("template").Child("runnerContainer").Child("env").Index(i),
@@ -282,11 +848,48 @@ func (r *RunnerPool) GetRunnerSecretName() string {
 	return "runner-token-" + r.Name
 }
 
+// GetAPITokenSecretName returns the Secret name for the token to authenticate the requests to the runner pods.
+func (r *RunnerPool) GetAPITokenSecretName() string {
+	return "runner-api-token-" + r.Name
+}
+
+// GetCacheClaimName returns the PersistentVolumeClaim name for the cache created by the controller.
+func (r *RunnerPool) GetCacheClaimName(cacheName string) string {
+	return r.Name + "-cache-" + cacheName
+}
+
+// GetCacheEvictionCronJobName returns the CronJob name to evict the caches.
+func (r *RunnerPool) GetCacheEvictionCronJobName() string {
+	return r.Name + "-cache-eviction"
+}
+
+// GetContainerHooksServiceAccountName returns the name of the ServiceAccount, the Role and the RoleBinding
+// for the runner container hooks to create the job pods.
+func (r *RunnerPool) GetContainerHooksServiceAccountName() string {
+	return r.Name + "-container-hooks"
+}
+
+func (r *RunnerPool) GetGitHubURL() string {
+	if r.Spec.GitHubURL == "" {
+		return constants.DefaultGitHubURL
+	}
+	return strings.TrimSuffix(r.Spec.GitHubURL, "/")
+}
+
 func (r *RunnerPool) IsOrgLevel() bool {
 	return r.Spec.Organization != ""
 }
 
+func (r *RunnerPool) IsEnterpriseLevel() bool {
+	return r.Spec.Enterprise != ""
+}
+
+// GetOwner returns the owner of the repository, or the organization.
+// It returns the empty string for enterprise-level runners.
 func (r *RunnerPool) GetOwner() string {
+	if r.IsEnterpriseLevel() {
+		return ""
+	}
 	if r.IsOrgLevel() {
 		return r.Spec.Organization
 	}
@@ -295,9 +898,21 @@ func (r *RunnerPool) GetOwner() string {
 }
 
 func (r *RunnerPool) GetRepository() string {
-	if r.IsOrgLevel() {
+	if r.IsOrgLevel() || r.IsEnterpriseLevel() {
 		return ""
 	}
 	split := strings.Split(r.Spec.Repository, "/")
 	return split[1]
 }
+
+// cronParser accepts only the five fields of the standard cron expression.
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// ParseCron parses the cron expression of the schedule.
+// The time zone should be specified by TimeZone, so the "TZ=" and "CRON_TZ=" prefixes are rejected.
+func (sc *ScheduleConfig) ParseCron() (cron.Schedule, error) {
+	if strings.HasPrefix(sc.Cron, "TZ=") || strings.HasPrefix(sc.Cron, "CRON_TZ=") {
+		return nil, fmt.Errorf("time zone prefix is not allowed in cron expression: %s", sc.Cron)
+	}
+	return cronParser.Parse(sc.Cron)
+}
@@ -8,8 +8,10 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -84,6 +86,12 @@ var _ = Describe("validate RunnerPool webhook with ", func() {
 		Expect(rp.Spec.Template.ServiceAccountName).To(Equal("default"))
 	})
 
+	It("should allow creating RunnerPool with Enterprise", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Enterprise = "test-enterprise"
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+	})
+
 	It("should deny creating RunnerPool with neither Repository nor Organization", func() {
 		rp := makeRunnerPoolTemplate(name, namespace)
 		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
@@ -96,6 +104,18 @@ var _ = Describe("validate RunnerPool webhook with ", func() {
 		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
 	})
 
+	It("should deny creating RunnerPool with Enterprise and another scope", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Enterprise = "test-enterprise"
+		rp.Spec.Organization = "test-org"
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+
+		rp = makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Enterprise = "test-enterprise"
+		rp.Spec.Repository = "test-org/test-repo"
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+	})
+
 	It("should deny updating RunnerPool if Repository is changed", func() {
 		rp := makeRunnerPoolTemplate(name, namespace)
 		rp.Spec.Repository = "test-org/test-repo1"
@@ -114,6 +134,83 @@ var _ = Describe("validate RunnerPool webhook with ", func() {
 		Expect(k8sClient.Update(ctx, rp)).NotTo(Succeed())
 	})
 
+	It("should deny updating RunnerPool if Enterprise is changed", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Enterprise = "test-enterprise1"
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		rp.Spec.Enterprise = "test-enterprise2"
+		Expect(k8sClient.Update(ctx, rp)).NotTo(Succeed())
+	})
+
+	It("should deny creating enterprise-level RunnerPool with polling autoscaling", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Enterprise = "test-enterprise"
+		rp.Spec.MaxRunnerPods = 3
+		rp.Spec.Autoscaling.Enable = true
+		rp.Spec.Autoscaling.Polling = true
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+	})
+
+	It("should deny creating RunnerPool with both credential secret and shared credential", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Organization = "test-org"
+		rp.Spec.CredentialSecretName = "github-cred"
+		rp.Spec.SharedCredentialName = "shared-cred"
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+	})
+
+	It("should allow creating RunnerPool with credential provider", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Organization = "test-org"
+		rp.Spec.CredentialProvider = &CredentialProviderSpec{Type: "vault", Path: "github/app"}
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+	})
+
+	It("should deny creating RunnerPool with invalid credential provider", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Organization = "test-org"
+		rp.Spec.CredentialSecretName = "github-cred"
+		rp.Spec.CredentialProvider = &CredentialProviderSpec{Type: "file", Path: "github-app"}
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+
+		for _, p := range []string{"/etc/github-app", "..", "../other-ns/github-app", "github-app/../../other-ns", "."} {
+			rp := makeRunnerPoolTemplate(name, namespace)
+			rp.Spec.Organization = "test-org"
+			rp.Spec.CredentialProvider = &CredentialProviderSpec{Type: "file", Path: p}
+			Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed(), "path: %s", p)
+		}
+
+		rp = makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Organization = "test-org"
+		rp.Spec.CredentialProvider = &CredentialProviderSpec{Type: "unknown", Path: "github-app"}
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+	})
+
+	It("should deny updating RunnerPool if GitHubURL is changed", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Organization = "test-org"
+		rp.Spec.GitHubURL = "https://github.example.com"
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		rp.Spec.GitHubURL = "https://github2.example.com"
+		Expect(k8sClient.Update(ctx, rp)).NotTo(Succeed())
+	})
+
+	It("should deny creating RunnerPool with invalid GitHubURL", func() {
+		testCases := []string{
+			"github.example.com",
+			"ftp://github.example.com",
+			"https://",
+		}
+		for _, tc := range testCases {
+			rp := makeRunnerPoolTemplate(name, namespace)
+			rp.Spec.Organization = "test-org"
+			rp.Spec.GitHubURL = tc
+			Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed(), "githubURL: %s", tc)
+		}
+	})
+
 	It("should allow creating RunnerPool when Replicas == MaxRunnerPods", func() {
 		rp := makeRunnerPoolTemplate(name, namespace)
 		rp.Spec.Repository = "test-org/test-repo"
@@ -150,14 +247,429 @@ var _ = Describe("validate RunnerPool webhook with ", func() {
 		Expect(k8sClient.Update(ctx, rp)).NotTo(Succeed())
 	})
 
+	It("should allow creating RunnerPool with autoscaling", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.MaxRunnerPods = 3
+		rp.Spec.Autoscaling.Enable = true
+		rp.Spec.Autoscaling.MinReplicas = 3
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+	})
+
+	It("should deny creating RunnerPool with autoscaling when MaxRunnerPods == 0", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.Autoscaling.Enable = true
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+	})
+
+	It("should deny creating RunnerPool with autoscaling when MinReplicas > MaxRunnerPods", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.MaxRunnerPods = 2
+		rp.Spec.Autoscaling.Enable = true
+		rp.Spec.Autoscaling.MinReplicas = 3
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+	})
+
+	It("should deny creating RunnerPool with invalid scale down stabilization window", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.MaxRunnerPods = 2
+		rp.Spec.Autoscaling.Enable = true
+		rp.Spec.Autoscaling.ScaleDownStabilizationWindow = "5"
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+	})
+
+	It("should deny creating RunnerPool with invalid polling window", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Organization = "test-org"
+		rp.Spec.MaxRunnerPods = 2
+		rp.Spec.Autoscaling.Enable = true
+		rp.Spec.Autoscaling.Polling = true
+		rp.Spec.Autoscaling.PollingWindow = "24"
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+	})
+
+	It("should deny creating RunnerPool with negative polling repository limit", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Organization = "test-org"
+		rp.Spec.MaxRunnerPods = 2
+		rp.Spec.Autoscaling.Enable = true
+		rp.Spec.Autoscaling.Polling = true
+		rp.Spec.Autoscaling.PollingRepositoryLimit = -1
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+	})
+
+	It("should allow creating RunnerPool with schedules", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.MaxRunnerPods = 10
+		rp.Spec.Schedules = []ScheduleConfig{
+			{Name: "office-hours", Cron: "0 9 * * 1-5", TimeZone: "Asia/Tokyo", Replicas: 10},
+			{Name: "night", Cron: "0 19 * * 1-5", TimeZone: "Asia/Tokyo", Replicas: 1},
+			{Name: "weekend", Cron: "0 0 * * 6", Replicas: 0},
+		}
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+	})
+
+	It("should deny creating RunnerPool with invalid schedules", func() {
+		testCases := [][]ScheduleConfig{
+			{{Name: "", Cron: "0 9 * * *", Replicas: 1}},
+			{{Name: "a", Cron: "0 9 * * *", Replicas: 1}, {Name: "a", Cron: "0 19 * * *", Replicas: 1}},
+			{{Name: "a", Cron: "0 9 * *", Replicas: 1}},
+			{{Name: "a", Cron: "@daily", Replicas: 1}},
+			{{Name: "a", Cron: "@every 1h", Replicas: 1}},
+			{{Name: "a", Cron: "CRON_TZ=Asia/Tokyo 0 9 * * *", Replicas: 1}},
+			{{Name: "a", Cron: "TZ=Asia/Tokyo 0 9 * * *", Replicas: 1}},
+			{{Name: "a", Cron: "0 9 * * *", TimeZone: "Asia/Nowhere", Replicas: 1}},
+			{{Name: "a", Cron: "0 9 * * *", Replicas: 11}},
+		}
+		for _, tc := range testCases {
+			rp := makeRunnerPoolTemplate(name, namespace)
+			rp.Spec.Repository = "test-org/test-repo"
+			rp.Spec.MaxRunnerPods = 10
+			rp.Spec.Schedules = tc
+			Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed(), "schedules: %v", tc)
+		}
+	})
+
+	It("should allow creating RunnerPool with labels", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.Labels = []string{"large", "ubuntu-22.04"}
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+	})
+
+	It("should deny creating RunnerPool with invalid labels", func() {
+		testCases := [][]string{
+			{""},
+			{"large,gpu"},
+			{"other-ns/other-pool"},
+			{"large", "Large"},
+		}
+		for _, tc := range testCases {
+			rp := makeRunnerPoolTemplate(name, namespace)
+			rp.Spec.Repository = "test-org/test-repo"
+			rp.Spec.Labels = tc
+			Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed(), "labels: %v", tc)
+		}
+	})
+
+	It("should allow creating RunnerPool with work volume claim template", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.WorkVolumeClaimTemplate = &WorkVolumeClaimTemplate{
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("10Gi"),
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+	})
+
+	It("should deny creating RunnerPool with invalid work volume claim template", func() {
+		By("setting with work volume")
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.WorkVolume = &corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+		rp.Spec.WorkVolumeClaimTemplate = &WorkVolumeClaimTemplate{
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("10Gi"),
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+
+		By("setting without access modes")
+		rp = makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.WorkVolumeClaimTemplate = &WorkVolumeClaimTemplate{
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("10Gi"),
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+
+		By("setting without storage request")
+		rp = makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.WorkVolumeClaimTemplate = &WorkVolumeClaimTemplate{
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			},
+		}
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+	})
+
+	It("should allow creating RunnerPool with caches", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.Caches = []CacheConfig{
+			{
+				Name:            "go-mod",
+				MountPath:       "/home/runner/go/pkg/mod",
+				HostPath:        "/var/cache/meows/go-mod",
+				KeyByRepository: true,
+			},
+			{
+				Name:      "npm",
+				MountPath: "/home/runner/.npm",
+				ClaimName: "npm-cache",
+				SizeLimit: ptr.To(resource.MustParse("10Gi")),
+			},
+			{
+				Name:      "ccache",
+				MountPath: "/home/runner/.ccache",
+				VolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse("20Gi"),
+						},
+					},
+				},
+				KeyByRepository: true,
+				SizeLimit:       ptr.To(resource.MustParse("15Gi")),
+			},
+		}
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+	})
+
+	It("should deny creating RunnerPool with invalid caches", func() {
+		rwxSpec := &corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("20Gi"),
+				},
+			},
+		}
+		testCases := map[string][]CacheConfig{
+			"duplicated names": {
+				{Name: "cache", MountPath: "/cache1", ClaimName: "cache1"},
+				{Name: "cache", MountPath: "/cache2", ClaimName: "cache2"},
+			},
+			"invalid name": {
+				{Name: "Cache", MountPath: "/cache", ClaimName: "cache"},
+			},
+			"relative mount path": {
+				{Name: "cache", MountPath: "cache", ClaimName: "cache"},
+			},
+			"no source": {
+				{Name: "cache", MountPath: "/cache"},
+			},
+			"multiple sources": {
+				{Name: "cache", MountPath: "/cache", ClaimName: "cache", HostPath: "/var/cache"},
+			},
+			"relative host path": {
+				{Name: "cache", MountPath: "/cache", HostPath: "var/cache"},
+			},
+			"claim without ReadWriteMany": {
+				{Name: "cache", MountPath: "/cache", VolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources:   rwxSpec.Resources,
+				}},
+			},
+			"claim without storage request": {
+				{Name: "cache", MountPath: "/cache", VolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+					AccessModes: rwxSpec.AccessModes,
+				}},
+			},
+			"size limit with host path": {
+				{Name: "cache", MountPath: "/cache", HostPath: "/var/cache", SizeLimit: ptr.To(resource.MustParse("10Gi"))},
+			},
+			"zero size limit": {
+				{Name: "cache", MountPath: "/cache", VolumeClaimSpec: rwxSpec, SizeLimit: ptr.To(resource.MustParse("0"))},
+			},
+		}
+		for title, tc := range testCases {
+			rp := makeRunnerPoolTemplate(name, namespace)
+			rp.Spec.Repository = "test-org/test-repo"
+			rp.Spec.Caches = tc
+			Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed(), title)
+		}
+	})
+
+	It("should allow creating RunnerPool with additional containers", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.Template.InitContainers = []corev1.Container{
+			{Name: "init", Image: "busybox"},
+		}
+		rp.Spec.Template.Containers = []corev1.Container{
+			{Name: "dind", Image: "docker:dind", VolumeMounts: []corev1.VolumeMount{{Name: "work-dir", MountPath: "/runner/_work"}}},
+		}
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+	})
+
+	It("should deny creating RunnerPool with invalid containers or volumes", func() {
+		By("naming an additional container runner")
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.Template.Containers = []corev1.Container{
+			{Name: "runner", Image: "busybox"},
+		}
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+
+		By("naming an init container runner")
+		rp = makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.Template.InitContainers = []corev1.Container{
+			{Name: "runner", Image: "busybox"},
+		}
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+
+		By("duplicating the container names")
+		rp = makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.Template.InitContainers = []corev1.Container{
+			{Name: "sidecar", Image: "busybox"},
+		}
+		rp.Spec.Template.Containers = []corev1.Container{
+			{Name: "sidecar", Image: "busybox"},
+		}
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+
+		By("using the reserved volume names")
+		for _, volumeName := range []string{"var-dir", "work-dir", "cache-go-mod"} {
+			rp = makeRunnerPoolTemplate(name, namespace)
+			rp.Spec.Repository = "test-org/test-repo"
+			rp.Spec.Caches = []CacheConfig{
+				{Name: "go-mod", MountPath: "/home/runner/go/pkg/mod", ClaimName: "go-mod"},
+			}
+			rp.Spec.Template.Volumes = []corev1.Volume{
+				{Name: volumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			}
+			Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed(), volumeName)
+		}
+	})
+
+	It("should allow creating RunnerPool with container mode", func() {
+		for _, mode := range []string{ContainerModeNone, ContainerModeDind, ContainerModeRootlessBuildkit, ContainerModeKubernetes} {
+			rp := makeRunnerPoolTemplate(name+"-"+mode, namespace)
+			rp.Spec.Repository = "test-org/test-repo"
+			rp.Spec.ContainerMode = mode
+			rp.Spec.Sidecar.Resources = corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("1"),
+				},
+			}
+			Expect(k8sClient.Create(ctx, rp)).To(Succeed(), mode)
+		}
+	})
+
+	It("should deny creating RunnerPool with invalid container mode", func() {
+		By("setting an unknown mode")
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.ContainerMode = "podman"
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+
+		By("using the container names reserved by the mode")
+		for mode, containerName := range map[string]string{
+			ContainerModeDind:             "docker",
+			ContainerModeRootlessBuildkit: "buildkitd",
+		} {
+			rp := makeRunnerPoolTemplate(name, namespace)
+			rp.Spec.Repository = "test-org/test-repo"
+			rp.Spec.ContainerMode = mode
+			rp.Spec.Template.Containers = []corev1.Container{
+				{Name: containerName, Image: "busybox"},
+			}
+			Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed(), mode)
+		}
+
+		By("using the volume names reserved by the mode")
+		for mode, volumeName := range map[string]string{
+			ContainerModeDind:             "docker-certs",
+			ContainerModeRootlessBuildkit: "buildkit-socket",
+		} {
+			rp := makeRunnerPoolTemplate(name, namespace)
+			rp.Spec.Repository = "test-org/test-repo"
+			rp.Spec.ContainerMode = mode
+			rp.Spec.Template.Volumes = []corev1.Volume{
+				{Name: volumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			}
+			Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed(), mode)
+		}
+
+		By("setting the work volume with the kubernetes mode")
+		rp = makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.ContainerMode = ContainerModeKubernetes
+		rp.Spec.WorkVolume = &corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+
+		By("setting the service account with the kubernetes mode")
+		rp = makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.ContainerMode = ContainerModeKubernetes
+		rp.Spec.Template.ServiceAccountName = "test-sa"
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+
+		By("disabling the service account token with the kubernetes mode")
+		rp = makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.ContainerMode = ContainerModeKubernetes
+		rp.Spec.Template.AutomountServiceAccountToken = ptr.To(false)
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+
+		By("setting the credential secret with the kubernetes mode")
+		rp = makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.ContainerMode = ContainerModeKubernetes
+		rp.Spec.CredentialSecretName = "github-cred"
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+	})
+
+	It("should allow creating RunnerPool with runner group", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Organization = "test-org"
+		rp.Spec.RunnerGroup = "test-group"
+		rp.Spec.CreateRunnerGroup = true
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+	})
+
+	It("should deny creating RunnerPool with invalid runner group", func() {
+		rp := makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.RunnerGroup = "test-group"
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+
+		rp = makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Enterprise = "test-enterprise"
+		rp.Spec.RunnerGroup = "test-group"
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+
+		rp = makeRunnerPoolTemplate(name, namespace)
+		rp.Spec.Organization = "test-org"
+		rp.Spec.CreateRunnerGroup = true
+		Expect(k8sClient.Create(ctx, rp)).NotTo(Succeed())
+	})
+
 	It("should deny creating or updating RunnerPool with reserved environment variables", func() {
 		testCases := []string{
 			constants.PodNameEnvName,
 			constants.PodNamespaceEnvName,
 			constants.RunnerOrgEnvName,
 			constants.RunnerRepoEnvName,
+			constants.RunnerEnterpriseEnvName,
 			constants.RunnerPoolNameEnvName,
 			constants.RunnerOptionEnvName,
+			constants.RepositoryCachesEnvName,
 		}
 
 		for _, envName := range testCases {
@@ -16,13 +16,22 @@ const defaultRunnerOs = "ubuntu22.04"
 const defaultRunnerImage = "ghcr.io/cybozu-go/meows-runner:" + defaultRunnerOs + "-meows" + constants.Version
 
 var config struct {
-	zapOpts               zap.Options
-	metricsAddr           string
-	probeAddr             string
-	webhookAddr           string
-	configFile            string
-	runnerImage           string
-	runnerManagerInterval time.Duration
+	zapOpts                 zap.Options
+	metricsAddr             string
+	probeAddr               string
+	webhookAddr             string
+	configFile              string
+	controllerNamespace     string
+	credentialDir           string
+	vaultAddress            string
+	vaultTokenFile          string
+	vaultPathPrefix         string
+	runnerImage             string
+	runnerManagerInterval   time.Duration
+	githubCacheTTL          time.Duration
+	githubWebhookAddr       string
+	githubWebhookSecretFile string
+	runnerAPIKeyFile        string
 }
 
 // rootCmd represents the base command when called without any subcommands
@@ -50,7 +59,16 @@ func init() {
 	fs.StringVar(&config.webhookAddr, "webhook-addr", ":9443", "The address the webhook endpoint binds to")
 	fs.StringVar(&config.runnerImage, "runner-image", defaultRunnerImage, "The image of runner container")
 	fs.StringVar(&config.configFile, "config-file", "", "Path to the controller config file (YAML)")
+	fs.StringVar(&config.controllerNamespace, "controller-namespace", os.Getenv("POD_NAMESPACE"), "The namespace of the Secrets of the shared credentials. The default is the value of POD_NAMESPACE environment variable.")
+	fs.StringVar(&config.credentialDir, "credential-dir", "", "Path to the directory containing the credentials of the RunnerPools using the file credential provider. If empty, the provider is disabled.")
+	fs.StringVar(&config.vaultAddress, "vault-address", "", "The address of the HashiCorp Vault-compatible server for the vault credential provider. If empty, the provider is disabled.")
+	fs.StringVar(&config.vaultTokenFile, "vault-token-file", "", "Path to the file containing the token to access the vault server")
+	fs.StringVar(&config.vaultPathPrefix, "vault-path-prefix", "secret/data/meows", "The path prefix of the KV secrets of the credentials in the vault server")
 	fs.DurationVar(&config.runnerManagerInterval, "runner-manager-interval", time.Minute, "Interval to watch and delete Pods.")
+	fs.DurationVar(&config.githubCacheTTL, "github-cache-ttl", 30*time.Second, "Duration to share the listings of runners and queued jobs among RunnerPools in the same scope. If 0, the listings are not cached.")
+	fs.StringVar(&config.githubWebhookAddr, "github-webhook-addr", "", "The address the GitHub webhook endpoint binds to. If empty, the endpoint is disabled.")
+	fs.StringVar(&config.githubWebhookSecretFile, "github-webhook-secret-file", "", "Path to the file containing the secret of the GitHub webhook")
+	fs.StringVar(&config.runnerAPIKeyFile, "runner-api-key-file", "", "Path to the file containing the key to derive the tokens to authenticate to the runner pods. If empty, the runner pods accept the requests without the tokens.")
 
 	goflags := flag.NewFlagSet("klog", flag.ExitOnError)
 	klog.InitFlags(goflags)
@@ -1,12 +1,16 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
 
+	constants "github.com/cybozu-go/meows"
 	meowsv1alpha1 "github.com/cybozu-go/meows/api/v1alpha1"
 	"github.com/cybozu-go/meows/controllers"
 	"github.com/cybozu-go/meows/github"
@@ -19,6 +23,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	k8sMetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -69,14 +74,37 @@ func run() error {
 	metrics.InitControllerMetrics(k8sMetrics.Registry)
 
 	log := ctrl.Log.WithName("controllers")
+	recorder := mgr.GetEventRecorder("meows-controller")
 	factory := github.NewFactory()
+	if config.githubCacheTTL > 0 {
+		factory = github.NewCachedFactory(factory, config.githubCacheTTL)
+	}
+	jobQueue := controllers.NewJobQueue()
+
+	if config.githubWebhookAddr != "" {
+		if err := addGitHubWebhookServer(mgr, jobQueue); err != nil {
+			setupLog.Error(err, "unable to set up github webhook server")
+			return err
+		}
+	}
+
+	var runnerAPIKey []byte
+	if config.runnerAPIKeyFile != "" {
+		runnerAPIKey, err = runner.ReadAPIKey(config.runnerAPIKeyFile)
+		if err != nil {
+			setupLog.Error(err, "unable to read runner api key")
+			return err
+		}
+	}
 
 	runnerManager := controllers.NewRunnerManager(
 		log,
 		mgr.GetClient(),
 		mgr.GetScheme(),
+		recorder,
 		factory,
-		runner.NewClient(),
+		runner.NewClient(runnerAPIKey),
+		jobQueue,
 		config.runnerManagerInterval,
 	)
 	defer runnerManager.StopAll()
@@ -84,15 +112,32 @@ func run() error {
 	secretUpdater := controllers.NewSecretUpdater(
 		log,
 		mgr.GetClient(),
+		recorder,
 		factory,
 	)
 	defer secretUpdater.StopAll()
 
-	orgRegexp, repoRegexp, err := loadValidationRuleFromFile(config.configFile)
+	cfg, err := loadConfigFile(config.configFile)
+	if err != nil {
+		setupLog.Error(err, "unable to read config file")
+		return err
+	}
+	orgRegexp, repoRegexp, enterpriseRegexp, err := cfg.validationRules()
 	if err != nil {
 		setupLog.Error(err, "unable to read validation rule from config file")
 		return err
 	}
+	sharedCredentials, err := cfg.sharedCredentials(config.controllerNamespace)
+	if err != nil {
+		setupLog.Error(err, "unable to read shared credentials from config file")
+		return err
+	}
+
+	credentialProviders, err := newCredentialProviders()
+	if err != nil {
+		setupLog.Error(err, "unable to set up credential providers")
+		return err
+	}
 
 	reconciler := controllers.NewRunnerPoolReconciler(
 		log,
@@ -103,6 +148,10 @@ func run() error {
 		secretUpdater,
 		orgRegexp,
 		repoRegexp,
+		enterpriseRegexp,
+		sharedCredentials,
+		credentialProviders,
+		runnerAPIKey,
 	)
 
 	if err = reconciler.SetupWithManager(mgr); err != nil {
@@ -134,35 +183,89 @@ func run() error {
 	return nil
 }
 
-type validationRuleConfig struct {
-	OrganizationRule string `yaml:"organization-rule"`
-	RepositoryRule   string `yaml:"repository-rule"`
+func newCredentialProviders() (map[string]controllers.CredentialProvider, error) {
+	providers := map[string]controllers.CredentialProvider{}
+	if config.credentialDir != "" {
+		providers[meowsv1alpha1.CredentialProviderFile] = controllers.NewFileCredentialProvider(config.credentialDir)
+	}
+	if config.vaultAddress != "" {
+		if config.vaultTokenFile == "" {
+			return nil, errors.New("vault-token-file should be specified to access the vault server")
+		}
+		providers[meowsv1alpha1.CredentialProviderVault] = controllers.NewVaultCredentialProvider(config.vaultAddress, config.vaultTokenFile, config.vaultPathPrefix)
+	}
+	return providers, nil
+}
+
+func addGitHubWebhookServer(mgr ctrl.Manager, jobQueue controllers.JobQueue) error {
+	if config.githubWebhookSecretFile == "" {
+		return errors.New("github-webhook-secret-file should be specified to verify the webhook payloads")
+	}
+	data, err := os.ReadFile(config.githubWebhookSecretFile)
+	if err != nil {
+		return fmt.Errorf("failed to read github webhook secret file %q: %w", config.githubWebhookSecretFile, err)
+	}
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return fmt.Errorf("github webhook secret file %q is empty", config.githubWebhookSecretFile)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/"+constants.GitHubWebhookEndpoint, github.NewWebhookHandler([]byte(secret), jobQueue.Update))
+	return mgr.Add(&manager.Server{
+		Name: "github-webhook",
+		Server: &http.Server{
+			Addr:    config.githubWebhookAddr,
+			Handler: mux,
+		},
+		// The queued jobs are only used by the runner managers working in the leader.
+		OnlyServeWhenLeader: true,
+	})
 }
 
-func loadValidationRuleFromFile(path string) (*regexp.Regexp, *regexp.Regexp, error) {
+type controllerConfig struct {
+	OrganizationRule  string                   `yaml:"organization-rule"`
+	RepositoryRule    string                   `yaml:"repository-rule"`
+	EnterpriseRule    string                   `yaml:"enterprise-rule"`
+	DefaultCredential string                   `yaml:"default-credential"`
+	SharedCredentials []sharedCredentialConfig `yaml:"shared-credentials"`
+}
+
+type sharedCredentialConfig struct {
+	Name          string `yaml:"name"`
+	SecretName    string `yaml:"secret-name"`
+	Path          string `yaml:"path"`
+	NamespaceRule string `yaml:"namespace-rule"`
+}
+
+func loadConfigFile(path string) (*controllerConfig, error) {
+	cfg := &controllerConfig{}
 	if path == "" {
-		return nil, nil, nil
+		return cfg, nil
 	}
 
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read config file %q: %w", path, err)
+		return nil, fmt.Errorf("failed to read config file %q: %w", path, err)
 	}
-
-	var cfg validationRuleConfig
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, nil, fmt.Errorf("failed to parse config file: %w", err)
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	return cfg, nil
+}
+
+func (cfg *controllerConfig) validationRules() (*regexp.Regexp, *regexp.Regexp, *regexp.Regexp, error) {
 	setupLog.Info("validation rule loaded",
 		"organization-rule", cfg.OrganizationRule,
 		"repository-rule", cfg.RepositoryRule,
+		"enterprise-rule", cfg.EnterpriseRule,
 	)
 
 	var orgRegexp *regexp.Regexp
 	if cfg.OrganizationRule != "" {
 		re, err := regexp.Compile(cfg.OrganizationRule)
 		if err != nil {
-			return nil, nil, fmt.Errorf("invalid organization-rule: %w", err)
+			return nil, nil, nil, fmt.Errorf("invalid organization-rule: %w", err)
 		}
 		orgRegexp = re
 	}
@@ -171,10 +274,68 @@ func loadValidationRuleFromFile(path string) (*regexp.Regexp, *regexp.Regexp, er
 	if cfg.RepositoryRule != "" {
 		re, err := regexp.Compile(cfg.RepositoryRule)
 		if err != nil {
-			return nil, nil, fmt.Errorf("invalid repository-rule: %w", err)
+			return nil, nil, nil, fmt.Errorf("invalid repository-rule: %w", err)
 		}
 		repoRegexp = re
 	}
 
-	return orgRegexp, repoRegexp, nil
+	var enterpriseRegexp *regexp.Regexp
+	if cfg.EnterpriseRule != "" {
+		re, err := regexp.Compile(cfg.EnterpriseRule)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid enterprise-rule: %w", err)
+		}
+		enterpriseRegexp = re
+	}
+
+	return orgRegexp, repoRegexp, enterpriseRegexp, nil
+}
+
+func (cfg *controllerConfig) sharedCredentials(namespace string) (*controllers.SharedCredentials, error) {
+	if len(cfg.SharedCredentials) == 0 {
+		if cfg.DefaultCredential != "" {
+			return nil, fmt.Errorf("default-credential %q is not found in shared-credentials", cfg.DefaultCredential)
+		}
+		return nil, nil
+	}
+
+	shared := &controllers.SharedCredentials{
+		Namespace:   namespace,
+		Default:     cfg.DefaultCredential,
+		Credentials: map[string]*controllers.SharedCredential{},
+	}
+	for _, c := range cfg.SharedCredentials {
+		if c.Name == "" {
+			return nil, errors.New("name of shared credential should not be empty")
+		}
+		if _, ok := shared.Credentials[c.Name]; ok {
+			return nil, fmt.Errorf("shared credential %q is duplicated", c.Name)
+		}
+		if c.NamespaceRule == "" {
+			return nil, fmt.Errorf("namespace-rule of shared credential %q should not be empty", c.Name)
+		}
+		re, err := controllers.CompileNamespaceRule(c.NamespaceRule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace-rule of shared credential %q: %w", c.Name, err)
+		}
+		secretName := c.SecretName
+		if secretName == "" {
+			secretName = c.Name
+		}
+		if c.Path == "" && namespace == "" {
+			return nil, fmt.Errorf("controller-namespace should be specified to read shared credential %q from the secret", c.Name)
+		}
+		shared.Credentials[c.Name] = &controllers.SharedCredential{
+			SecretName:    secretName,
+			Path:          c.Path,
+			NamespaceRule: re,
+		}
+		setupLog.Info("shared credential loaded", "name", c.Name, "secret-name", secretName, "path", c.Path, "namespace-rule", c.NamespaceRule)
+	}
+	if shared.Default != "" {
+		if _, ok := shared.Credentials[shared.Default]; !ok {
+			return nil, fmt.Errorf("default-credential %q is not found in shared-credentials", shared.Default)
+		}
+	}
+	return shared, nil
 }
@@ -1,6 +1,11 @@
 package main
 
-import "github.com/cybozu-go/meows/cmd/controller/cmd"
+import (
+	// Embed the time zone database to evaluate the schedules of RunnerPools in any environment.
+	_ "time/tzdata"
+
+	"github.com/cybozu-go/meows/cmd/controller/cmd"
+)
 
 func main() {
 	cmd.Execute()
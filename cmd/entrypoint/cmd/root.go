@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	constants "github.com/cybozu-go/meows"
 	"github.com/cybozu-go/meows/runner"
@@ -31,10 +34,21 @@ var rootCmd = &cobra.Command{
 		podName := os.Getenv(constants.PodNameEnvName)
 		logger := zap.New(zap.UseFlagOptions(&config.zapOpts)).WithName("runner").WithValues("pod", podName)
 		log.SetLogger(logger)
-		well.Go(r.Run)
 
-		well.Stop()
-		return well.Wait()
+		// Use an own environment instead of the global one, which is canceled at once by SIGTERM.
+		env := well.NewEnvironment(context.Background())
+		env.Go(r.Run)
+		go func() {
+			ch := make(chan os.Signal, 1)
+			signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+			s := <-ch
+			logger.Info("got signal; terminating runner", "signal", s.String())
+			r.Terminate(context.Background())
+			env.Cancel(nil)
+		}()
+
+		env.Stop()
+		return env.Wait()
 	},
 }
 
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+
+	constants "github.com/cybozu-go/meows"
+	"github.com/cybozu-go/meows/runner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diagDir          string
+	jobResultFile    string
+	jobInfoFile      string
+	slackChannelFile string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "job-completed",
+	Short: "Record the result of the job",
+	Long: `Record the result of the job and the failed steps read from the log of the runner worker.
+This command is executed by the job-completed hook of the runner, so the workflows need not call it.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Record the job info and the Slack channel for the jobs not calling job-started.
+		if _, err := os.Stat(jobInfoFile); errors.Is(err, fs.ErrNotExist) {
+			jobInfo, err := runner.GetJobInfo()
+			if err != nil {
+				return err
+			}
+			data, err := json.Marshal(jobInfo)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(jobInfoFile, data, 0664); err != nil {
+				return err
+			}
+		}
+
+		if _, err := os.Stat(slackChannelFile); errors.Is(err, fs.ErrNotExist) {
+			if slackChannel := os.Getenv(constants.SlackChannelEnvName); slackChannel != "" {
+				if err := os.WriteFile(slackChannelFile, []byte(slackChannel), 0664); err != nil {
+					return err
+				}
+			}
+		}
+
+		res, err := runner.ReadWorkerLog(diagDir)
+		if err != nil {
+			return err
+		}
+		if res == nil {
+			return errors.New("no worker log found in " + diagDir)
+		}
+		return runner.WriteJobResultFile(jobResultFile, res)
+	},
+}
+
+// Execute adds all child commands to the root command and sets flags appropriately.
+// This is called by main.main(). It only needs to happen once to the rootCmd.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func init() {
+	fs := rootCmd.Flags()
+	fs.StringVarP(&diagDir, "diag-dir", "d", constants.RunnerRootDirPath+"/_diag", "Directory of the runner logs.")
+	fs.StringVarP(&jobResultFile, "result-file", "r", constants.RunnerVarDirPath+"/job_result.json", "Job result file.")
+	fs.StringVarP(&jobInfoFile, "jobinfo-file", "f", constants.RunnerVarDirPath+"/github.env", "Job info file.")
+	fs.StringVarP(&slackChannelFile, "slackchannel-file", "s", constants.SlackChannelFilePath, "A file that describes the Slack channel to be notified.")
+}
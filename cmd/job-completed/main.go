@@ -0,0 +1,7 @@
+package main
+
+import "github.com/cybozu-go/meows/cmd/job-completed/cmd"
+
+func main() {
+	cmd.Execute()
+}
@@ -32,7 +32,12 @@ var rootCmd = &cobra.Command{
 		}
 
 		slackChannel := os.Getenv(constants.SlackChannelEnvName)
-		return os.WriteFile(slackChannelFile, []byte(slackChannel), 0664)
+		err = os.WriteFile(slackChannelFile, []byte(slackChannel), 0664)
+		if err != nil {
+			return err
+		}
+
+		return runner.LinkRepositoryCaches(jobInfo.Repository)
 	},
 }
 
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cybozu-go/meows/runner"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use: "cache",
+	}
+	cmd.AddCommand(newEvictCmd())
+	return cmd
+}
+
+func newEvictCmd() *cobra.Command {
+	var sizeLimit string
+	var depth int
+	var jobWindow time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "evict DIRECTORY",
+		Short: "evict the least recently used cache entries",
+		Long: `This command removes the least recently used entries in the cache directory until the total size is within the limit.
+The entries used within the job window are not removed, because running jobs may hold them.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			limit, err := resource.ParseQuantity(sizeLimit)
+			if err != nil {
+				return fmt.Errorf("invalid size limit %q; %w", sizeLimit, err)
+			}
+
+			removed, err := runner.EvictCache(args[0], depth, limit.Value(), jobWindow)
+			for _, p := range removed {
+				fmt.Printf("evict cache %s\n", p)
+			}
+			return err
+		},
+	}
+
+	fs := cmd.Flags()
+	fs.StringVar(&sizeLimit, "size-limit", "", "The size of the cache to start the eviction (e.g. 10Gi).")
+	fs.IntVar(&depth, "depth", 1, "The depth of the cache entries. Specify 2 for the caches kept for each repository.")
+	fs.DurationVar(&jobWindow, "job-window", runner.DefaultCacheJobWindow, "The duration to protect the cache entries used by the jobs from the eviction.")
+	cmd.MarkFlagRequired("size-limit")
+	return cmd
+}
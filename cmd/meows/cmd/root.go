@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 
+	"github.com/cybozu-go/meows/cmd/meows/cmd/cache"
 	"github.com/cybozu-go/meows/cmd/meows/cmd/runner"
 	"github.com/cybozu-go/meows/cmd/meows/cmd/slackagent"
 	"github.com/spf13/cobra"
@@ -23,4 +24,5 @@ func Execute() {
 func init() {
 	rootCmd.AddCommand(slackagent.NewCommand())
 	rootCmd.AddCommand(runner.NewCommand())
+	rootCmd.AddCommand(cache.NewCommand())
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	constants "github.com/cybozu-go/meows"
 	"github.com/cybozu-go/meows/github"
 	"github.com/cybozu-go/well"
 	"github.com/spf13/cobra"
@@ -16,6 +17,7 @@ var config struct {
 	appInstallationID   int64
 	appPrivateKeyPath   string
 	personalAccessToken string
+	githubURL           string
 }
 
 var githubClient github.Client
@@ -39,10 +41,12 @@ func NewCommand() *cobra.Command {
 			var cred *github.ClientCredential
 			if config.personalAccessToken != "" {
 				cred = &github.ClientCredential{
+					GitHubURL:           config.githubURL,
 					PersonalAccessToken: config.personalAccessToken,
 				}
 			} else {
 				cred = &github.ClientCredential{
+					GitHubURL:         config.githubURL,
 					AppID:             config.appID,
 					AppInstallationID: config.appInstallationID,
 					PrivateKeyPath:    config.appPrivateKeyPath,
@@ -65,6 +69,7 @@ func NewCommand() *cobra.Command {
 	fs.Int64Var(&config.appInstallationID, "app-installation-id", 0, "The installation ID for GitHub App.")
 	fs.StringVar(&config.appPrivateKeyPath, "app-private-key-path", "", "The path for GitHub App private key.")
 	fs.StringVar(&config.personalAccessToken, "token", "", "The personal access token (PAT) of GitHub.")
+	fs.StringVar(&config.githubURL, "github-url", constants.DefaultGitHubURL, "The URL of the GitHub server.")
 	return cmd
 }
 
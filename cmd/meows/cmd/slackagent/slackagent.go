@@ -60,7 +60,7 @@ If RESULT is omitted or any other value is specified, it will be treated as 'unk
 			if err != nil {
 				return err
 			}
-			return c.PostResult(context.Background(), config.channel, result, config.extend, config.namespace, podName, jobInfo)
+			return c.PostResult(context.Background(), config.channel, result, nil, config.extend, config.namespace, podName, jobInfo)
 		},
 	}
 
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/cybozu-go/meows/agent"
+	"github.com/cybozu-go/meows/runner"
 	"github.com/cybozu-go/well"
 	"github.com/go-logr/zapr"
 	"github.com/spf13/cobra"
@@ -18,6 +19,7 @@ const (
 	channelFlagName     = "channel"
 	appTokenFlagName    = "app-token"
 	botTokenFlagName    = "bot-token"
+	apiKeyFileFlagName  = "runner-api-key-file"
 	developmentFlagName = "development"
 	verboseFlagName     = "verbose"
 )
@@ -41,6 +43,15 @@ var rootCmd = &cobra.Command{
 			return fmt.Errorf(`"%s" should not be empty`, botTokenFlagName)
 		}
 
+		var apiKey []byte
+		if apiKeyFile := viper.GetString(apiKeyFileFlagName); apiKeyFile != "" {
+			key, err := runner.ReadAPIKey(apiKeyFile)
+			if err != nil {
+				return err
+			}
+			apiKey = key
+		}
+
 		cmd.SilenceUsage = true
 
 		zapLog, err := zap.NewProduction()
@@ -49,7 +60,7 @@ var rootCmd = &cobra.Command{
 		}
 		log := zapr.NewLogger(zapLog)
 
-		s, err := agent.NewServer(log, listenAddr, defaultChannel, appToken, botToken, devMode, verbose)
+		s, err := agent.NewServer(log, listenAddr, defaultChannel, appToken, botToken, apiKey, devMode, verbose)
 		if err != nil {
 			return err
 		}
@@ -68,6 +79,7 @@ func init() {
 
 	fs.String(appTokenFlagName, "", "The Slack App token.")
 	fs.String(botTokenFlagName, "", "The Slack Bot token.")
+	fs.String(apiKeyFileFlagName, "", "Path to the file containing the key to authenticate to the runner pods. It should be the same as the key of the controller.")
 
 	if err := viper.BindPFlags(fs); err != nil {
 		panic(err)
@@ -9,6 +9,43 @@ const (
 const (
 	// RunnerContainerName is a container name which runs GitHub Actions runner.
 	RunnerContainerName = "runner"
+
+	// DockerContainerName is a container name which runs Docker daemon in the dind container mode.
+	DockerContainerName = "docker"
+
+	// BuildkitContainerName is a container name which runs BuildKit daemon in the rootless-buildkit container mode.
+	BuildkitContainerName = "buildkitd"
+)
+
+// Volume names
+const (
+	// VarDirVolumeName is a volume name for the directory storing variable files.
+	VarDirVolumeName = "var-dir"
+
+	// WorkDirVolumeName is a volume name for the working directory.
+	// Additional containers can mount this volume to share the working directory with the runner container.
+	WorkDirVolumeName = "work-dir"
+
+	// DockerCertsVolumeName is a volume name for the TLS certificates of Docker daemon.
+	DockerCertsVolumeName = "docker-certs"
+
+	// DockerDataVolumeName is a volume name for the data of Docker daemon.
+	DockerDataVolumeName = "docker-data"
+
+	// BuildkitSocketVolumeName is a volume name for the socket of BuildKit daemon.
+	BuildkitSocketVolumeName = "buildkit-socket"
+
+	// BuildkitDataVolumeName is a volume name for the data of BuildKit daemon.
+	BuildkitDataVolumeName = "buildkit-data"
+)
+
+// Default images of the sidecar containers.
+const (
+	// DefaultDockerImage is the image of Docker daemon in the dind container mode.
+	DefaultDockerImage = "docker:28-dind"
+
+	// DefaultBuildkitImage is the image of BuildKit daemon in the rootless-buildkit container mode.
+	DefaultBuildkitImage = "moby/buildkit:v0.23.2-rootless"
 )
 
 // Metadata keys
@@ -37,6 +74,15 @@ const (
 
 	// AppComponentRunner is the component name for runner.
 	AppComponentRunner = "runner"
+
+	// AppComponentCache is the component name for the cache volumes.
+	AppComponentCache = "cache"
+
+	// AppComponentCacheEviction is the component name for the cache eviction.
+	AppComponentCacheEviction = "cache-eviction"
+
+	// AppComponentContainerHooks is the component name for the runner container hooks.
+	AppComponentContainerHooks = "container-hooks"
 )
 
 // Container ports
@@ -55,6 +101,15 @@ const (
 
 	// StatusEndPoint is the endpoint to get status of a runner pod.
 	StatusEndPoint = "status"
+
+	// JITConfigEndpoint is the endpoint to put the just-in-time runner configuration to a runner pod.
+	JITConfigEndpoint = "jit_config"
+)
+
+// Controller endpoints
+const (
+	// GitHubWebhookEndpoint is the endpoint to receive webhook events from GitHub.
+	GitHubWebhookEndpoint = "github_webhook"
 )
 
 // Runner pods state.
@@ -63,6 +118,8 @@ const (
 	RunnerPodStateRunning      = "running"
 	RunnerPodStateDebugging    = "debugging"
 	RunnerPodStateStale        = "stale"
+	RunnerPodStateTerminating  = "terminating"
+	RunnerPodStateFailed       = "failed"
 )
 
 // Exit state of Actions Listener.
@@ -90,6 +147,11 @@ const (
 	DefaultSlackAgentServiceName = "slack-agent.meows.svc"
 )
 
+const (
+	// DefaultGitHubURL is the URL of GitHub.com.
+	DefaultGitHubURL = "https://github.com"
+)
+
 // Directory path for runner pods.
 const (
 	// RunnerRootDirPath is a directory path where GitHub Actions Runner will be installed.
@@ -109,6 +171,18 @@ const (
 
 	// RunnerTokenFileName is a file name for GitHub registration token.
 	RunnerTokenFileName = "runnertoken"
+
+	// APITokenDirName is a directory name for storing the token to authenticate the requests to the runner pod.
+	APITokenDirName = "api-token"
+
+	// APITokenFileName is a file name for the token to authenticate the requests to the runner pod.
+	APITokenFileName = "token"
+
+	// RunnerContainerHooksPath is a file path of the runner container hooks for the kubernetes container mode.
+	RunnerContainerHooksPath = RunnerRootDirPath + "/k8s/index.js"
+
+	// RepositoryCachesDirPath is a directory path where the caches kept for each repository are mounted.
+	RepositoryCachesDirPath = "/meows-caches"
 )
 
 // Environment variables
@@ -125,6 +199,9 @@ const (
 	// RunnerRepoEnvName is a env field key for RUNNER_REPO.
 	RunnerRepoEnvName = "RUNNER_REPO"
 
+	// RunnerEnterpriseEnvName is a env field key for RUNNER_ENTERPRISE.
+	RunnerEnterpriseEnvName = "RUNNER_ENTERPRISE"
+
 	// RunnerPoolNameEnvName is a env field key for RUNNER_POOL_NAME.
 	RunnerPoolNameEnvName = "RUNNER_POOL_NAME"
 
@@ -133,4 +210,7 @@ const (
 
 	// SlackChannelEnvName is a env field key for MEOWS_SLACK_CHANNEL
 	SlackChannelEnvName = "MEOWS_SLACK_CHANNEL"
+
+	// RepositoryCachesEnvName is a env field key for MEOWS_REPOSITORY_CACHES
+	RepositoryCachesEnvName = "MEOWS_REPOSITORY_CACHES"
 )
@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	constants "github.com/cybozu-go/meows"
+	meowsv1alpha1 "github.com/cybozu-go/meows/api/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	dockerCertsDirPath = "/certs"
+	dockerDataDirPath  = "/var/lib/docker"
+	dockerTLSPort      = "2376"
+
+	buildkitSocketDirPath = "/run/buildkit"
+	buildkitDataDirPath   = "/home/user/.local/share/buildkit"
+
+	// buildkitUserID is the user which runs BuildKit daemon in the rootless image.
+	buildkitUserID = 1000
+
+	defaultKubernetesModeWorkVolumeSize = "10Gi"
+)
+
+// containerMode is the set of the resources injected into the runner pod for the container mode of the RunnerPool.
+type containerMode struct {
+	sidecar      *corev1.Container
+	volumes      []corev1.Volume
+	runnerEnv    []corev1.EnvVar
+	runnerMounts []corev1.VolumeMount
+
+	// supplementalGroups are added to the runner pod to access the files created by the sidecar.
+	supplementalGroups []int64
+
+	// serviceAccountName overrides the service account of the runner pod.
+	serviceAccountName string
+
+	// workVolumeClaimTemplate is used for the working directory if the RunnerPool does not specify it.
+	workVolumeClaimTemplate *meowsv1alpha1.WorkVolumeClaimTemplate
+}
+
+// makeContainerMode returns the resources for the container mode of the RunnerPool.
+// It returns nil if the RunnerPool does not use any container mode.
+func makeContainerMode(rp *meowsv1alpha1.RunnerPool) *containerMode {
+	switch rp.Spec.ContainerMode {
+	case meowsv1alpha1.ContainerModeDind:
+		return makeDindContainerMode(rp)
+	case meowsv1alpha1.ContainerModeRootlessBuildkit:
+		return makeRootlessBuildkitContainerMode(rp)
+	case meowsv1alpha1.ContainerModeKubernetes:
+		return makeKubernetesContainerMode(rp)
+	default:
+		return nil
+	}
+}
+
+func makeSidecar(rp *meowsv1alpha1.RunnerPool, name, defaultImage string) *corev1.Container {
+	image := rp.Spec.Sidecar.Image
+	if image == "" {
+		image = defaultImage
+	}
+	return &corev1.Container{
+		Name:            name,
+		Image:           image,
+		ImagePullPolicy: rp.Spec.Sidecar.ImagePullPolicy,
+		Resources:       rp.Spec.Sidecar.Resources,
+	}
+}
+
+// makeDindContainerMode runs Docker daemon in a privileged sidecar.
+// The runner container connects to the daemon over TLS with the client certificates generated by the daemon.
+func makeDindContainerMode(rp *meowsv1alpha1.RunnerPool) *containerMode {
+	sidecar := makeSidecar(rp, constants.DockerContainerName, constants.DefaultDockerImage)
+	sidecar.SecurityContext = &corev1.SecurityContext{
+		Privileged: ptr.To(true),
+	}
+	sidecar.Env = []corev1.EnvVar{
+		{Name: "DOCKER_TLS_CERTDIR", Value: dockerCertsDirPath},
+	}
+	sidecar.VolumeMounts = []corev1.VolumeMount{
+		{Name: constants.DockerCertsVolumeName, MountPath: dockerCertsDirPath},
+		{Name: constants.DockerDataVolumeName, MountPath: dockerDataDirPath},
+		// Share the working directory to bind-mount the files of the jobs into the containers.
+		{Name: constants.WorkDirVolumeName, MountPath: constants.RunnerWorkDirPath},
+	}
+
+	return &containerMode{
+		sidecar: sidecar,
+		volumes: []corev1.Volume{
+			{Name: constants.DockerCertsVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			{Name: constants.DockerDataVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		},
+		runnerEnv: []corev1.EnvVar{
+			{Name: "DOCKER_HOST", Value: "tcp://localhost:" + dockerTLSPort},
+			{Name: "DOCKER_TLS_VERIFY", Value: "1"},
+			{Name: "DOCKER_CERT_PATH", Value: dockerCertsDirPath + "/client"},
+		},
+		runnerMounts: []corev1.VolumeMount{
+			{Name: constants.DockerCertsVolumeName, MountPath: dockerCertsDirPath, ReadOnly: true},
+		},
+	}
+}
+
+// makeRootlessBuildkitContainerMode runs BuildKit daemon without privileges in a sidecar.
+// The runner container connects to the daemon with the socket in the shared volume.
+func makeRootlessBuildkitContainerMode(rp *meowsv1alpha1.RunnerPool) *containerMode {
+	sidecar := makeSidecar(rp, constants.BuildkitContainerName, constants.DefaultBuildkitImage)
+	sidecar.Args = []string{
+		"--addr", "unix://" + buildkitSocketDirPath + "/buildkitd.sock",
+		"--oci-worker-no-process-sandbox",
+	}
+	sidecar.SecurityContext = &corev1.SecurityContext{
+		RunAsUser:  ptr.To[int64](buildkitUserID),
+		RunAsGroup: ptr.To[int64](buildkitUserID),
+		// Rootless BuildKit creates the user namespaces, which are denied by the default profiles.
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeUnconfined,
+		},
+		AppArmorProfile: &corev1.AppArmorProfile{
+			Type: corev1.AppArmorProfileTypeUnconfined,
+		},
+	}
+	sidecar.VolumeMounts = []corev1.VolumeMount{
+		{Name: constants.BuildkitSocketVolumeName, MountPath: buildkitSocketDirPath},
+		{Name: constants.BuildkitDataVolumeName, MountPath: buildkitDataDirPath},
+	}
+
+	return &containerMode{
+		sidecar: sidecar,
+		volumes: []corev1.Volume{
+			{Name: constants.BuildkitSocketVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			{Name: constants.BuildkitDataVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		},
+		runnerEnv: []corev1.EnvVar{
+			{Name: "BUILDKIT_HOST", Value: "unix://" + buildkitSocketDirPath + "/buildkitd.sock"},
+		},
+		runnerMounts: []corev1.VolumeMount{
+			{Name: constants.BuildkitSocketVolumeName, MountPath: buildkitSocketDirPath},
+		},
+		// The socket is accessible only for the group of the daemon.
+		supplementalGroups: []int64{buildkitUserID},
+	}
+}
+
+// makeKubernetesContainerMode runs the job containers as separate pods with the runner container hooks.
+// The job pods mount the PersistentVolumeClaim of the working directory of the runner pod,
+// which is found by the hooks with ACTIONS_RUNNER_CLAIM_NAME.
+func makeKubernetesContainerMode(rp *meowsv1alpha1.RunnerPool) *containerMode {
+	return &containerMode{
+		runnerEnv: []corev1.EnvVar{
+			{Name: "ACTIONS_RUNNER_CONTAINER_HOOKS", Value: constants.RunnerContainerHooksPath},
+			// POD_NAME is defined before these variables, so it is expanded by Kubernetes.
+			{Name: "ACTIONS_RUNNER_POD_NAME", Value: "$(" + constants.PodNameEnvName + ")"},
+			{Name: "ACTIONS_RUNNER_CLAIM_NAME", Value: "$(" + constants.PodNameEnvName + ")-" + constants.WorkDirVolumeName},
+		},
+		serviceAccountName: rp.GetContainerHooksServiceAccountName(),
+		workVolumeClaimTemplate: &meowsv1alpha1.WorkVolumeClaimTemplate{
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse(defaultKubernetesModeWorkVolumeSize),
+					},
+				},
+			},
+		},
+	}
+}
+
+// containerHooksRules are the permissions for the runner container hooks to run the job containers as pods.
+// The jobs can use them to create pods mounting any Secret in the namespace and to exec into any pod in it,
+// so the RunnerPool of the kubernetes container mode requires a dedicated namespace. See checkDedicatedNamespace.
+var containerHooksRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{""},
+		Resources: []string{"pods"},
+		Verbs:     []string{"get", "list", "create", "delete"},
+	},
+	{
+		APIGroups: []string{""},
+		Resources: []string{"pods/exec"},
+		Verbs:     []string{"get", "create"},
+	},
+	{
+		APIGroups: []string{""},
+		Resources: []string{"pods/log"},
+		Verbs:     []string{"get", "list", "watch"},
+	},
+	{
+		APIGroups: []string{"batch"},
+		Resources: []string{"jobs"},
+		Verbs:     []string{"get", "list", "create", "delete"},
+	},
+	{
+		APIGroups: []string{""},
+		Resources: []string{"secrets"},
+		Verbs:     []string{"create", "delete"},
+	},
+}
+
+// checkDedicatedNamespace refuses the RunnerPool if the namespace is shared with a RunnerPool of the kubernetes container mode,
+// or with the shared credentials.
+func (r *RunnerPoolReconciler) checkDedicatedNamespace(ctx context.Context, rp *meowsv1alpha1.RunnerPool) error {
+	kubernetesMode := rp.Spec.ContainerMode == meowsv1alpha1.ContainerModeKubernetes
+	if kubernetesMode && r.sharedCredentials != nil && rp.Namespace == r.sharedCredentials.Namespace {
+		return fmt.Errorf("containerMode kubernetes cannot be used in namespace %s, which has the shared credentials", rp.Namespace)
+	}
+
+	rpList := &meowsv1alpha1.RunnerPoolList{}
+	if err := r.List(ctx, rpList, client.InNamespace(rp.Namespace)); err != nil {
+		return err
+	}
+	for i := range rpList.Items {
+		other := &rpList.Items[i]
+		if other.Name == rp.Name {
+			continue
+		}
+		if kubernetesMode {
+			return fmt.Errorf("containerMode kubernetes requires a dedicated namespace, but RunnerPool %s is in namespace %s", other.Name, rp.Namespace)
+		}
+		if other.Spec.ContainerMode == meowsv1alpha1.ContainerModeKubernetes {
+			return fmt.Errorf("RunnerPool %s in namespace %s uses containerMode kubernetes, which requires a dedicated namespace", other.Name, rp.Namespace)
+		}
+	}
+	return nil
+}
+
+// reconcileContainerHooksRBAC creates the ServiceAccount of the runner pods and its permissions for the kubernetes container mode.
+// They are deleted if the RunnerPool does not use the mode.
+func (r *RunnerPoolReconciler) reconcileContainerHooksRBAC(ctx context.Context, log logr.Logger, rp *meowsv1alpha1.RunnerPool) error {
+	name := rp.GetContainerHooksServiceAccountName()
+	sa := &corev1.ServiceAccount{}
+	role := &rbacv1.Role{}
+	rb := &rbacv1.RoleBinding{}
+	for _, obj := range []client.Object{sa, role, rb} {
+		obj.SetNamespace(rp.Namespace)
+		obj.SetName(name)
+	}
+
+	if rp.Spec.ContainerMode != meowsv1alpha1.ContainerModeKubernetes {
+		for _, obj := range []client.Object{rb, role, sa} {
+			err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			// Do not delete the objects of the same name created by others.
+			if !metav1.IsControlledBy(obj, rp) {
+				continue
+			}
+			err = r.Delete(ctx, obj, client.Preconditions{UID: ptr.To(obj.GetUID())})
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			log.Info("deleted object for container hooks", "kind", fmt.Sprintf("%T", obj), "name", name)
+		}
+		return nil
+	}
+
+	labels := componentLabelSet(rp, constants.AppComponentContainerHooks)
+	for _, f := range []struct {
+		obj    client.Object
+		mutate func()
+	}{
+		{obj: sa, mutate: func() {}},
+		{obj: role, mutate: func() {
+			role.Rules = containerHooksRules
+		}},
+		{obj: rb, mutate: func() {
+			rb.RoleRef = rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "Role",
+				Name:     name,
+			}
+			rb.Subjects = []rbacv1.Subject{
+				{
+					Kind:      rbacv1.ServiceAccountKind,
+					Namespace: rp.Namespace,
+					Name:      name,
+				},
+			}
+		}},
+	} {
+		op, err := ctrl.CreateOrUpdate(ctx, r.Client, f.obj, func() error {
+			f.obj.SetLabels(mergeMap(f.obj.GetLabels(), labels))
+			f.mutate()
+			return ctrl.SetControllerReference(rp, f.obj, r.scheme)
+		})
+		if err != nil {
+			return err
+		}
+		if op != controllerutil.OperationResultNone {
+			log.Info("reconciled object for container hooks", "kind", fmt.Sprintf("%T", f.obj), "name", name, "operation", string(op))
+		}
+	}
+	return nil
+}
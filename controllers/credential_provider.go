@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	constants "github.com/cybozu-go/meows"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CredentialProvider reads the data of a GitHub credential from a backend.
+// The data has the same keys as the credential secret (e.g. `token`, `app-id`).
+type CredentialProvider interface {
+	Read(ctx context.Context, namespace, name string) (map[string][]byte, error)
+}
+
+// secretCredentialProvider reads the credential from a Secret in the namespace.
+type secretCredentialProvider struct {
+	client client.Client
+}
+
+func (p *secretCredentialProvider) Read(ctx context.Context, namespace, name string) (map[string][]byte, error) {
+	s := &corev1.Secret{}
+	err := p.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credential secret; %w", err)
+	}
+	return s.Data, nil
+}
+
+type fileCredentialProvider struct {
+	dir string
+}
+
+// NewFileCredentialProvider returns a CredentialProvider which reads the credential from the files in `<dir>/<namespace>/<name>`.
+// Each file is named as a key of the credential secret, such as a mounted Secret.
+func NewFileCredentialProvider(dir string) CredentialProvider {
+	return &fileCredentialProvider{dir: dir}
+}
+
+func (p *fileCredentialProvider) Read(ctx context.Context, namespace, name string) (map[string][]byte, error) {
+	dir := filepath.Join(p.dir, namespace, filepath.FromSlash(path.Clean(name)))
+	if !strings.HasPrefix(dir, filepath.Join(p.dir, namespace)+string(filepath.Separator)) {
+		return nil, fmt.Errorf("invalid credential path %q", name)
+	}
+	data, err := readCredentialFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credential files; %w", err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no credential files in %s", dir)
+	}
+	return data, nil
+}
+
+func readCredentialFiles(dir string) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	for _, key := range []string{
+		constants.CredentialSecretDataPATToken,
+		constants.CredentialSecretDataAppID,
+		constants.CredentialSecretDataAppInstallationID,
+		constants.CredentialSecretDataAppPrivateKey,
+	} {
+		v, err := os.ReadFile(filepath.Join(dir, key))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		data[key] = v
+	}
+	return data, nil
+}
+
+const (
+	vaultTokenHeader = "X-Vault-Token"
+	vaultTimeout     = 30 * time.Second
+)
+
+type vaultCredentialProvider struct {
+	address    string
+	tokenFile  string
+	pathPrefix string
+	httpClient *http.Client
+}
+
+// NewVaultCredentialProvider returns a CredentialProvider which reads the credential from the KV secret
+// `<pathPrefix>/<namespace>/<name>` of the HashiCorp Vault-compatible server.
+// Both the KV secrets engine version 1 and 2 are supported. For version 2, pathPrefix should contain `data` (e.g. `secret/data/meows`).
+// The token is read from tokenFile on every request, so that it can be renewed by an agent such as Vault Agent.
+func NewVaultCredentialProvider(address, tokenFile, pathPrefix string) CredentialProvider {
+	return &vaultCredentialProvider{
+		address:    strings.TrimSuffix(address, "/"),
+		tokenFile:  tokenFile,
+		pathPrefix: strings.Trim(pathPrefix, "/"),
+		httpClient: &http.Client{Timeout: vaultTimeout},
+	}
+}
+
+func (p *vaultCredentialProvider) Read(ctx context.Context, namespace, name string) (map[string][]byte, error) {
+	secretPath := path.Join(p.pathPrefix, namespace, path.Clean(name))
+	if !strings.HasPrefix(secretPath, path.Join(p.pathPrefix, namespace)+"/") {
+		return nil, fmt.Errorf("invalid credential path %q", name)
+	}
+
+	token, err := os.ReadFile(p.tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault token; %w", err)
+	}
+
+	u, err := url.JoinPath(p.address, "v1", secretPath)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(vaultTokenHeader, strings.TrimSpace(string(token)))
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request vault; %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, res.Body)
+		return nil, fmt.Errorf("failed to read vault secret %s; status %d", secretPath, res.StatusCode)
+	}
+
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response; %w", err)
+	}
+
+	// The KV secrets engine version 2 wraps the secret with its metadata.
+	fields := body.Data
+	if raw, ok := body.Data["data"]; ok {
+		if _, ok := body.Data["metadata"]; ok {
+			fields = nil
+			if err := json.Unmarshal(raw, &fields); err != nil {
+				return nil, fmt.Errorf("failed to decode vault secret; %w", err)
+			}
+		}
+	}
+
+	data := map[string][]byte{}
+	for k, raw := range fields {
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("value of %s in vault secret should be a string", k)
+		}
+		data[k] = []byte(v)
+	}
+	return data, nil
+}
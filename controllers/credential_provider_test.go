@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Credential providers", func() {
+	ctx := context.Background()
+
+	It("should read the credential from the files", func() {
+		dir := GinkgoT().TempDir()
+		credDir := filepath.Join(dir, "test-ns", "github", "app")
+		Expect(os.MkdirAll(credDir, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(credDir, "app-id"), []byte("1234"), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(credDir, "app-private-key"), []byte("dummy-private-key"), 0600)).To(Succeed())
+
+		p := NewFileCredentialProvider(dir)
+		data, err := p.Read(ctx, "test-ns", "github/app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal(map[string][]byte{
+			"app-id":          []byte("1234"),
+			"app-private-key": []byte("dummy-private-key"),
+		}))
+
+		By("reading the credential not found")
+		_, err = p.Read(ctx, "test-ns", "github/pat")
+		Expect(err).To(HaveOccurred())
+
+		By("reading the credential of another namespace")
+		_, err = p.Read(ctx, "other-ns", "../test-ns/github/app")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should read the credential from the vault server", func() {
+		var requests []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Vault-Token") != "vault-token" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			requests = append(requests, r.URL.Path)
+			switch r.URL.Path {
+			case "/v1/secret/data/meows/test-ns/github-app":
+				w.Write([]byte(`{"data":{"data":{"app-id":"1234","app-private-key":"dummy-private-key"},"metadata":{"version":1}}}`))
+			case "/v1/kv/meows/test-ns/github-pat":
+				w.Write([]byte(`{"data":{"token":"dummy-pat"}}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		tokenFile := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(tokenFile, []byte("vault-token\n"), 0600)).To(Succeed())
+
+		By("reading the KV secret of version 2")
+		p := NewVaultCredentialProvider(server.URL, tokenFile, "secret/data/meows")
+		data, err := p.Read(ctx, "test-ns", "github-app")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal(map[string][]byte{
+			"app-id":          []byte("1234"),
+			"app-private-key": []byte("dummy-private-key"),
+		}))
+
+		By("reading the KV secret of version 1")
+		p = NewVaultCredentialProvider(server.URL+"/", tokenFile, "/kv/meows/")
+		data, err = p.Read(ctx, "test-ns", "github-pat")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal(map[string][]byte{
+			"token": []byte("dummy-pat"),
+		}))
+
+		By("reading the KV secret not found")
+		_, err = p.Read(ctx, "test-ns", "not-found")
+		Expect(err).To(HaveOccurred())
+
+		By("reading the KV secret of another namespace")
+		_, err = p.Read(ctx, "other-ns", "../test-ns/github-pat")
+		Expect(err).To(HaveOccurred())
+		Expect(requests).To(Equal([]string{
+			"/v1/secret/data/meows/test-ns/github-app",
+			"/v1/kv/meows/test-ns/github-pat",
+			"/v1/kv/meows/test-ns/not-found",
+		}))
+
+		By("reading with an invalid token")
+		Expect(os.WriteFile(tokenFile, []byte("invalid-token"), 0600)).To(Succeed())
+		_, err = p.Read(ctx, "test-ns", "github-pat")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cybozu-go/meows/github"
+)
+
+// queuedJobTimeout is the time to forget a queued job.
+// GitHub cancels a job that is not picked up by a self-hosted runner within 24 hours.
+// So a job that remains queued longer than this is considered that an event was missed.
+const queuedJobTimeout = 24 * time.Hour
+
+// JobQueue keeps track of the workflow jobs waiting for runners.
+// It is updated by `workflow_job` webhook events.
+type JobQueue interface {
+	Update(*github.WorkflowJob)
+	CountQueuedJobs(label string) int
+	Subscribe(label string) <-chan struct{}
+	Unsubscribe(label string)
+}
+
+type queuedJob struct {
+	labels   []string
+	queuedAt time.Time
+}
+
+type jobQueue struct {
+	mu       sync.Mutex
+	jobs     map[int64]*queuedJob
+	watchers map[string]chan struct{}
+}
+
+func NewJobQueue() JobQueue {
+	return &jobQueue{
+		jobs:     map[int64]*queuedJob{},
+		watchers: map[string]chan struct{}{},
+	}
+}
+
+// Update records or forgets a job according to the action of the event,
+// and notifies the subscribers of the job's labels.
+func (q *jobQueue) Update(job *github.WorkflowJob) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if job.Action == github.WorkflowJobActionQueued {
+		q.jobs[job.ID] = &queuedJob{
+			labels:   job.Labels,
+			queuedAt: time.Now(),
+		}
+	} else {
+		delete(q.jobs, job.ID)
+	}
+
+	for _, l := range job.Labels {
+		ch, ok := q.watchers[l]
+		if !ok {
+			continue
+		}
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// CountQueuedJobs returns the number of the queued jobs which require the label.
+func (q *jobQueue) CountQueuedJobs(label string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var count int
+	now := time.Now()
+	for id, job := range q.jobs {
+		if now.Sub(job.queuedAt) > queuedJobTimeout {
+			delete(q.jobs, id)
+			continue
+		}
+		if slices.Contains(job.labels, label) {
+			count++
+		}
+	}
+	return count
+}
+
+// Subscribe returns a channel which receives a value when a job with the label is updated.
+func (q *jobQueue) Subscribe(label string) <-chan struct{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ch, ok := q.watchers[label]
+	if !ok {
+		ch = make(chan struct{}, 1)
+		q.watchers[label] = ch
+	}
+	return ch
+}
+
+func (q *jobQueue) Unsubscribe(label string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.watchers, label)
+}
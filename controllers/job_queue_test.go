@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/cybozu-go/meows/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JobQueue", func() {
+	job := func(action string, id int64, labels ...string) *github.WorkflowJob {
+		return &github.WorkflowJob{ID: id, Action: action, Labels: labels}
+	}
+
+	DescribeTable("should count the queued jobs by the label",
+		func(events []*github.WorkflowJob, expected map[string]int) {
+			q := NewJobQueue()
+			for _, e := range events {
+				q.Update(e)
+			}
+			for label, count := range expected {
+				Expect(q.CountQueuedJobs(label)).To(Equal(count), "label: %s", label)
+			}
+		},
+		Entry("no jobs", nil, map[string]int{"ns/rp1": 0}),
+		Entry("queued jobs",
+			[]*github.WorkflowJob{
+				job(github.WorkflowJobActionQueued, 1, "self-hosted", "ns/rp1"),
+				job(github.WorkflowJobActionQueued, 2, "self-hosted", "ns/rp1"),
+				job(github.WorkflowJobActionQueued, 3, "self-hosted", "ns/rp2"),
+			},
+			map[string]int{"self-hosted": 3, "ns/rp1": 2, "ns/rp2": 1, "ns/rp3": 0},
+		),
+		Entry("a job in progress",
+			[]*github.WorkflowJob{
+				job(github.WorkflowJobActionQueued, 1, "self-hosted", "ns/rp1"),
+				job(github.WorkflowJobActionQueued, 2, "self-hosted", "ns/rp1"),
+				job(github.WorkflowJobActionInProgress, 1, "self-hosted", "ns/rp1"),
+			},
+			map[string]int{"ns/rp1": 1},
+		),
+		Entry("a completed job",
+			[]*github.WorkflowJob{
+				job(github.WorkflowJobActionQueued, 1, "self-hosted", "ns/rp1"),
+				job(github.WorkflowJobActionInProgress, 1, "self-hosted", "ns/rp1"),
+				job(github.WorkflowJobActionCompleted, 1, "self-hosted", "ns/rp1"),
+			},
+			map[string]int{"ns/rp1": 0},
+		),
+		Entry("a job completed without running",
+			[]*github.WorkflowJob{
+				job(github.WorkflowJobActionQueued, 1, "self-hosted", "ns/rp1"),
+				job(github.WorkflowJobActionCompleted, 1, "self-hosted", "ns/rp1"),
+			},
+			map[string]int{"ns/rp1": 0},
+		),
+		Entry("a job in progress whose queued event is missed",
+			[]*github.WorkflowJob{
+				job(github.WorkflowJobActionInProgress, 1, "self-hosted", "ns/rp1"),
+			},
+			map[string]int{"ns/rp1": 0},
+		),
+		Entry("a job queued again",
+			[]*github.WorkflowJob{
+				job(github.WorkflowJobActionQueued, 1, "self-hosted", "ns/rp1"),
+				job(github.WorkflowJobActionQueued, 1, "self-hosted", "ns/rp1"),
+			},
+			map[string]int{"ns/rp1": 1},
+		),
+	)
+
+	It("should forget the jobs queued longer than the timeout", func() {
+		q := NewJobQueue()
+		q.Update(job(github.WorkflowJobActionQueued, 1, "ns/rp1"))
+		q.Update(job(github.WorkflowJobActionQueued, 2, "ns/rp1"))
+		Expect(q.CountQueuedJobs("ns/rp1")).To(Equal(2))
+
+		jq := q.(*jobQueue)
+		jq.jobs[1].queuedAt = time.Now().Add(-queuedJobTimeout - time.Minute)
+		jq.jobs[2].queuedAt = time.Now().Add(-queuedJobTimeout + time.Minute)
+		Expect(q.CountQueuedJobs("ns/rp1")).To(Equal(1))
+		Expect(jq.jobs).NotTo(HaveKey(int64(1)))
+		Expect(jq.jobs).To(HaveKey(int64(2)))
+	})
+
+	DescribeTable("should notify the subscribers of the job's labels",
+		func(event *github.WorkflowJob, notified map[string]bool) {
+			q := NewJobQueue()
+			channels := map[string]<-chan struct{}{}
+			for label := range notified {
+				channels[label] = q.Subscribe(label)
+			}
+
+			q.Update(event)
+			for label, ch := range channels {
+				if notified[label] {
+					Expect(ch).To(Receive(), "label: %s", label)
+				} else {
+					Expect(ch).NotTo(Receive(), "label: %s", label)
+				}
+			}
+		},
+		Entry("queued", job(github.WorkflowJobActionQueued, 1, "self-hosted", "ns/rp1"),
+			map[string]bool{"self-hosted": true, "ns/rp1": true, "ns/rp2": false}),
+		Entry("in progress", job(github.WorkflowJobActionInProgress, 1, "ns/rp1"),
+			map[string]bool{"ns/rp1": true, "ns/rp2": false}),
+		Entry("completed", job(github.WorkflowJobActionCompleted, 1, "ns/rp1"),
+			map[string]bool{"ns/rp1": true, "ns/rp2": false}),
+	)
+
+	It("should not block the updates when the notifications are not received", func() {
+		q := NewJobQueue()
+		ch := q.Subscribe("ns/rp1")
+		Expect(q.Subscribe("ns/rp1")).To(Equal(ch))
+
+		for i := range int64(3) {
+			q.Update(job(github.WorkflowJobActionQueued, i, "ns/rp1"))
+		}
+		Expect(ch).To(Receive())
+		Expect(ch).NotTo(Receive())
+		Expect(q.CountQueuedJobs("ns/rp1")).To(Equal(3))
+	})
+
+	It("should stop notifying after unsubscribing", func() {
+		q := NewJobQueue()
+		ch := q.Subscribe("ns/rp1")
+		q.Unsubscribe("ns/rp1")
+
+		q.Update(job(github.WorkflowJobActionQueued, 1, "ns/rp1"))
+		Expect(ch).NotTo(Receive())
+		Expect(q.CountQueuedJobs("ns/rp1")).To(Equal(1))
+	})
+})
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,16 +20,25 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete;update
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+
+// busyPodDeletionCost is the pod deletion cost for busy runner pods.
+// The ReplicaSet controller prefers deleting pods with lower costs (default 0) when scaling down.
+const busyPodDeletionCost = "100"
 
 // RunnerManager manages runner pods and runners registered in GitHub.
 // It generates one goroutine for each RunnerPool CR to manage them.
@@ -41,21 +52,25 @@ type runnerManager struct {
 	log                 logr.Logger
 	k8sClient           client.Client
 	scheme              *runtime.Scheme
+	recorder            events.EventRecorder
 	githubClientFactory github.ClientFactory
 	runnerPodClient     runner.Client
+	jobQueue            JobQueue
 	interval            time.Duration
 	mu                  sync.Mutex
 	stopped             bool
 	processes           map[string]*manageProcess
 }
 
-func NewRunnerManager(log logr.Logger, k8sClient client.Client, scheme *runtime.Scheme, githubClientFactory github.ClientFactory, runnerPodClient runner.Client, interval time.Duration) RunnerManager {
+func NewRunnerManager(log logr.Logger, k8sClient client.Client, scheme *runtime.Scheme, recorder events.EventRecorder, githubClientFactory github.ClientFactory, runnerPodClient runner.Client, jobQueue JobQueue, interval time.Duration) RunnerManager {
 	return &runnerManager{
 		log:                 log.WithName("RunnerManager"),
 		k8sClient:           k8sClient,
 		scheme:              scheme,
+		recorder:            recorder,
 		githubClientFactory: githubClientFactory,
 		runnerPodClient:     runnerPodClient,
+		jobQueue:            jobQueue,
 		interval:            interval,
 		processes:           map[string]*manageProcess{},
 	}
@@ -79,8 +94,11 @@ func (m *runnerManager) StartOrUpdate(rp *meowsv1alpha1.RunnerPool, cred *github
 			m.log.WithValues("runnerpool", rpNamespacedName),
 			m.k8sClient,
 			m.scheme,
+			m.recorder,
 			githubClient,
+			cred,
 			m.runnerPodClient,
+			m.jobQueue,
 			m.interval,
 			rp,
 		)
@@ -91,7 +109,18 @@ func (m *runnerManager) StartOrUpdate(rp *meowsv1alpha1.RunnerPool, cred *github
 		m.processes[rpNamespacedName] = process
 		return nil
 	}
-	return m.processes[rpNamespacedName].update(rp)
+
+	process := m.processes[rpNamespacedName]
+	if !process.getGitHubCredential().Equal(cred) {
+		githubClient, err := m.githubClientFactory.New(cred)
+		if err != nil {
+			return fmt.Errorf("failed to create a github client; %w", err)
+		}
+		process.setGitHubClient(githubClient, cred)
+		process.log.Info("reloaded the github credential")
+		m.recorder.Eventf(process.rpRef, nil, corev1.EventTypeNormal, reasonUpdatedCredential, actionUpdateCredential, "Reloaded the GitHub credential")
+	}
+	return process.update(rp)
 }
 
 func (m *runnerManager) Stop(rp *meowsv1alpha1.RunnerPool) error {
@@ -124,16 +153,32 @@ type manageProcess struct {
 	log                   logr.Logger
 	k8sClient             client.Client
 	scheme                *runtime.Scheme
-	githubClient          github.Client
+	recorder              events.EventRecorder
+	githubClient          github.Client            // This field will be accessed from multiple goroutines. So use mutex to access.
+	githubCred            *github.ClientCredential // This field will be accessed from multiple goroutines. So use mutex to access.
 	runnerPodClient       runner.Client
 	slackAgentClient      *agent.Client
+	jobQueue              JobQueue
 	interval              time.Duration
 	rpNamespace           string
 	rpName                string
+	rpRef                 *corev1.ObjectReference
+	deploymentName        string
 	owner                 string
 	repo                  string
+	enterprise            string
 	replicas              int32 // This field will be accessed from multiple goroutines. So use mutex to access.
+	specReplicas          int32 // This field will be accessed from multiple goroutines. So use mutex to access.
 	maxRunnerPods         int32 // This field will be accessed from multiple goroutines. So use mutex to access.
+	autoscaling           bool  // This field will be accessed from multiple goroutines. So use mutex to access.
+	minReplicas           int32 // This field will be accessed from multiple goroutines. So use mutex to access.
+	pollQueuedJobs        bool  // This field will be accessed from multiple goroutines. So use mutex to access.
+	scaleDownWindow       time.Duration
+	pollingOptions        github.PollingOptions          // This field will be accessed from multiple goroutines. So use mutex to access.
+	schedules             []meowsv1alpha1.ScheduleConfig // This field will be accessed from multiple goroutines. So use mutex to access.
+	runnerGroup           string                         // This field will be accessed from multiple goroutines. So use mutex to access.
+	labels                []string                       // This field will be accessed from multiple goroutines. So use mutex to access.
+	createRunnerGroup     bool                           // This field will be accessed from multiple goroutines. So use mutex to access.
 	needSlackNotification bool
 	slackChannel          string
 	slackAgentServiceName string
@@ -142,17 +187,21 @@ type manageProcess struct {
 	denyDisruption        bool
 
 	// Update internally.
-	lastCheckTime   time.Time
-	env             *well.Environment
-	cancel          context.CancelFunc
-	prevRunnerNames []string
-	mu              sync.Mutex
-	deleteMetrics   func()
+	lastCheckTime      time.Time
+	env                *well.Environment
+	cancel             context.CancelFunc
+	prevRunnerNames    []string
+	recommendations    []recommendation
+	readyRunnerGroup   string
+	readyRunnerGroupID int64
+	mu                 sync.Mutex
+	deleteMetrics      func()
 }
 
-func newManageProcess(log logr.Logger, k8sClient client.Client, scheme *runtime.Scheme, githubClient github.Client, runnerPodClient runner.Client, interval time.Duration, rp *meowsv1alpha1.RunnerPool) (*manageProcess, error) {
+func newManageProcess(log logr.Logger, k8sClient client.Client, scheme *runtime.Scheme, recorder events.EventRecorder, githubClient github.Client, githubCred *github.ClientCredential, runnerPodClient runner.Client, jobQueue JobQueue, interval time.Duration, rp *meowsv1alpha1.RunnerPool) (*manageProcess, error) {
 	extendDuration, _ := time.ParseDuration(rp.Spec.Notification.ExtendDuration)
 	recreateDeadline, _ := time.ParseDuration(rp.Spec.RecreateDeadline)
+	scaleDownWindow, _ := time.ParseDuration(rp.Spec.Autoscaling.ScaleDownStabilizationWindow)
 
 	agentName := constants.DefaultSlackAgentServiceName
 	if rp.Spec.Notification.Slack.AgentServiceName != "" {
@@ -168,15 +217,31 @@ func newManageProcess(log logr.Logger, k8sClient client.Client, scheme *runtime.
 		log:                   log,
 		k8sClient:             k8sClient,
 		scheme:                scheme,
+		recorder:              recorder,
 		githubClient:          githubClient,
+		githubCred:            githubCred,
 		runnerPodClient:       runnerPodClient,
+		jobQueue:              jobQueue,
 		interval:              interval,
 		rpNamespace:           rp.Namespace,
 		rpName:                rp.Name,
+		rpRef:                 runnerPoolReference(rp),
+		deploymentName:        rp.GetRunnerDeploymentName(),
 		owner:                 rp.GetOwner(),
 		repo:                  rp.GetRepository(),
+		enterprise:            rp.Spec.Enterprise,
 		replicas:              rp.Spec.Replicas,
+		specReplicas:          rp.Spec.Replicas,
 		maxRunnerPods:         rp.Spec.MaxRunnerPods,
+		autoscaling:           rp.Spec.Autoscaling.Enable,
+		minReplicas:           rp.Spec.Autoscaling.MinReplicas,
+		pollQueuedJobs:        rp.Spec.Autoscaling.Polling,
+		scaleDownWindow:       scaleDownWindow,
+		pollingOptions:        pollingOptions(rp),
+		schedules:             rp.Spec.Schedules,
+		runnerGroup:           rp.Spec.RunnerGroup,
+		labels:                rp.Spec.Labels,
+		createRunnerGroup:     rp.Spec.CreateRunnerGroup,
 		slackAgentClient:      agentClient,
 		needSlackNotification: rp.Spec.Notification.Slack.Enable,
 		slackChannel:          rp.Spec.Notification.Slack.Channel,
@@ -190,14 +255,67 @@ func newManageProcess(log logr.Logger, k8sClient client.Client, scheme *runtime.
 			metrics.DeleteRunnerPoolMetrics(rpNamespacedName)
 		},
 	}
+	if process.autoscaling {
+		process.replicas, _ = scheduledReplicas(process.schedules, process.minReplicas, time.Now())
+	} else {
+		process.replicas, _ = scheduledReplicas(process.schedules, process.specReplicas, time.Now())
+	}
 	return process, nil
 }
 
+// pollingOptions returns the options to poll the queued jobs of the organization.
+// The zero values are replaced with the defaults by the GitHub client.
+func pollingOptions(rp *meowsv1alpha1.RunnerPool) github.PollingOptions {
+	window, _ := time.ParseDuration(rp.Spec.Autoscaling.PollingWindow)
+	return github.PollingOptions{
+		MaxRepositories: int(rp.Spec.Autoscaling.PollingRepositoryLimit),
+		Window:          window,
+	}
+}
+
+func (p *manageProcess) getGitHubClient() github.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.githubClient
+}
+
+func (p *manageProcess) getGitHubCredential() *github.ClientCredential {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.githubCred
+}
+
+// setGitHubClient replaces the GitHub client with the one created from the rotated credential.
+// The runner pods are not affected, because they do not use the credential.
+func (p *manageProcess) setGitHubClient(githubClient github.Client, cred *github.ClientCredential) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.githubClient = githubClient
+	p.githubCred = cred
+}
+
 func (p *manageProcess) update(rp *meowsv1alpha1.RunnerPool) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	p.replicas = rp.Spec.Replicas
 	p.maxRunnerPods = rp.Spec.MaxRunnerPods
+	p.autoscaling = rp.Spec.Autoscaling.Enable
+	p.minReplicas = rp.Spec.Autoscaling.MinReplicas
+	p.pollQueuedJobs = rp.Spec.Autoscaling.Polling
+	p.pollingOptions = pollingOptions(rp)
+	scaleDownWindow, _ := time.ParseDuration(rp.Spec.Autoscaling.ScaleDownStabilizationWindow)
+	p.scaleDownWindow = scaleDownWindow
+	p.specReplicas = rp.Spec.Replicas
+	p.schedules = rp.Spec.Schedules
+	p.runnerGroup = rp.Spec.RunnerGroup
+	p.labels = rp.Spec.Labels
+	p.createRunnerGroup = rp.Spec.CreateRunnerGroup
+	if p.autoscaling {
+		// The replicas will be updated by the next autoscaling.
+		minReplicas, _ := scheduledReplicas(p.schedules, p.minReplicas, time.Now())
+		p.replicas = clampReplicas(p.replicas, minReplicas, p.maxRunnerPods)
+	} else {
+		p.replicas, _ = scheduledReplicas(p.schedules, p.specReplicas, time.Now())
+	}
 	p.needSlackNotification = rp.Spec.Notification.Slack.Enable
 	p.slackChannel = rp.Spec.Notification.Slack.Channel
 
@@ -258,6 +376,10 @@ func (p *manageProcess) run(ctx context.Context) {
 	ticker := time.NewTicker(p.interval)
 	defer ticker.Stop()
 
+	// Run immediately when a job for this pool is queued or finished, so that the autoscaling can follow bursts.
+	jobUpdated := p.jobQueue.Subscribe(p.rpNamespacedName())
+	defer p.jobQueue.Unsubscribe(p.rpNamespacedName())
+
 	p.log.Info("start a runner manager process")
 	for {
 		select {
@@ -265,10 +387,11 @@ func (p *manageProcess) run(ctx context.Context) {
 			p.log.Info("stop a runner manager process")
 			return
 		case <-ticker.C:
-			err := p.runOnce(ctx)
-			if err != nil {
-				p.log.Error(err, "failed to run a runner manager process")
-			}
+		case <-jobUpdated:
+		}
+		err := p.runOnce(ctx)
+		if err != nil {
+			p.log.Error(err, "failed to run a runner manager process")
 		}
 	}
 }
@@ -278,13 +401,25 @@ func (p *manageProcess) runOnce(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	runnerGroup, err := p.ensureRunnerGroup(ctx)
+	if err != nil {
+		// Continue to maintain the existing runners, though new runners cannot be registered.
+		p.log.Error(err, "runner group is not ready")
+	}
 	runnerList, err := p.fetchRunners(ctx)
+	if err != nil {
+		updateErr := p.updateStatus(ctx, func(rp *meowsv1alpha1.RunnerPool) {
+			setCondition(rp, meowsv1alpha1.ConditionGitHubReachable, metav1.ConditionFalse, reasonListRunnersFailed, err.Error())
+		})
+		return errors.Join(err, updateErr)
+	}
+	err = p.scaleRunnerPods(ctx, runnerList, podList)
 	if err != nil {
 		return err
 	}
 	p.updateMetrics(podList, runnerList)
 
-	err = p.maintainRunnerPods(ctx, runnerList, podList)
+	numDebuggingPods, err := p.maintainRunnerPods(ctx, runnerList, podList)
 	if err != nil {
 		return err
 	}
@@ -293,7 +428,82 @@ func (p *manageProcess) runOnce(ctx context.Context) error {
 		return err
 	}
 
-	return nil
+	return p.updateStatus(ctx, func(rp *meowsv1alpha1.RunnerPool) {
+		setCondition(rp, meowsv1alpha1.ConditionGitHubReachable, metav1.ConditionTrue, reasonListRunnersSucceeded, "")
+		rp.Status.OnlineRunners, rp.Status.BusyRunners, rp.Status.OfflineRunners = countRunners(runnerList)
+		rp.Status.DebuggingRunners = numDebuggingPods
+		if runnerGroup == "" {
+			meta.RemoveStatusCondition(&rp.Status.Conditions, meowsv1alpha1.ConditionRunnerGroupReady)
+		}
+	})
+}
+
+// ensureRunnerGroup checks that the runner group exists in the organization, and creates it if allowed.
+// It returns the name of the runner group, which is empty if the runner group is not specified.
+// Once the runner group is found, it is not checked again until the runner group is changed
+// or GitHub does not find it when registering a runner.
+func (p *manageProcess) ensureRunnerGroup(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	name := p.runnerGroup
+	create := p.createRunnerGroup
+	p.mu.Unlock()
+
+	if name == "" || name == p.readyRunnerGroup {
+		return name, nil
+	}
+
+	setNotReady := func(reason string, err error) error {
+		updateErr := p.updateStatus(ctx, func(rp *meowsv1alpha1.RunnerPool) {
+			setCondition(rp, meowsv1alpha1.ConditionRunnerGroupReady, metav1.ConditionFalse, reason, err.Error())
+		})
+		return errors.Join(err, updateErr)
+	}
+
+	groups, err := p.getGitHubClient().ListRunnerGroups(ctx, p.owner)
+	if err != nil {
+		return name, setNotReady(reasonListRunnerGroupsFailed, err)
+	}
+
+	reason := reasonRunnerGroupFound
+	i := slices.IndexFunc(groups, func(g *github.RunnerGroup) bool { return g.Name == name })
+	var group *github.RunnerGroup
+	if i >= 0 {
+		group = groups[i]
+	} else {
+		if !create {
+			err := fmt.Errorf("runner group %s is not found in organization %s", name, p.owner)
+			p.recorder.Eventf(p.rpRef, nil, corev1.EventTypeWarning, reasonRunnerGroupNotFound, actionCheckRunnerGroup, "%s", err.Error())
+			return name, setNotReady(reasonRunnerGroupNotFound, err)
+		}
+		group, err = p.getGitHubClient().CreateRunnerGroup(ctx, p.owner, name)
+		if err != nil {
+			p.recorder.Eventf(p.rpRef, nil, corev1.EventTypeWarning, reasonCreateRunnerGroupFailed, actionCreateRunnerGroup, "failed to create runner group %s: %v", name, err)
+			return name, setNotReady(reasonCreateRunnerGroupFailed, err)
+		}
+		p.log.Info("created runner group", "runnerGroup", name)
+		p.recorder.Eventf(p.rpRef, nil, corev1.EventTypeNormal, reasonCreatedRunnerGroup, actionCreateRunnerGroup, "created runner group %s", name)
+		reason = reasonCreatedRunnerGroup
+	}
+
+	p.readyRunnerGroup = name
+	p.readyRunnerGroupID = group.ID
+	return name, p.updateStatus(ctx, func(rp *meowsv1alpha1.RunnerPool) {
+		setCondition(rp, meowsv1alpha1.ConditionRunnerGroupReady, metav1.ConditionTrue, reason, "")
+	})
+}
+
+func countRunners(runnerList []*github.Runner) (online, busy, offline int32) {
+	for _, r := range runnerList {
+		if !r.Online {
+			offline++
+			continue
+		}
+		online++
+		if r.Busy {
+			busy++
+		}
+	}
+	return online, busy, offline
 }
 
 func (p *manageProcess) fetchRunnerPods(ctx context.Context) (*corev1.PodList, error) {
@@ -323,7 +533,7 @@ func (p *manageProcess) fetchRunnerPods(ctx context.Context) (*corev1.PodList, e
 }
 
 func (p *manageProcess) fetchRunners(ctx context.Context) ([]*github.Runner, error) {
-	runnerList, err := p.githubClient.ListRunners(ctx, p.owner, p.repo, []string{p.rpNamespacedName()})
+	runnerList, err := p.listRunners(ctx)
 	if err != nil {
 		p.log.Error(err, "failed to list runners")
 		return nil, err
@@ -331,6 +541,28 @@ func (p *manageProcess) fetchRunners(ctx context.Context) ([]*github.Runner, err
 	return runnerList, nil
 }
 
+// listRunners lists the runners of the RunnerPool in the repository, the organization or the enterprise.
+func (p *manageProcess) listRunners(ctx context.Context) ([]*github.Runner, error) {
+	if p.enterprise != "" {
+		return p.getGitHubClient().ListEnterpriseRunners(ctx, p.enterprise, []string{p.rpNamespacedName()})
+	}
+	return p.getGitHubClient().ListRunners(ctx, p.owner, p.repo, []string{p.rpNamespacedName()})
+}
+
+func (p *manageProcess) removeRunner(ctx context.Context, runnerID int64) error {
+	if p.enterprise != "" {
+		return p.getGitHubClient().RemoveEnterpriseRunner(ctx, p.enterprise, runnerID)
+	}
+	return p.getGitHubClient().RemoveRunner(ctx, p.owner, p.repo, runnerID)
+}
+
+func (p *manageProcess) generateJITConfig(ctx context.Context, name string, runnerGroupID int64, labels []string) (string, error) {
+	if p.enterprise != "" {
+		return p.getGitHubClient().GenerateEnterpriseJITConfig(ctx, p.enterprise, name, runnerGroupID, labels)
+	}
+	return p.getGitHubClient().GenerateJITConfig(ctx, p.owner, p.repo, name, runnerGroupID, labels)
+}
+
 func (p *manageProcess) updateMetrics(podList *corev1.PodList, runnerList []*github.Runner) {
 	p.mu.Lock()
 	metrics.UpdateRunnerPoolMetrics(p.rpNamespacedName(), int(p.replicas))
@@ -349,6 +581,215 @@ func (p *manageProcess) updateMetrics(podList *corev1.PodList, runnerList []*git
 	p.prevRunnerNames = currentRunnerNames
 }
 
+// Reasons and actions of the events recorded by the runner manager.
+const (
+	reasonDeletedStalePod          = "DeletedStalePod"
+	reasonDeletedFailedPod         = "DeletedFailedPod"
+	reasonDeletedDebuggingPod      = "DeletedDebuggingPod"
+	reasonRecreateDeadlineExceeded = "RecreateDeadlineExceeded"
+	reasonDeleteFailed             = "DeleteFailed"
+	reasonProtectedBusyPod         = "ProtectedBusyPod"
+	reasonProtectFailed            = "ProtectFailed"
+	reasonUnlinkedBusyPod          = "UnlinkedBusyPod"
+	reasonUnlinkFailed             = "UnlinkFailed"
+	reasonRemovedOfflineRunner     = "RemovedOfflineRunner"
+	reasonRemoveRunnerFailed       = "RemoveRunnerFailed"
+	reasonCreatedRunnerGroup       = "CreatedRunnerGroup"
+	reasonCreateRunnerGroupFailed  = "CreateRunnerGroupFailed"
+	reasonRunnerGroupNotFound      = "RunnerGroupNotFound"
+	reasonDeliveredJITConfig       = "DeliveredJITConfig"
+	reasonDeliverJITConfigFailed   = "DeliverJITConfigFailed"
+	reasonJITConfigConflict        = "JITConfigConflict"
+	reasonUpdatedCredential        = "UpdatedCredential"
+
+	actionDelete            = "Delete"
+	actionProtect           = "Protect"
+	actionUnlink            = "Unlink"
+	actionRemoveRunner      = "RemoveRunner"
+	actionCheckRunnerGroup  = "CheckRunnerGroup"
+	actionCreateRunnerGroup = "CreateRunnerGroup"
+	actionDeliverJITConfig  = "DeliverJITConfig"
+	actionUpdateCredential  = "UpdateCredential"
+)
+
+// jitRunnerDefaultLabel is the label given to the runners registered with just-in-time configurations.
+// Unlike config.sh, the API does not add it by default.
+const jitRunnerDefaultLabel = "self-hosted"
+
+// Reasons of the RunnerPool conditions set by the runner manager.
+const (
+	reasonListRunnersSucceeded   = "ListRunnersSucceeded"
+	reasonListRunnersFailed      = "ListRunnersFailed"
+	reasonRunnerGroupFound       = "RunnerGroupFound"
+	reasonListRunnerGroupsFailed = "ListRunnerGroupsFailed"
+	reasonAllRepositoriesPolled  = "AllRepositoriesPolled"
+	reasonRepositoriesTruncated  = "RepositoriesTruncated"
+)
+
+// recommendation is the number of replicas decided by the autoscaling at a time.
+type recommendation struct {
+	timestamp time.Time
+	replicas  int32
+}
+
+// scaleRunnerPods updates the replicas of the Deployment according to the active schedule and the number of queued jobs.
+// Busy runner pods that are still controlled by the Deployment are added to the desired replicas,
+// so that they are not counted as the runner pods to accept a new job.
+func (p *manageProcess) scaleRunnerPods(ctx context.Context, runnerList []*github.Runner, podList *corev1.PodList) error {
+	p.mu.Lock()
+	autoscaling := p.autoscaling
+	specReplicas := p.specReplicas
+	minReplicas := p.minReplicas
+	maxRunnerPods := p.maxRunnerPods
+	pollQueuedJobs := p.pollQueuedJobs
+	pollingOptions := p.pollingOptions
+	scaleDownWindow := p.scaleDownWindow
+	schedules := p.schedules
+	p.mu.Unlock()
+	pollOrganization := autoscaling && pollQueuedJobs && p.repo == ""
+	if !autoscaling {
+		p.recommendations = nil
+		if len(schedules) == 0 {
+			return p.updateStatus(ctx, func(rp *meowsv1alpha1.RunnerPool) {
+				meta.RemoveStatusCondition(&rp.Status.Conditions, meowsv1alpha1.ConditionQueuedJobsPolled)
+			})
+		}
+	}
+
+	now := time.Now()
+	var desired int32
+	var activeSchedule string
+	var truncated bool
+	var keysAndValues []any
+	if autoscaling {
+		minReplicas, activeSchedule = scheduledReplicas(schedules, minReplicas, now)
+
+		var numBusyPods int32
+		for i := range podList.Items {
+			po := &podList.Items[i]
+			if _, ok := po.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; ok && runnerBusy(runnerList, po.Name) {
+				numBusyPods++
+			}
+		}
+		var numQueuedJobs int32
+		var err error
+		numQueuedJobs, truncated, err = p.countQueuedJobs(ctx, pollQueuedJobs, pollingOptions)
+		if err != nil {
+			return err
+		}
+		recommended := clampReplicas(numQueuedJobs+numBusyPods, minReplicas, maxRunnerPods)
+		desired = clampReplicas(p.stabilizeReplicas(now, recommended, scaleDownWindow), minReplicas, maxRunnerPods)
+		keysAndValues = []any{"queuedJobs", numQueuedJobs, "busyPods", numBusyPods, "recommended", recommended}
+	} else {
+		desired, activeSchedule = scheduledReplicas(schedules, specReplicas, now)
+	}
+
+	d := &appsv1.Deployment{}
+	err := p.k8sClient.Get(ctx, types.NamespacedName{Namespace: p.rpNamespace, Name: p.deploymentName}, d)
+	if err != nil {
+		p.log.Error(err, "failed to get deployment")
+		return err
+	}
+	current := ptr.Deref(d.Spec.Replicas, 1)
+	if current != desired {
+		patch := client.MergeFrom(d.DeepCopy())
+		d.Spec.Replicas = ptr.To(desired)
+		err := p.k8sClient.Patch(ctx, d, patch)
+		if err != nil {
+			p.log.Error(err, "failed to scale deployment")
+			return err
+		}
+		keysAndValues = append(keysAndValues, "activeSchedule", activeSchedule, "from", current, "to", desired)
+		p.log.Info("scaled deployment", keysAndValues...)
+	}
+
+	p.mu.Lock()
+	p.replicas = desired
+	p.mu.Unlock()
+
+	return p.updateStatus(ctx, func(rp *meowsv1alpha1.RunnerPool) {
+		if autoscaling {
+			rp.Status.DesiredReplicas = ptr.To(desired)
+		}
+		rp.Status.ActiveSchedule = activeSchedule
+		switch {
+		case !pollOrganization:
+			meta.RemoveStatusCondition(&rp.Status.Conditions, meowsv1alpha1.ConditionQueuedJobsPolled)
+		case truncated:
+			setCondition(rp, meowsv1alpha1.ConditionQueuedJobsPolled, metav1.ConditionFalse, reasonRepositoriesTruncated,
+				"some repositories pushed within the polling window are not polled; increase pollingRepositoryLimit to count their queued jobs")
+		default:
+			setCondition(rp, meowsv1alpha1.ConditionQueuedJobsPolled, metav1.ConditionTrue, reasonAllRepositoriesPolled,
+				"all the repositories pushed within the polling window are polled")
+		}
+	})
+}
+
+// countQueuedJobs counts the queued jobs for the runner pool.
+// It also returns true if some repositories of the organization are not polled because of the limit.
+func (p *manageProcess) countQueuedJobs(ctx context.Context, pollQueuedJobs bool, opts github.PollingOptions) (int32, bool, error) {
+	if !pollQueuedJobs {
+		return int32(p.jobQueue.CountQueuedJobs(p.rpNamespacedName())), false, nil
+	}
+
+	jobs, truncated, err := p.getGitHubClient().ListQueuedJobs(ctx, p.owner, p.repo, []string{p.rpNamespacedName()}, opts)
+	if err != nil {
+		p.log.Error(err, "failed to list queued jobs")
+		return 0, false, err
+	}
+	if truncated {
+		p.log.Info("some repositories pushed within the polling window are not polled because of pollingRepositoryLimit")
+	}
+	return int32(len(jobs)), truncated, nil
+}
+
+// stabilizeReplicas records the recommendation and returns the highest one within the window.
+// So the runner pods are scaled up immediately, but scaled down only after the window passes.
+func (p *manageProcess) stabilizeReplicas(now time.Time, recommended int32, window time.Duration) int32 {
+	desired := recommended
+	recommendations := []recommendation{{timestamp: now, replicas: recommended}}
+	for _, r := range p.recommendations {
+		if now.Sub(r.timestamp) >= window {
+			continue
+		}
+		recommendations = append(recommendations, r)
+		desired = max(desired, r.replicas)
+	}
+	p.recommendations = recommendations
+	return desired
+}
+
+// updateStatus applies f to the latest runnerpool and patches its status if changed.
+// The conditions should be set with setCondition to record the observed generation.
+func (p *manageProcess) updateStatus(ctx context.Context, f func(*meowsv1alpha1.RunnerPool)) error {
+	rp := &meowsv1alpha1.RunnerPool{}
+	err := p.k8sClient.Get(ctx, types.NamespacedName{Namespace: p.rpNamespace, Name: p.rpName}, rp)
+	if apierrors.IsNotFound(err) {
+		// The process will be stopped soon.
+		return nil
+	}
+	if err != nil {
+		p.log.Error(err, "failed to get runnerpool")
+		return err
+	}
+
+	orig := rp.DeepCopy()
+	f(rp)
+	if equality.Semantic.DeepEqual(orig.Status, rp.Status) {
+		return nil
+	}
+	err = p.k8sClient.Status().Patch(ctx, rp, client.MergeFrom(orig))
+	if err != nil {
+		p.log.Error(err, "failed to update status")
+		return err
+	}
+	return nil
+}
+
+func clampReplicas(replicas, minReplicas, maxReplicas int32) int32 {
+	return max(minReplicas, min(replicas, maxReplicas))
+}
+
 func difference(prev, current []string) []string {
 	set := map[string]bool{}
 	for _, val := range current {
@@ -364,12 +805,35 @@ func difference(prev, current []string) []string {
 	return ret
 }
 
-func (p *manageProcess) maintainRunnerPods(ctx context.Context, runnerList []*github.Runner, podList *corev1.PodList) error {
+// resolveJobURL sets the URL of the job run by the runner pod to the job info.
+func (p *manageProcess) resolveJobURL(ctx context.Context, po *corev1.Pod, info *runner.JobInfo) error {
+	if info == nil || info.JobURL != "" {
+		return nil
+	}
+	owner, repo, ok := strings.Cut(info.Repository, "/")
+	if !ok {
+		return fmt.Errorf("invalid repository %q", info.Repository)
+	}
+	runnerName := info.RunnerName
+	if runnerName == "" {
+		runnerName = po.Name
+	}
+	u, err := p.getGitHubClient().GetJobURL(ctx, owner, repo, int64(info.RunID), int64(info.RunAttempt), runnerName)
+	if err != nil {
+		return err
+	}
+	info.JobURL = u
+	return nil
+}
+
+// maintainRunnerPods deletes or unlinks the runner pods according to their states,
+// and returns the number of the runner pods in the debugging state.
+func (p *manageProcess) maintainRunnerPods(ctx context.Context, runnerList []*github.Runner, podList *corev1.PodList) (int32, error) {
 	now := time.Now().UTC()
 	lastCheckTime := p.lastCheckTime
 	p.lastCheckTime = now
 
-	var numUnlabeledPods int32
+	var numUnlabeledPods, numDebuggingPods int32
 	for i := range podList.Items {
 		po := &podList.Items[i]
 		if _, ok := po.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; !ok {
@@ -393,23 +857,70 @@ func (p *manageProcess) maintainRunnerPods(ctx context.Context, runnerList []*gi
 			continue
 		}
 
-		status, err := p.runnerPodClient.GetStatus(ctx, po.Status.PodIP)
+		status, err := p.runnerPodClient.GetStatus(ctx, po)
 		if err != nil {
 			log.Error(err, "failed to get status, skipped maintaining runner pod")
 			continue
 		}
 
+		if status.JITConfigRequired {
+			err := p.deliverJITConfig(ctx, po, runnerList)
+			if errors.Is(err, runner.ErrJITConfigConflict) {
+				// The pod accepted a configuration which the controller did not deliver, so it cannot be trusted.
+				log.Error(err, "jit config is delivered by another client")
+				p.recordPodEvent(po, corev1.EventTypeWarning, reasonJITConfigConflict, actionDeliverJITConfig, "jit config was delivered by another client: %v", err)
+				err = p.k8sClient.Delete(ctx, po)
+				if err != nil && !apierrors.IsNotFound(err) {
+					log.Error(err, "failed to delete runner pod with unknown jit config")
+					p.recordPodEvent(po, corev1.EventTypeWarning, reasonDeleteFailed, actionDelete, "failed to delete runner pod with unknown jit config: %v", err)
+				} else {
+					log.Info("deleted runner pod with unknown jit config")
+				}
+				continue
+			}
+			if err != nil {
+				log.Error(err, "failed to deliver jit config")
+				p.recordPodEvent(po, corev1.EventTypeWarning, reasonDeliverJITConfigFailed, actionDeliverJITConfig, "failed to deliver jit config: %v", err)
+			} else {
+				log.Info("delivered jit config")
+				p.recordPodEvent(po, corev1.EventTypeNormal, reasonDeliveredJITConfig, actionDeliverJITConfig, "delivered jit config")
+			}
+			continue
+		}
+
 		if status.State == constants.RunnerPodStateStale {
 			err = p.k8sClient.Delete(ctx, po)
 			if err != nil && !apierrors.IsNotFound(err) {
 				log.Error(err, "failed to delete stale runner pod")
+				p.recordPodEvent(po, corev1.EventTypeWarning, reasonDeleteFailed, actionDelete, "failed to delete stale runner pod: %v", err)
 			} else {
 				log.Info("deleted stale runner pod")
+				p.recordPodEvent(po, corev1.EventTypeNormal, reasonDeletedStalePod, actionDelete, "deleted stale runner pod")
+			}
+			continue
+		}
+
+		if status.State == constants.RunnerPodStateFailed {
+			// The runner listener exceeded the max retries, so recreate the pod.
+			err = p.k8sClient.Delete(ctx, po)
+			if err != nil && !apierrors.IsNotFound(err) {
+				log.Error(err, "failed to delete failed runner pod")
+				p.recordPodEvent(po, corev1.EventTypeWarning, reasonDeleteFailed, actionDelete, "failed to delete failed runner pod: %v", err)
+			} else {
+				log.Info("deleted failed runner pod", "listener_attempts", status.ListenerAttempts)
+				p.recordPodEvent(po, corev1.EventTypeNormal, reasonDeletedFailedPod, actionDelete, "deleted failed runner pod after %d listener attempts", status.ListenerAttempts)
 			}
 			continue
 		}
 
+		if status.State == constants.RunnerPodStateTerminating {
+			// The pod is being deleted, and waits for the running job to finish.
+			log.Info("skip because the runner pod is terminating")
+			continue
+		}
+
 		if status.State == constants.RunnerPodStateDebugging {
+			numDebuggingPods++
 			needExtend := status.Extend != nil && *status.Extend && extendDuration != 0
 
 			if needNotification && status.FinishedAt.After(lastCheckTime) {
@@ -417,7 +928,10 @@ func (p *manageProcess) maintainRunnerPods(ctx context.Context, runnerList []*gi
 				if status.SlackChannel != "" {
 					ch = status.SlackChannel
 				}
-				err := p.slackAgentClient.PostResult(ctx, ch, status.Result, needExtend, po.Namespace, po.Name, status.JobInfo)
+				if err := p.resolveJobURL(ctx, po, status.JobInfo); err != nil {
+					log.Error(err, "failed to resolve job url, the url of the workflow run is notified instead")
+				}
+				err := p.slackAgentClient.PostResult(ctx, ch, status.Result, status.FailedSteps, needExtend, po.Namespace, po.Name, status.JobInfo)
 				if err != nil {
 					log.Error(err, "failed to send a notification to slack-agent")
 				} else {
@@ -438,14 +952,16 @@ func (p *manageProcess) maintainRunnerPods(ctx context.Context, runnerList []*gi
 				err := p.k8sClient.Delete(ctx, po)
 				if err != nil && !apierrors.IsNotFound(err) {
 					log.Error(err, "failed to delete debugging runner pod")
+					p.recordPodEvent(po, corev1.EventTypeWarning, reasonDeleteFailed, actionDelete, "failed to delete debugging runner pod: %v", err)
 				} else {
 					log.Info("deleted debugging runner pod")
+					p.recordPodEvent(po, corev1.EventTypeNormal, reasonDeletedDebuggingPod, actionDelete, "deleted debugging runner pod after the job finished with %s", status.Result)
 				}
 				continue
 			}
 
 			if status.DeletionTime == nil && status.FinishedAt != nil && status.FinishedAt.After(lastCheckTime) {
-				err := p.runnerPodClient.PutDeletionTime(ctx, po.Status.PodIP, status.FinishedAt.Add(extendDuration))
+				err := p.runnerPodClient.PutDeletionTime(ctx, po, status.FinishedAt.Add(extendDuration))
 				if err != nil {
 					log.Error(err, "failed to set deletion time")
 				}
@@ -457,8 +973,10 @@ func (p *manageProcess) maintainRunnerPods(ctx context.Context, runnerList []*gi
 			err = p.k8sClient.Delete(ctx, po)
 			if err != nil && !apierrors.IsNotFound(err) {
 				log.Error(err, "failed to delete runner pod that exceeded recreate deadline")
+				p.recordPodEvent(po, corev1.EventTypeWarning, reasonDeleteFailed, actionDelete, "failed to delete runner pod that exceeded recreate deadline: %v", err)
 			} else {
 				log.Info("deleted runner pod that exceeded recreate deadline")
+				p.recordPodEvent(po, corev1.EventTypeNormal, reasonRecreateDeadlineExceeded, actionDelete, "deleted runner pod that exceeded recreate deadline %s", recreateDeadline)
 			}
 			continue
 		}
@@ -485,9 +1003,11 @@ func (p *manageProcess) maintainRunnerPods(ctx context.Context, runnerList []*gi
 					})
 					if err != nil {
 						log.Error(err, "failed to create or update protection pdb")
+						p.recordPodEvent(po, corev1.EventTypeWarning, reasonProtectFailed, actionProtect, "failed to create or update protection pdb: %v", err)
 						continue
 					}
 					log.Info("created or updated protection pdb")
+					p.recordPodEvent(po, corev1.EventTypeNormal, reasonProtectedBusyPod, actionProtect, "created or updated protection pdb %s", pdb.Name)
 					po.Labels[constants.RunnerPodName] = po.Name
 					err = p.k8sClient.Update(ctx, po)
 					if err != nil {
@@ -498,6 +1018,20 @@ func (p *manageProcess) maintainRunnerPods(ctx context.Context, runnerList []*gi
 				}
 			}
 
+			if po.Annotations[corev1.PodDeletionCost] != busyPodDeletionCost {
+				// When the Deployment is scaled down by autoscaling or schedules, let the ReplicaSet delete idle pods rather than the busy ones.
+				if po.Annotations == nil {
+					po.Annotations = map[string]string{}
+				}
+				po.Annotations[corev1.PodDeletionCost] = busyPodDeletionCost
+				err = p.k8sClient.Update(ctx, po)
+				if err != nil {
+					log.Error(err, "failed to annotate runner pod with deletion cost")
+					continue
+				}
+				log.Info("annotated runner pod with deletion cost")
+			}
+
 			if numRemovablePods <= 0 {
 				continue
 			}
@@ -508,13 +1042,59 @@ func (p *manageProcess) maintainRunnerPods(ctx context.Context, runnerList []*gi
 			err = p.k8sClient.Update(ctx, po)
 			if err != nil {
 				log.Error(err, "failed to unlink (update) runner pod")
+				p.recordPodEvent(po, corev1.EventTypeWarning, reasonUnlinkFailed, actionUnlink, "failed to unlink runner pod from deployment: %v", err)
 				continue
 			}
 			numRemovablePods--
 			log.Info("unlinked (updated) runner pod")
+			p.recordPodEvent(po, corev1.EventTypeNormal, reasonUnlinkedBusyPod, actionUnlink, "unlinked busy runner pod from deployment")
 		}
 	}
-	return nil
+	return numDebuggingPods, nil
+}
+
+// deliverJITConfig registers a runner for the pod and sends the just-in-time runner configuration to the pod.
+func (p *manageProcess) deliverJITConfig(ctx context.Context, po *corev1.Pod, runnerList []*github.Runner) error {
+	p.mu.Lock()
+	runnerGroup := p.runnerGroup
+	labels := append([]string{jitRunnerDefaultLabel, p.rpNamespacedName()}, p.labels...)
+	p.mu.Unlock()
+
+	runnerGroupID := github.DefaultRunnerGroupID
+	if runnerGroup != "" {
+		if runnerGroup != p.readyRunnerGroup {
+			return fmt.Errorf("runner group %s is not ready", runnerGroup)
+		}
+		runnerGroupID = p.readyRunnerGroupID
+	}
+
+	// The runner remains if the config was generated but failed to be delivered. It has never run a job.
+	for _, r := range runnerList {
+		if r.Name != po.Name {
+			continue
+		}
+		if err := p.removeRunner(ctx, r.ID); err != nil {
+			return fmt.Errorf("failed to remove undelivered runner; %w", err)
+		}
+	}
+
+	config, err := p.generateJITConfig(ctx, po.Name, runnerGroupID, labels)
+	if err != nil {
+		if runnerGroup != "" && github.IsNotFound(err) {
+			// The runner group may have been deleted, so check it again in the next loop.
+			p.readyRunnerGroup = ""
+			p.readyRunnerGroupID = 0
+		}
+		return err
+	}
+	return p.runnerPodClient.PutJITConfig(ctx, po, config)
+}
+
+// recordPodEvent records an event on the runner pod and the RunnerPool.
+func (p *manageProcess) recordPodEvent(po *corev1.Pod, eventtype, reason, action, note string, args ...any) {
+	note = fmt.Sprintf(note, args...)
+	p.recorder.Eventf(po, p.rpRef, eventtype, reason, action, "%s", note)
+	p.recorder.Eventf(p.rpRef, po, eventtype, reason, action, "%s: %s", po.Name, note)
 }
 
 func runnerBusy(runnerList []*github.Runner, name string) bool {
@@ -531,24 +1111,27 @@ func (p *manageProcess) deleteOfflineRunners(ctx context.Context, runnerList []*
 		if runner.Online || podExists(runner.Name, podList) {
 			continue
 		}
-		err := p.githubClient.RemoveRunner(ctx, p.owner, p.repo, runner.ID)
+		err := p.removeRunner(ctx, runner.ID)
 		if err != nil {
 			p.log.Error(err, "failed to remove runner", "runner", runner.Name, "runner_id", runner.ID)
+			p.recorder.Eventf(p.rpRef, nil, corev1.EventTypeWarning, reasonRemoveRunnerFailed, actionRemoveRunner, "failed to remove offline runner %s: %v", runner.Name, err)
 			return err
 		}
 		p.log.Info("removed runner", "runner", runner.Name, "runner_id", runner.ID)
+		p.recorder.Eventf(p.rpRef, nil, corev1.EventTypeNormal, reasonRemovedOfflineRunner, actionRemoveRunner, "removed offline runner %s whose pod does not exist", runner.Name)
 	}
 	return nil
 }
 
 func (p *manageProcess) deleteAllRunners(ctx context.Context) error {
-	runnerList, err := p.githubClient.ListRunners(ctx, p.owner, p.repo, []string{p.rpNamespacedName()})
+	// Do not miss the runners registered after the cached listing.
+	runnerList, err := p.listRunners(github.WithoutCache(ctx))
 	if err != nil {
 		p.log.Error(err, "failed to list runners")
 		return err
 	}
 	for _, runner := range runnerList {
-		err := p.githubClient.RemoveRunner(ctx, p.owner, p.repo, runner.ID)
+		err := p.removeRunner(ctx, runner.ID)
 		if err != nil {
 			p.log.Error(err, "failed to remove runner", "runner", runner.Name, "runner_id", runner.ID)
 			return err
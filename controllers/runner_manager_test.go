@@ -17,10 +17,16 @@ import (
 	. "github.com/onsi/gomega/gstruct"
 	gomegatypes "github.com/onsi/gomega/types"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -69,6 +75,7 @@ var _ = Describe("RunnerManager", func() {
 					{spec: makePod("pod2", "test-ns1", "rp2"), ip: "10.0.0.2", state: "stale"},                                                       // state is stale.
 					{spec: makePod("pod3", "test-ns2", "rp3"), ip: "10.0.0.3", state: "running"},                                                     // recreate deadline is exceeded and runner is not exist.
 					{spec: makePod("pod4", "test-ns2", "rp3"), ip: "10.0.0.4", state: "running"},                                                     // recreate deadline is exceeded and runner is not busy.
+					{spec: makePod("pod5", "test-ns1", "rp1"), ip: "10.0.0.5", state: "failed"},                                                      // state is failed.
 				},
 				inputRunners: map[string][]*github.Runner{
 					"owner/repo2": {
@@ -192,7 +199,7 @@ var _ = Describe("RunnerManager", func() {
 			By("preparing fake clients")
 			runnerPodClient := runner.NewFakeClient()
 			githubClientFactory := github.NewFakeClientFactory()
-			runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, githubClientFactory, runnerPodClient, time.Second)
+			runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, &events.FakeRecorder{}, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
 
 			By("preparing pods and runners")
 			for _, inputPod := range tt.inputPods {
@@ -271,7 +278,8 @@ var _ = Describe("RunnerManager", func() {
 		By("preparing fake clients")
 		runnerPodClient := runner.NewFakeClient()
 		githubClientFactory := github.NewFakeClientFactory()
-		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, githubClientFactory, runnerPodClient, time.Second)
+		recorder := events.NewFakeRecorder(100)
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, recorder, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
 
 		By("starting runnerpool manager")
 		rp := makeRunnerPoolWithRepository("rp1", "test-ns1", "owner/repo1")
@@ -336,6 +344,16 @@ var _ = Describe("RunnerManager", func() {
 		Expect(labeledPodNames).To(HaveLen(len(inputPods) - int(rp.Spec.MaxRunnerPods-rp.Spec.Replicas)))
 		Expect(labeledPodNames).To(ContainElement("pod1"))
 
+		By("checking events")
+		var recordedEvents []string
+		for len(recorder.Events) > 0 {
+			recordedEvents = append(recordedEvents, <-recorder.Events)
+		}
+		// Each event is recorded on both the pod and the runnerpool.
+		Expect(recordedEvents).To(HaveLen(2 * int(rp.Spec.MaxRunnerPods-rp.Spec.Replicas)))
+		Expect(recordedEvents).To(HaveEach(HavePrefix("Normal UnlinkedBusyPod ")))
+		Expect(recordedEvents).To(ContainElement("Normal UnlinkedBusyPod " + unlabeledPod.Name + ": unlinked busy runner pod from deployment"))
+
 		By("deleting one of the unlabeled pods")
 		Expect(k8sClient.Delete(ctx, unlabeledPod)).To(Succeed())
 		time.Sleep(2 * time.Second)
@@ -383,11 +401,569 @@ var _ = Describe("RunnerManager", func() {
 		Expect(runnerManager.Stop(rp)).To(Succeed())
 	})
 
+	It("should scale the deployment according to queued jobs", func() {
+		By("preparing fake clients")
+		runnerPodClient := runner.NewFakeClient()
+		githubClientFactory := github.NewFakeClientFactory()
+		jobQueue := NewJobQueue()
+		// Use a long interval to check that the process runs when the jobs are updated.
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, &events.FakeRecorder{}, githubClientFactory, runnerPodClient, jobQueue, time.Hour)
+
+		By("creating a deployment")
+		labels := map[string]string{"app": "rp1"}
+		d := &appsv1.Deployment{}
+		d.Name = "rp1"
+		d.Namespace = "test-ns1"
+		d.Spec.Replicas = ptr.To[int32](1)
+		d.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+		d.Spec.Template.Labels = labels
+		d.Spec.Template.Spec.Containers = []corev1.Container{{Name: "runner", Image: "sample:latest"}}
+		Expect(k8sClient.Create(ctx, d)).To(Succeed())
+
+		By("starting runnerpool manager")
+		rp := makeRunnerPoolWithRepository("rp1", "test-ns1", "owner/repo1")
+		rp.Spec.MaxRunnerPods = 3
+		rp.Spec.Autoscaling.Enable = true
+		rp.Spec.Autoscaling.MinReplicas = 1
+		rp.Spec.Autoscaling.ScaleDownStabilizationWindow = "0s"
+		rp.Finalizers = nil
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+		Expect(runnerManager.StartOrUpdate(rp, nil)).To(Succeed())
+
+		By("queueing jobs")
+		jobQueue.Update(&github.WorkflowJob{ID: 1, Action: "queued", Labels: []string{"self-hosted", "test-ns1/rp1"}})
+		jobQueue.Update(&github.WorkflowJob{ID: 2, Action: "queued", Labels: []string{"self-hosted", "test-ns1/rp1"}})
+		jobQueue.Update(&github.WorkflowJob{ID: 3, Action: "queued", Labels: []string{"self-hosted", "test-ns1/rp2"}})
+		Eventually(func() int32 {
+			d := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "rp1", Namespace: "test-ns1"}, d); err != nil {
+				return 0
+			}
+			return *d.Spec.Replicas
+		}).Should(BeNumerically("==", 2))
+
+		By("queueing jobs more than maxRunnerPods")
+		jobQueue.Update(&github.WorkflowJob{ID: 4, Action: "queued", Labels: []string{"self-hosted", "test-ns1/rp1"}})
+		jobQueue.Update(&github.WorkflowJob{ID: 5, Action: "queued", Labels: []string{"self-hosted", "test-ns1/rp1"}})
+		Eventually(func() int32 {
+			d := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "rp1", Namespace: "test-ns1"}, d); err != nil {
+				return 0
+			}
+			return *d.Spec.Replicas
+		}).Should(BeNumerically("==", 3))
+
+		By("finishing jobs")
+		jobQueue.Update(&github.WorkflowJob{ID: 1, Action: "in_progress", Labels: []string{"self-hosted", "test-ns1/rp1"}})
+		jobQueue.Update(&github.WorkflowJob{ID: 2, Action: "completed", Labels: []string{"self-hosted", "test-ns1/rp1"}})
+		jobQueue.Update(&github.WorkflowJob{ID: 4, Action: "completed", Labels: []string{"self-hosted", "test-ns1/rp1"}})
+		jobQueue.Update(&github.WorkflowJob{ID: 5, Action: "completed", Labels: []string{"self-hosted", "test-ns1/rp1"}})
+		Eventually(func() int32 {
+			d := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "rp1", Namespace: "test-ns1"}, d); err != nil {
+				return 0
+			}
+			return *d.Spec.Replicas
+		}).Should(BeNumerically("==", 1))
+
+		By("tearing down")
+		Expect(runnerManager.Stop(rp)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, d)).To(Succeed())
+		deleteRunnerPool(ctx, "rp1", "test-ns1")
+	})
+
+	It("should scale the deployment according to polled queued jobs", func() {
+		By("preparing fake clients")
+		runnerPodClient := runner.NewFakeClient()
+		githubClientFactory := github.NewFakeClientFactory()
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, &events.FakeRecorder{}, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
+
+		By("creating a deployment")
+		labels := map[string]string{"app": "rp1"}
+		d := &appsv1.Deployment{}
+		d.Name = "rp1"
+		d.Namespace = "test-ns1"
+		d.Spec.Replicas = ptr.To[int32](1)
+		d.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+		d.Spec.Template.Labels = labels
+		d.Spec.Template.Spec.Containers = []corev1.Container{{Name: "runner", Image: "sample:latest"}}
+		Expect(k8sClient.Create(ctx, d)).To(Succeed())
+
+		By("starting runnerpool manager")
+		rp := makeRunnerPoolWithRepository("rp1", "test-ns1", "owner/repo1")
+		rp.Spec.MaxRunnerPods = 3
+		rp.Spec.Autoscaling.Enable = true
+		rp.Spec.Autoscaling.Polling = true
+		rp.Spec.Autoscaling.ScaleDownStabilizationWindow = "5s"
+		rp.Finalizers = nil
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+		Expect(runnerManager.StartOrUpdate(rp, nil)).To(Succeed())
+
+		getReplicas := func() int32 {
+			d := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "rp1", Namespace: "test-ns1"}, d); err != nil {
+				return -1
+			}
+			return *d.Spec.Replicas
+		}
+		getDesiredReplicas := func() int32 {
+			rp := &meowsv1alpha1.RunnerPool{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "rp1", Namespace: "test-ns1"}, rp); err != nil {
+				return -1
+			}
+			return ptr.Deref(rp.Status.DesiredReplicas, -1)
+		}
+		Eventually(getReplicas).Should(BeNumerically("==", 0))
+		Eventually(getDesiredReplicas).Should(BeNumerically("==", 0))
+
+		By("checking the status")
+		Eventually(func(g Gomega) {
+			rp := &meowsv1alpha1.RunnerPool{}
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "rp1", Namespace: "test-ns1"}, rp)).To(Succeed())
+			g.Expect(meta.IsStatusConditionTrue(rp.Status.Conditions, meowsv1alpha1.ConditionGitHubReachable)).To(BeTrue())
+			g.Expect(rp.Status.OnlineRunners).To(BeNumerically("==", 0))
+			g.Expect(rp.Status.OfflineRunners).To(BeNumerically("==", 0))
+		}).Should(Succeed())
+
+		By("queueing jobs")
+		githubClientFactory.SetQueuedJobs(map[string][]*github.Job{
+			"owner/repo1": {
+				{ID: 1, RunID: 1, Repo: "repo1", Labels: []string{"self-hosted", "test-ns1/rp1"}},
+				{ID: 2, RunID: 1, Repo: "repo1", Labels: []string{"self-hosted", "test-ns1/rp1"}},
+				{ID: 3, RunID: 2, Repo: "repo1", Labels: []string{"self-hosted", "test-ns1/rp2"}},
+			},
+		})
+		Eventually(getReplicas).Should(BeNumerically("==", 2))
+		Eventually(getDesiredReplicas).Should(BeNumerically("==", 2))
+
+		By("finishing jobs")
+		githubClientFactory.SetQueuedJobs(map[string][]*github.Job{})
+		Consistently(getReplicas, 3*time.Second).Should(BeNumerically("==", 2))
+		Eventually(getReplicas, 10*time.Second).Should(BeNumerically("==", 0))
+		Eventually(getDesiredReplicas).Should(BeNumerically("==", 0))
+
+		By("tearing down")
+		Expect(runnerManager.Stop(rp)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, d)).To(Succeed())
+		deleteRunnerPool(ctx, "rp1", "test-ns1")
+	})
+
+	It("should report the repositories truncated in polling queued jobs of an organization", func() {
+		By("preparing fake clients")
+		runnerPodClient := runner.NewFakeClient()
+		githubClientFactory := github.NewFakeClientFactory()
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, &events.FakeRecorder{}, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
+
+		By("creating a deployment")
+		labels := map[string]string{"app": "rp1"}
+		d := &appsv1.Deployment{}
+		d.Name = "rp1"
+		d.Namespace = "test-ns1"
+		d.Spec.Replicas = ptr.To[int32](1)
+		d.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+		d.Spec.Template.Labels = labels
+		d.Spec.Template.Spec.Containers = []corev1.Container{{Name: "runner", Image: "sample:latest"}}
+		Expect(k8sClient.Create(ctx, d)).To(Succeed())
+
+		By("queueing jobs in two repositories")
+		githubClientFactory.SetQueuedJobs(map[string][]*github.Job{
+			"org": {
+				{ID: 1, RunID: 1, Repo: "repo1", Labels: []string{"self-hosted", "test-ns1/rp1"}},
+				{ID: 2, RunID: 2, Repo: "repo2", Labels: []string{"self-hosted", "test-ns1/rp1"}},
+			},
+		})
+
+		By("starting runnerpool manager with the limit of one repository")
+		rp := makeRunnerPoolWithOrganization("rp1", "test-ns1", "org")
+		rp.Spec.MaxRunnerPods = 3
+		rp.Spec.Autoscaling.Enable = true
+		rp.Spec.Autoscaling.Polling = true
+		rp.Spec.Autoscaling.PollingRepositoryLimit = 1
+		rp.Finalizers = nil
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+		Expect(runnerManager.StartOrUpdate(rp, nil)).To(Succeed())
+
+		getPolledCondition := func() *metav1.Condition {
+			rp := &meowsv1alpha1.RunnerPool{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "rp1", Namespace: "test-ns1"}, rp); err != nil {
+				return nil
+			}
+			return meta.FindStatusCondition(rp.Status.Conditions, meowsv1alpha1.ConditionQueuedJobsPolled)
+		}
+		Eventually(func(g Gomega) {
+			cond := getPolledCondition()
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			g.Expect(cond.Reason).To(Equal(reasonRepositoriesTruncated))
+		}).Should(Succeed())
+		Eventually(func() int32 {
+			d := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "rp1", Namespace: "test-ns1"}, d); err != nil {
+				return -1
+			}
+			return *d.Spec.Replicas
+		}).Should(BeNumerically("==", 1))
+
+		By("raising the limit")
+		rp.Spec.Autoscaling.PollingRepositoryLimit = 2
+		Expect(runnerManager.StartOrUpdate(rp, nil)).To(Succeed())
+		Eventually(func(g Gomega) {
+			cond := getPolledCondition()
+			g.Expect(cond).NotTo(BeNil())
+			g.Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			g.Expect(cond.Reason).To(Equal(reasonAllRepositoriesPolled))
+		}).Should(Succeed())
+
+		By("disabling the polling")
+		rp.Spec.Autoscaling.Polling = false
+		Expect(runnerManager.StartOrUpdate(rp, nil)).To(Succeed())
+		Eventually(getPolledCondition).Should(BeNil())
+
+		By("tearing down")
+		Expect(runnerManager.Stop(rp)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, d)).To(Succeed())
+		deleteRunnerPool(ctx, "rp1", "test-ns1")
+	})
+
+	It("should protect busy pods when the deployment is scaled down by schedules", func() {
+		By("preparing fake clients")
+		runnerPodClient := runner.NewFakeClient()
+		githubClientFactory := github.NewFakeClientFactory()
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, &events.FakeRecorder{}, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
+
+		By("creating a deployment")
+		labels := map[string]string{"app": "rp1"}
+		d := &appsv1.Deployment{}
+		d.Name = "rp1"
+		d.Namespace = "test-ns1"
+		d.Spec.Replicas = ptr.To[int32](2)
+		d.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+		d.Spec.Template.Labels = labels
+		d.Spec.Template.Spec.Containers = []corev1.Container{{Name: "runner", Image: "sample:latest"}}
+		Expect(k8sClient.Create(ctx, d)).To(Succeed())
+
+		By("creating a busy pod and an idle pod controlled by the deployment")
+		inputPods := []struct {
+			spec *corev1.Pod
+			ip   string
+		}{
+			{spec: makePod("pod1", "test-ns1", "rp1"), ip: "10.0.0.1"},
+			{spec: makePod("pod2", "test-ns1", "rp1"), ip: "10.0.0.2"},
+		}
+		for _, inputPod := range inputPods {
+			inputPod.spec.Labels[appsv1.DefaultDeploymentUniqueLabelKey] = "foo"
+			Expect(k8sClient.Create(ctx, inputPod.spec)).To(Succeed())
+			created := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: inputPod.spec.Name, Namespace: inputPod.spec.Namespace}, created)).To(Succeed())
+			created.Status.PodIP = inputPod.ip
+			created.Status.Phase = corev1.PodRunning
+			Expect(k8sClient.Status().Update(ctx, created)).To(Succeed())
+			runnerPodClient.SetStatus(created.Status.PodIP, &runner.Status{State: "running"})
+		}
+		githubClientFactory.SetRunners(map[string][]*github.Runner{
+			"owner/repo1": {
+				{Name: "pod1", ID: 1, Online: true, Busy: true, Labels: []string{"test-ns1/rp1"}},
+				{Name: "pod2", ID: 2, Online: true, Busy: false, Labels: []string{"test-ns1/rp1"}},
+			},
+		})
+
+		By("starting runnerpool manager with a schedule scaling down the deployment")
+		rp := makeRunnerPoolWithRepository("rp1", "test-ns1", "owner/repo1")
+		rp.Spec.Replicas = 2
+		rp.Spec.Schedules = []meowsv1alpha1.ScheduleConfig{
+			{Name: "always", Cron: "* * * * *", Replicas: 1},
+		}
+		rp.Finalizers = nil
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+		Expect(runnerManager.StartOrUpdate(rp, nil)).To(Succeed())
+
+		By("checking the deployment is scaled down and the busy pod is protected")
+		Eventually(func() int32 {
+			d := &appsv1.Deployment{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "rp1", Namespace: "test-ns1"}, d); err != nil {
+				return 0
+			}
+			return *d.Spec.Replicas
+		}).Should(BeNumerically("==", 1))
+		getDeletionCost := func(name string) string {
+			po := &corev1.Pod{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "test-ns1"}, po); err != nil {
+				return ""
+			}
+			return po.Annotations[corev1.PodDeletionCost]
+		}
+		Eventually(func() string { return getDeletionCost("pod1") }).Should(Equal(busyPodDeletionCost))
+		Expect(getDeletionCost("pod2")).To(BeEmpty())
+
+		By("tearing down")
+		Expect(runnerManager.Stop(rp)).To(Succeed())
+		deleteRunnerPool(ctx, "rp1", "test-ns1")
+		k8sClient.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace("test-ns1"))
+		time.Sleep(500 * time.Millisecond)
+	})
+
+	It("should check and create the runner group", func() {
+		By("preparing fake clients")
+		runnerPodClient := runner.NewFakeClient()
+		githubClientFactory := github.NewFakeClientFactory()
+		githubClientFactory.SetRunnerGroups(map[string][]*github.RunnerGroup{
+			"org1": {{ID: 1, Name: "Default"}},
+		})
+		recorder := events.NewFakeRecorder(100)
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, recorder, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
+
+		By("starting runnerpool manager with a missing runner group")
+		rp := makeRunnerPoolWithOrganization("rp1", "test-ns1", "org1")
+		rp.Spec.RunnerGroup = "group1"
+		rp.Finalizers = nil
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+		Expect(runnerManager.StartOrUpdate(rp, nil)).To(Succeed())
+
+		getCondition := func() *metav1.Condition {
+			rp := &meowsv1alpha1.RunnerPool{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "rp1", Namespace: "test-ns1"}, rp); err != nil {
+				return nil
+			}
+			return meta.FindStatusCondition(rp.Status.Conditions, meowsv1alpha1.ConditionRunnerGroupReady)
+		}
+		Eventually(getCondition).Should(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status":             Equal(metav1.ConditionFalse),
+			"Reason":             Equal("RunnerGroupNotFound"),
+			"ObservedGeneration": Equal(rp.Generation),
+		})))
+		Eventually(recorder.Events).Should(Receive(HavePrefix("Warning RunnerGroupNotFound ")))
+
+		By("allowing to create the runner group")
+		rp.Spec.CreateRunnerGroup = true
+		Expect(runnerManager.StartOrUpdate(rp, nil)).To(Succeed())
+		Eventually(getCondition).Should(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionTrue),
+			"Reason": Equal("CreatedRunnerGroup"),
+		})))
+		Eventually(recorder.Events).Should(Receive(HavePrefix("Normal CreatedRunnerGroup ")))
+		groups, err := githubClientFactory.ListRunnerGroups(ctx, "org1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(groups).To(ContainElement(PointTo(MatchFields(IgnoreExtras, Fields{"Name": Equal("group1")}))))
+
+		By("tearing down")
+		Expect(runnerManager.Stop(rp)).To(Succeed())
+		deleteRunnerPool(ctx, "rp1", "test-ns1")
+	})
+
+	It("should reload the rotated credential", func() {
+		By("preparing fake clients")
+		runnerPodClient := runner.NewFakeClient()
+		githubClientFactory := github.NewFakeClientFactory()
+		recorder := events.NewFakeRecorder(100)
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, recorder, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
+
+		By("starting runnerpool manager")
+		rp := makeRunnerPoolWithRepository("rp1", "test-ns1", "repo1")
+		rp.Finalizers = nil
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+		Expect(runnerManager.StartOrUpdate(rp, &github.ClientCredential{PersonalAccessToken: "old-pat"})).To(Succeed())
+
+		By("updating with the same credential")
+		Expect(runnerManager.StartOrUpdate(rp, &github.ClientCredential{PersonalAccessToken: "old-pat"})).To(Succeed())
+		Consistently(recorder.Events, time.Second).ShouldNot(Receive(HavePrefix("Normal UpdatedCredential ")))
+
+		By("updating with the rotated credential")
+		Expect(runnerManager.StartOrUpdate(rp, &github.ClientCredential{PersonalAccessToken: "new-pat"})).To(Succeed())
+		Eventually(recorder.Events).Should(Receive(HavePrefix("Normal UpdatedCredential ")))
+
+		By("tearing down")
+		Expect(runnerManager.Stop(rp)).To(Succeed())
+		deleteRunnerPool(ctx, "rp1", "test-ns1")
+	})
+
+	It("should manage enterprise-level runners", func() {
+		By("preparing fake clients")
+		runnerPodClient := runner.NewFakeClient()
+		githubClientFactory := github.NewFakeClientFactory()
+		githubClientFactory.SetRunners(map[string][]*github.Runner{
+			"enterprises/enterprise1": {
+				{Name: "pod1", ID: 1, Online: false, Busy: false, Labels: []string{"test-ns1/rp1"}}, // pod does not exist
+				{Name: "pod2", ID: 2, Online: true, Busy: false, Labels: []string{"test-ns1/rp1"}},  // pod does not exist, but online
+				{Name: "pod3", ID: 3, Online: false, Busy: false, Labels: []string{"test-ns1/rp2"}}, // another runnerpool
+			},
+		})
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, &events.FakeRecorder{}, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
+
+		By("starting runnerpool manager")
+		rp := makeRunnerPool("rp1", "test-ns1")
+		rp.Spec.Enterprise = "enterprise1"
+		Expect(runnerManager.StartOrUpdate(rp, nil)).To(Succeed())
+
+		By("checking the offline runner is removed")
+		listRunnerNames := func() []string {
+			runnerList, err := githubClientFactory.ListEnterpriseRunners(ctx, "enterprise1", nil)
+			Expect(err).NotTo(HaveOccurred())
+			var names []string
+			for _, r := range runnerList {
+				names = append(names, r.Name)
+			}
+			return names
+		}
+		Eventually(listRunnerNames).Should(ConsistOf("pod2", "pod3"))
+
+		By("stopping runnerpool manager")
+		Expect(runnerManager.Stop(rp)).To(Succeed())
+		Expect(listRunnerNames()).To(ConsistOf("pod3"))
+	})
+
+	It("should deliver just-in-time configurations to runner pods", func() {
+		By("preparing fake clients")
+		runnerPodClient := runner.NewFakeClient()
+		githubClientFactory := github.NewFakeClientFactory()
+		recorder := events.NewFakeRecorder(100)
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, recorder, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
+
+		By("preparing a pod waiting for the configuration")
+		po := makePod("pod1", "test-ns1", "rp1")
+		Expect(k8sClient.Create(ctx, po)).To(Succeed())
+		created := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "pod1", Namespace: "test-ns1"}, created)).To(Succeed())
+		created.Status.PodIP = "10.0.0.1"
+		created.Status.Phase = corev1.PodRunning
+		Expect(k8sClient.Status().Update(ctx, created)).To(Succeed())
+		runnerPodClient.SetStatus("10.0.0.1", &runner.Status{State: "initializing", JITConfigRequired: true})
+
+		By("starting runnerpool manager")
+		rp := makeRunnerPoolWithOrganization("rp1", "test-ns1", "org1")
+		rp.Spec.JITConfig = true
+		rp.Spec.Labels = []string{"gpu"}
+		rp.Finalizers = nil
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+		Expect(runnerManager.StartOrUpdate(rp, nil)).To(Succeed())
+
+		By("checking the configuration is delivered")
+		Eventually(func() string {
+			return runnerPodClient.GetJITConfig("10.0.0.1")
+		}).Should(Equal("fakejitconfig-pod1"))
+		Eventually(recorder.Events).Should(Receive(HavePrefix("Normal DeliveredJITConfig ")))
+		runnerList, err := githubClientFactory.ListRunners(ctx, "org1", "", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(runnerList).To(ConsistOf(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Name":   Equal("pod1"),
+			"Labels": ConsistOf("self-hosted", "test-ns1/rp1", "gpu"),
+		}))))
+
+		By("tearing down")
+		Expect(runnerManager.Stop(rp)).To(Succeed())
+		deleteRunnerPool(ctx, "rp1", "test-ns1")
+		k8sClient.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace("test-ns1"))
+		time.Sleep(500 * time.Millisecond)
+	})
+
+	It("should check the runner group again when it is not found in registering a runner", func() {
+		By("preparing fake clients")
+		runnerPodClient := runner.NewFakeClient()
+		githubClientFactory := github.NewFakeClientFactory()
+		githubClientFactory.SetRunnerGroups(map[string][]*github.RunnerGroup{
+			"org1": {{ID: 2, Name: "group1"}},
+		})
+		recorder := events.NewFakeRecorder(100)
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, recorder, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
+
+		By("starting runnerpool manager with the runner group")
+		rp := makeRunnerPoolWithOrganization("rp1", "test-ns1", "org1")
+		rp.Spec.JITConfig = true
+		rp.Spec.RunnerGroup = "group1"
+		rp.Finalizers = nil
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+		Expect(runnerManager.StartOrUpdate(rp, nil)).To(Succeed())
+
+		getCondition := func() *metav1.Condition {
+			rp := &meowsv1alpha1.RunnerPool{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "rp1", Namespace: "test-ns1"}, rp); err != nil {
+				return nil
+			}
+			return meta.FindStatusCondition(rp.Status.Conditions, meowsv1alpha1.ConditionRunnerGroupReady)
+		}
+		Eventually(getCondition).Should(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionTrue),
+			"Reason": Equal("RunnerGroupFound"),
+		})))
+
+		By("deleting the runner group and preparing a pod waiting for the configuration")
+		githubClientFactory.SetRunnerGroups(map[string][]*github.RunnerGroup{})
+		po := makePod("pod1", "test-ns1", "rp1")
+		Expect(k8sClient.Create(ctx, po)).To(Succeed())
+		created := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "pod1", Namespace: "test-ns1"}, created)).To(Succeed())
+		created.Status.PodIP = "10.0.0.1"
+		created.Status.Phase = corev1.PodRunning
+		Expect(k8sClient.Status().Update(ctx, created)).To(Succeed())
+		runnerPodClient.SetStatus("10.0.0.1", &runner.Status{State: "initializing", JITConfigRequired: true})
+
+		By("checking the deleted runner group is detected")
+		Eventually(recorder.Events).Should(Receive(HavePrefix("Warning DeliverJITConfigFailed ")))
+		Eventually(getCondition).Should(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionFalse),
+			"Reason": Equal("RunnerGroupNotFound"),
+		})))
+
+		By("recreating the runner group")
+		githubClientFactory.SetRunnerGroups(map[string][]*github.RunnerGroup{
+			"org1": {{ID: 3, Name: "group1"}},
+		})
+		Eventually(func() string {
+			return runnerPodClient.GetJITConfig("10.0.0.1")
+		}).Should(Equal("fakejitconfig-pod1"))
+		Expect(getCondition()).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionTrue),
+		})))
+
+		By("tearing down")
+		Expect(runnerManager.Stop(rp)).To(Succeed())
+		deleteRunnerPool(ctx, "rp1", "test-ns1")
+		k8sClient.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace("test-ns1"))
+		time.Sleep(500 * time.Millisecond)
+	})
+
+	It("should delete runner pods which accepted jit configurations from another client", func() {
+		By("preparing fake clients")
+		runnerPodClient := runner.NewFakeClient()
+		githubClientFactory := github.NewFakeClientFactory()
+		recorder := events.NewFakeRecorder(100)
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, recorder, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
+
+		By("preparing a pod which accepted a configuration from another client")
+		po := makePod("pod1", "test-ns1", "rp1")
+		Expect(k8sClient.Create(ctx, po)).To(Succeed())
+		created := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "pod1", Namespace: "test-ns1"}, created)).To(Succeed())
+		created.Status.PodIP = "10.0.0.1"
+		created.Status.Phase = corev1.PodRunning
+		Expect(k8sClient.Status().Update(ctx, created)).To(Succeed())
+		runnerPodClient.SetStatus("10.0.0.1", &runner.Status{State: "initializing", JITConfigRequired: true})
+		runnerPodClient.SetJITConfig("10.0.0.1", "malicious-jit-config")
+
+		By("starting runnerpool manager")
+		rp := makeRunnerPoolWithOrganization("rp1", "test-ns1", "org1")
+		rp.Spec.JITConfig = true
+		rp.Finalizers = nil
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+		Expect(runnerManager.StartOrUpdate(rp, nil)).To(Succeed())
+
+		By("checking the conflict is reported and the pod is deleted")
+		Eventually(recorder.Events).Should(Receive(HavePrefix("Warning JITConfigConflict ")))
+		Eventually(func() bool {
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: "pod1", Namespace: "test-ns1"}, &corev1.Pod{})
+			return apierrors.IsNotFound(err)
+		}).Should(BeTrue())
+
+		By("tearing down")
+		Expect(runnerManager.Stop(rp)).To(Succeed())
+		deleteRunnerPool(ctx, "rp1", "test-ns1")
+		time.Sleep(500 * time.Millisecond)
+	})
+
 	It("should expose metrics about runnerpools", func() {
 		By("preparing fake clients")
 		runnerPodClient := runner.NewFakeClient()
 		githubClientFactory := github.NewFakeClientFactory()
-		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, githubClientFactory, runnerPodClient, time.Second)
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, &events.FakeRecorder{}, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
 
 		By("starting metrics server")
 		server := &http.Server{Addr: metricsPort, Handler: promhttp.Handler()}
@@ -469,7 +1045,7 @@ var _ = Describe("RunnerManager", func() {
 		By("preparing fake clients")
 		runnerPodClient := runner.NewFakeClient()
 		githubClientFactory := github.NewFakeClientFactory()
-		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, githubClientFactory, runnerPodClient, time.Second)
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, &events.FakeRecorder{}, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
 
 		By("starting metrics server")
 		server := &http.Server{Addr: metricsPort, Handler: promhttp.Handler()}
@@ -592,7 +1168,7 @@ var _ = Describe("RunnerManager", func() {
 		By("preparing fake clients")
 		runnerPodClient := runner.NewFakeClient()
 		githubClientFactory := github.NewFakeClientFactory()
-		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, githubClientFactory, runnerPodClient, time.Second)
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, &events.FakeRecorder{}, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
 
 		By("starting metrics server")
 		server := &http.Server{Addr: metricsPort, Handler: promhttp.Handler()}
@@ -764,7 +1340,7 @@ var _ = Describe("RunnerManager", func() {
 		By("preparing fake clients")
 		runnerPodClient := runner.NewFakeClient()
 		githubClientFactory := github.NewFakeClientFactory()
-		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, githubClientFactory, runnerPodClient, time.Second)
+		runnerManager := NewRunnerManager(ctrl.Log, k8sClient, scheme, &events.FakeRecorder{}, githubClientFactory, runnerPodClient, NewJobQueue(), time.Second)
 
 		By("starting metrics server")
 		server := &http.Server{Addr: metricsPort, Handler: promhttp.Handler()}
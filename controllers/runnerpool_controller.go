@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"time"
 
@@ -17,8 +18,12 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -26,34 +31,71 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+// Reasons of the RunnerPool conditions set by the reconciler.
+const (
+	reasonCredentialLoaded          = "CredentialLoaded"
+	reasonGetCredentialFailed       = "GetCredentialFailed"
+	reasonTokenIssued               = "TokenIssued"
+	reasonJITConfigEnabled          = "JITConfigEnabled"
+	reasonRunnerAPIKeyNotConfigured = "RunnerAPIKeyNotConfigured"
+	reasonWaitingForToken           = "WaitingForToken"
+	reasonReconcileSecretFailed     = "ReconcileSecretFailed"
+	reasonStartSecretUpdaterFailed  = "StartSecretUpdaterFailed"
+	reasonReconcileDeploymentFailed = "ReconcileDeploymentFailed"
+	reasonDeploymentProgressing     = "DeploymentProgressing"
+	reasonReconcileCachesFailed     = "ReconcileCachesFailed"
+	reasonReconcileRBACFailed       = "ReconcileRBACFailed"
+	reasonReconcileAPITokenFailed   = "ReconcileAPITokenFailed"
+	reasonNamespaceNotDedicated     = "NamespaceNotDedicated"
+)
+
+// Schedule of the CronJob to evict the caches.
+const cacheEvictionSchedule = "@hourly"
+
+// Interval to read the credential from the credential providers.
+const credentialRefreshInterval = 5 * time.Minute
+
 // RunnerPoolReconciler reconciles a RunnerPool object
 type RunnerPoolReconciler struct {
 	client.Client
-	log                logr.Logger
-	scheme             *runtime.Scheme
-	runnerImage        string
-	runnerManager      RunnerManager
-	secretUpdater      SecretUpdater
-	organizationRegexp *regexp.Regexp
-	repositoryRegexp   *regexp.Regexp
+	log                 logr.Logger
+	scheme              *runtime.Scheme
+	runnerImage         string
+	runnerManager       RunnerManager
+	secretUpdater       SecretUpdater
+	organizationRegexp  *regexp.Regexp
+	repositoryRegexp    *regexp.Regexp
+	enterpriseRegexp    *regexp.Regexp
+	sharedCredentials   *SharedCredentials
+	secretProvider      CredentialProvider
+	credentialProviders map[string]CredentialProvider
+	runnerAPIKey        []byte
 }
 
 // NewRunnerPoolReconciler creates RunnerPoolReconciler
 func NewRunnerPoolReconciler(
 	log logr.Logger, client client.Client, scheme *runtime.Scheme, runnerImage string,
 	runnerManager RunnerManager, secretUpdater SecretUpdater,
-	organizationRegexp, repositoryRegexp *regexp.Regexp) *RunnerPoolReconciler {
+	organizationRegexp, repositoryRegexp, enterpriseRegexp *regexp.Regexp,
+	sharedCredentials *SharedCredentials, credentialProviders map[string]CredentialProvider, runnerAPIKey []byte) *RunnerPoolReconciler {
 	return &RunnerPoolReconciler{
-		Client:             client,
-		log:                log.WithName("RunnerPool"),
-		scheme:             scheme,
-		runnerImage:        runnerImage,
-		runnerManager:      runnerManager,
-		secretUpdater:      secretUpdater,
-		organizationRegexp: organizationRegexp,
-		repositoryRegexp:   repositoryRegexp,
+		Client:              client,
+		log:                 log.WithName("RunnerPool"),
+		scheme:              scheme,
+		runnerImage:         runnerImage,
+		runnerManager:       runnerManager,
+		secretUpdater:       secretUpdater,
+		organizationRegexp:  organizationRegexp,
+		repositoryRegexp:    repositoryRegexp,
+		enterpriseRegexp:    enterpriseRegexp,
+		sharedCredentials:   sharedCredentials,
+		secretProvider:      &secretCredentialProvider{client: client},
+		credentialProviders: credentialProviders,
+		runnerAPIKey:        runnerAPIKey,
 	}
 }
 
@@ -62,6 +104,13 @@ func NewRunnerPoolReconciler(
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
+
+// The controller grants the permissions to the runner container hooks without holding them itself.
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=escalate;bind
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -112,31 +161,84 @@ func (r *RunnerPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// Check before creating the Secrets of the RunnerPool, because the jobs of the kubernetes container mode can read them.
+	if err := r.checkDedicatedNamespace(ctx, rp); err != nil {
+		log.Error(err, "namespace is not dedicated to the container hooks")
+		r.updateFailedCondition(ctx, log, rp, meowsv1alpha1.ConditionDeploymentAvailable, reasonNamespaceNotDedicated, err.Error())
+		return ctrl.Result{}, err
+	}
+
 	cred, err := r.getGitHubCredential(ctx, log, rp)
 	if err != nil {
 		log.Error(err, "failed to get github credential")
+		r.updateFailedCondition(ctx, log, rp, meowsv1alpha1.ConditionCredentialReady, reasonGetCredentialFailed, err.Error())
 		return ctrl.Result{}, err
 	}
+	setCondition(rp, meowsv1alpha1.ConditionCredentialReady, metav1.ConditionTrue, reasonCredentialLoaded, "")
 
-	isContinuation, err := r.reconcileSecret(ctx, log, rp)
-	if err != nil {
-		log.Error(err, "failed to reconcile secret")
+	if rp.Spec.JITConfig {
+		// Without the API tokens, the runner pods would accept the configuration from anyone who can reach them.
+		if r.runnerAPIKey == nil {
+			err := errors.New("jitConfig requires the controller to be configured with --runner-api-key-file")
+			log.Error(err, "runner api key is not configured")
+			r.updateFailedCondition(ctx, log, rp, meowsv1alpha1.ConditionRegistrationTokenReady, reasonRunnerAPIKeyNotConfigured, err.Error())
+			return ctrl.Result{}, err
+		}
+		// The runner manager registers a runner for each pod, so the shared registration token is not needed.
+		if err := r.deleteSecret(ctx, log, rp); err != nil {
+			log.Error(err, "failed to delete secret")
+			r.updateFailedCondition(ctx, log, rp, meowsv1alpha1.ConditionRegistrationTokenReady, reasonReconcileSecretFailed, err.Error())
+			return ctrl.Result{}, err
+		}
+		setCondition(rp, meowsv1alpha1.ConditionRegistrationTokenReady, metav1.ConditionTrue, reasonJITConfigEnabled, "")
+	} else {
+		isContinuation, err := r.reconcileSecret(ctx, log, rp)
+		if err != nil {
+			log.Error(err, "failed to reconcile secret")
+			r.updateFailedCondition(ctx, log, rp, meowsv1alpha1.ConditionRegistrationTokenReady, reasonReconcileSecretFailed, err.Error())
+			return ctrl.Result{}, err
+		}
+		if err := r.secretUpdater.Start(rp, cred); err != nil {
+			log.Error(err, "failed to start secret updater")
+			r.updateFailedCondition(ctx, log, rp, meowsv1alpha1.ConditionRegistrationTokenReady, reasonStartSecretUpdaterFailed, err.Error())
+			return ctrl.Result{}, err
+		}
+		if !isContinuation {
+			log.Info("wait for the secret to be issued by secret updater")
+			r.updateFailedCondition(ctx, log, rp, meowsv1alpha1.ConditionRegistrationTokenReady, reasonWaitingForToken, "waiting for the registration token to be issued")
+			return ctrl.Result{
+				Requeue:      true,
+				RequeueAfter: 10 * time.Second,
+			}, nil
+		}
+		setCondition(rp, meowsv1alpha1.ConditionRegistrationTokenReady, metav1.ConditionTrue, reasonTokenIssued, "")
+	}
+
+	if err := r.reconcileCaches(ctx, log, rp); err != nil {
+		log.Error(err, "failed to reconcile caches")
+		r.updateFailedCondition(ctx, log, rp, meowsv1alpha1.ConditionDeploymentAvailable, reasonReconcileCachesFailed, err.Error())
 		return ctrl.Result{}, err
 	}
-	if err := r.secretUpdater.Start(rp, cred); err != nil {
-		log.Error(err, "failed to start secret updater")
+
+	if err := r.reconcileAPITokenSecret(ctx, log, rp); err != nil {
+		log.Error(err, "failed to reconcile secret for api token")
+		r.updateFailedCondition(ctx, log, rp, meowsv1alpha1.ConditionDeploymentAvailable, reasonReconcileAPITokenFailed, err.Error())
 		return ctrl.Result{}, err
 	}
-	if !isContinuation {
-		log.Info("wait for the secret to be issued by secret updater")
-		return ctrl.Result{
-			Requeue:      true,
-			RequeueAfter: 10 * time.Second,
-		}, nil
+
+	if err := r.reconcileContainerHooksRBAC(ctx, log, rp); err != nil {
+		log.Error(err, "failed to reconcile RBAC for container hooks")
+		r.updateFailedCondition(ctx, log, rp, meowsv1alpha1.ConditionDeploymentAvailable, reasonReconcileRBACFailed, err.Error())
+		return ctrl.Result{}, err
 	}
 
 	if err := r.reconcileDeployment(ctx, log, rp); err != nil {
 		log.Error(err, "failed to reconcile deployment")
+		r.updateFailedCondition(ctx, log, rp, meowsv1alpha1.ConditionDeploymentAvailable, reasonReconcileDeploymentFailed, err.Error())
+		return ctrl.Result{}, err
+	}
+	if err := r.updateDeploymentCondition(ctx, rp); err != nil {
+		log.Error(err, "failed to get deployment")
 		return ctrl.Result{}, err
 	}
 
@@ -146,22 +248,116 @@ func (r *RunnerPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	rp.Status.Bound = true
+	if !rp.Spec.Autoscaling.Enable {
+		rp.Status.DesiredReplicas = nil
+	}
+	_, rp.Status.ActiveSchedule = scheduledReplicas(rp.Spec.Schedules, 0, time.Now())
 	if err := r.Status().Update(ctx, rp); err != nil {
 		log.Error(err, "failed to update status")
 		return ctrl.Result{}, err
 	}
+	if rp.Spec.CredentialProvider != nil {
+		// The credential providers other than Secrets cannot be watched, so read the credential periodically to apply the rotation.
+		return ctrl.Result{RequeueAfter: credentialRefreshInterval}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
+func setCondition(rp *meowsv1alpha1.RunnerPool, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&rp.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: rp.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// updateFailedCondition records why the reconciliation cannot proceed in the status.
+// The error of the update is only logged, because the caller returns the original error.
+func (r *RunnerPoolReconciler) updateFailedCondition(ctx context.Context, log logr.Logger, rp *meowsv1alpha1.RunnerPool, conditionType, reason, message string) {
+	setCondition(rp, conditionType, metav1.ConditionFalse, reason, message)
+	if err := r.Status().Update(ctx, rp); err != nil {
+		log.Error(err, "failed to update status")
+	}
+}
+
+func (r *RunnerPoolReconciler) updateDeploymentCondition(ctx context.Context, rp *meowsv1alpha1.RunnerPool) error {
+	d := &appsv1.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: rp.Namespace, Name: rp.GetRunnerDeploymentName()}, d)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range d.Status.Conditions {
+		if c.Type != appsv1.DeploymentAvailable {
+			continue
+		}
+		reason := c.Reason
+		if reason == "" {
+			reason = reasonDeploymentProgressing
+		}
+		setCondition(rp, meowsv1alpha1.ConditionDeploymentAvailable, metav1.ConditionStatus(c.Status), reason, c.Message)
+		return nil
+	}
+	setCondition(rp, meowsv1alpha1.ConditionDeploymentAvailable, metav1.ConditionUnknown, reasonDeploymentProgressing, "waiting for the deployment to be observed")
+	return nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *RunnerPoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&meowsv1alpha1.RunnerPool{}).
 		Owns(&corev1.Secret{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.PersistentVolumeClaim{}).
+		Owns(&batchv1.CronJob{}).
+		Owns(&corev1.ServiceAccount{}).
+		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.runnerPoolsForCredentialSecret)).
 		Complete(r)
 }
 
+// runnerPoolsForCredentialSecret returns the RunnerPools using the secret as the credential,
+// so that the rotated credential is applied to the running processes.
+func (r *RunnerPoolReconciler) runnerPoolsForCredentialSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	shared := map[string]bool{}
+	for _, name := range r.sharedCredentials.usedBy(obj) {
+		shared[name] = true
+	}
+
+	var opts []client.ListOption
+	if len(shared) == 0 {
+		opts = append(opts, client.InNamespace(obj.GetNamespace()))
+	}
+	rpList := &meowsv1alpha1.RunnerPoolList{}
+	if err := r.List(ctx, rpList, opts...); err != nil {
+		r.log.Error(err, "failed to list RunnerPools", "secret", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range rpList.Items {
+		rp := &rpList.Items[i]
+		switch {
+		case rp.Spec.SharedCredentialName != "":
+			if !shared[rp.Spec.SharedCredentialName] {
+				continue
+			}
+		case rp.Namespace == obj.GetNamespace() && credentialSecretName(rp) == obj.GetName():
+		case rp.Spec.CredentialSecretName == "" && r.sharedCredentials.hasDefault() && shared[r.sharedCredentials.Default]:
+			// The RunnerPool may use the default shared credential.
+		default:
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: rp.Namespace, Name: rp.Name},
+		})
+	}
+	return requests
+}
+
 func labelSet(rp *meowsv1alpha1.RunnerPool) map[string]string {
 	labels := map[string]string{
 		constants.AppNameLabelKey:      constants.AppName,
@@ -171,6 +367,17 @@ func labelSet(rp *meowsv1alpha1.RunnerPool) map[string]string {
 	return labels
 }
 
+// runnerPoolReference returns the reference to the RunnerPool to record events on it.
+func runnerPoolReference(rp *meowsv1alpha1.RunnerPool) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion: meowsv1alpha1.GroupVersion.String(),
+		Kind:       "RunnerPool",
+		Namespace:  rp.Namespace,
+		Name:       rp.Name,
+		UID:        rp.UID,
+	}
+}
+
 func mergeMap(m1, m2 map[string]string) map[string]string {
 	m := make(map[string]string)
 	for k, v := range m1 {
@@ -185,8 +392,14 @@ func mergeMap(m1, m2 map[string]string) map[string]string {
 	return m
 }
 
-func readAppKeySecret(s *corev1.Secret) (*github.ClientCredential, error) {
-	appIDstr, ok := s.Data[constants.CredentialSecretDataAppID]
+func readCredentialData(data map[string][]byte) (*github.ClientCredential, error) {
+	if pat, ok := data[constants.CredentialSecretDataPATToken]; ok {
+		return &github.ClientCredential{
+			PersonalAccessToken: string(pat),
+		}, nil
+	}
+
+	appIDstr, ok := data[constants.CredentialSecretDataAppID]
 	if !ok {
 		return nil, fmt.Errorf("missing %s key", constants.CredentialSecretDataAppID)
 	}
@@ -195,16 +408,16 @@ func readAppKeySecret(s *corev1.Secret) (*github.ClientCredential, error) {
 		return nil, fmt.Errorf("invalid %s value; %w", constants.CredentialSecretDataAppID, err)
 	}
 
-	insIDstr, ok := s.Data[constants.CredentialSecretDataAppInstallationID]
-	if !ok {
-		return nil, fmt.Errorf("missing %s key", constants.CredentialSecretDataAppInstallationID)
-	}
-	insID, err := strconv.Atoi(string(insIDstr))
-	if err != nil {
-		return nil, fmt.Errorf("invalid %s value; %w", constants.CredentialSecretDataAppInstallationID, err)
+	// The installation ID is optional. If it is omitted, the installation on the RunnerPool's owner is used.
+	var insID int
+	if insIDstr, ok := data[constants.CredentialSecretDataAppInstallationID]; ok {
+		insID, err = strconv.Atoi(string(insIDstr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value; %w", constants.CredentialSecretDataAppInstallationID, err)
+		}
 	}
 
-	key, ok := s.Data[constants.CredentialSecretDataAppPrivateKey]
+	key, ok := data[constants.CredentialSecretDataAppPrivateKey]
 	if !ok {
 		return nil, fmt.Errorf("missing %s key", constants.CredentialSecretDataAppPrivateKey)
 	}
@@ -216,36 +429,73 @@ func readAppKeySecret(s *corev1.Secret) (*github.ClientCredential, error) {
 	}, nil
 }
 
-func (r *RunnerPoolReconciler) getGitHubCredential(ctx context.Context, log logr.Logger, rp *meowsv1alpha1.RunnerPool) (*github.ClientCredential, error) {
-	secretName := constants.DefaultCredentialSecretName
+func credentialSecretName(rp *meowsv1alpha1.RunnerPool) string {
 	if rp.Spec.CredentialSecretName != "" {
-		secretName = rp.Spec.CredentialSecretName
+		return rp.Spec.CredentialSecretName
 	}
+	return constants.DefaultCredentialSecretName
+}
 
-	s := &corev1.Secret{}
-	err := r.Client.Get(ctx, types.NamespacedName{
-		Name:      secretName,
-		Namespace: rp.Namespace,
-	}, s)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get credential secret; %w", err)
+func (r *RunnerPoolReconciler) getGitHubCredential(ctx context.Context, log logr.Logger, rp *meowsv1alpha1.RunnerPool) (*github.ClientCredential, error) {
+	var data map[string][]byte
+	switch {
+	case rp.Spec.CredentialProvider != nil:
+		provider, ok := r.credentialProviders[rp.Spec.CredentialProvider.Type]
+		if !ok {
+			return nil, fmt.Errorf("credential provider %q is not configured in the controller", rp.Spec.CredentialProvider.Type)
+		}
+		d, err := provider.Read(ctx, rp.Namespace, rp.Spec.CredentialProvider.Path)
+		if err != nil {
+			return nil, err
+		}
+		data = d
+	case rp.Spec.SharedCredentialName != "":
+		d, err := r.sharedCredentials.get(ctx, r.Client, rp.Spec.SharedCredentialName, rp.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		data = d
+	default:
+		d, err := r.secretProvider.Read(ctx, rp.Namespace, credentialSecretName(rp))
+		switch {
+		case apierrors.IsNotFound(err) && rp.Spec.CredentialSecretName == "" && r.sharedCredentials.hasDefault():
+			// The namespace does not have its own credential, so use the default one provided by the controller.
+			d, err = r.sharedCredentials.get(ctx, r.Client, r.sharedCredentials.Default, rp.Namespace)
+			if err != nil {
+				return nil, err
+			}
+		case err != nil:
+			return nil, err
+		case rp.Spec.ContainerMode == meowsv1alpha1.ContainerModeKubernetes:
+			// The jobs can create pods mounting the secrets in the namespace, so they can read the credential.
+			return nil, errors.New("containerMode kubernetes cannot be used with the credential secret in the namespace of the RunnerPool; use sharedCredentialName or credentialProvider instead")
+		}
+		data = d
 	}
 
-	if pat, ok := s.Data[constants.CredentialSecretDataPATToken]; ok {
-		return &github.ClientCredential{
-			PersonalAccessToken: string(pat),
-		}, nil
+	cred, err := readCredentialData(data)
+	if err != nil {
+		return nil, err
 	}
-
-	return readAppKeySecret(s)
+	cred.GitHubURL = rp.GetGitHubURL()
+	if cred.AppID != 0 {
+		cred.Owner = rp.GetOwner()
+		cred.Repository = rp.GetRepository()
+	}
+	return cred, nil
 }
 
 func (r *RunnerPoolReconciler) validation(ctx context.Context, rp *meowsv1alpha1.RunnerPool) error {
-	if rp.IsOrgLevel() {
+	switch {
+	case rp.IsEnterpriseLevel():
+		if r.enterpriseRegexp != nil && !r.enterpriseRegexp.MatchString(rp.Spec.Enterprise) {
+			return errors.New("enterprise is not match")
+		}
+	case rp.IsOrgLevel():
 		if r.organizationRegexp != nil && !r.organizationRegexp.MatchString(rp.Spec.Organization) {
 			return errors.New("organization is not match")
 		}
-	} else {
+	default:
 		if r.repositoryRegexp != nil && !r.repositoryRegexp.MatchString(rp.Spec.Repository) {
 			return errors.New("repository is not match")
 		}
@@ -276,6 +526,61 @@ func (r *RunnerPoolReconciler) reconcileSecret(ctx context.Context, log logr.Log
 	return false, r.Create(ctx, s)
 }
 
+// deleteSecret stops the secret updater and deletes the secret of the registration token.
+func (r *RunnerPoolReconciler) deleteSecret(ctx context.Context, log logr.Logger, rp *meowsv1alpha1.RunnerPool) error {
+	if err := r.secretUpdater.Stop(rp); err != nil {
+		return err
+	}
+
+	s := &corev1.Secret{}
+	s.SetName(rp.GetRunnerSecretName())
+	s.SetNamespace(rp.Namespace)
+	err := r.Delete(ctx, s)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	log.Info("deleted secret for registration token")
+	return nil
+}
+
+// reconcileAPITokenSecret creates the secret of the token to authenticate the requests to the runner pods.
+// It is deleted if the controller is not configured with the key.
+func (r *RunnerPoolReconciler) reconcileAPITokenSecret(ctx context.Context, log logr.Logger, rp *meowsv1alpha1.RunnerPool) error {
+	s := &corev1.Secret{}
+	s.SetName(rp.GetAPITokenSecretName())
+	s.SetNamespace(rp.Namespace)
+
+	if r.runnerAPIKey == nil {
+		err := r.Delete(ctx, s)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		log.Info("deleted secret for api token")
+		return nil
+	}
+
+	token := runner.APIToken(r.runnerAPIKey, rp.Namespace, rp.Name)
+	op, err := ctrl.CreateOrUpdate(ctx, r.Client, s, func() error {
+		s.Data = map[string][]byte{
+			constants.APITokenFileName: []byte(token),
+		}
+		return ctrl.SetControllerReference(rp, s, r.scheme)
+	})
+	if err != nil {
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		log.Info("reconciled secret for api token", "operation", string(op))
+	}
+	return nil
+}
+
 func (r *RunnerPoolReconciler) reconcileDeployment(ctx context.Context, log logr.Logger, rp *meowsv1alpha1.RunnerPool) error {
 	d := &appsv1.Deployment{}
 	d.SetNamespace(rp.GetNamespace())
@@ -292,48 +597,139 @@ func (r *RunnerPoolReconciler) reconcileDeployment(ctx context.Context, log logr
 		d.Spec.Template.Labels = mergeMap(d.Spec.Template.GetLabels(), labelSet(rp))
 		d.Spec.Template.Annotations = mergeMap(d.Spec.Template.GetAnnotations(), rp.Spec.Template.ObjectMeta.Annotations)
 
-		d.Spec.Replicas = ptr.To[int32](rp.Spec.Replicas)
+		if rp.Spec.Autoscaling.Enable {
+			// The replicas is updated by the runner manager according to the number of queued jobs.
+			// So keep the current value as long as it is within the range.
+			minReplicas, _ := scheduledReplicas(rp.Spec.Schedules, rp.Spec.Autoscaling.MinReplicas, time.Now())
+			replicas := ptr.Deref(d.Spec.Replicas, minReplicas)
+			d.Spec.Replicas = ptr.To[int32](clampReplicas(replicas, minReplicas, rp.Spec.MaxRunnerPods))
+		} else {
+			replicas, _ := scheduledReplicas(rp.Spec.Schedules, rp.Spec.Replicas, time.Now())
+			d.Spec.Replicas = ptr.To[int32](replicas)
+		}
 		d.Spec.Template.Spec.ServiceAccountName = rp.Spec.Template.ServiceAccountName
 		d.Spec.Template.Spec.ImagePullSecrets = rp.Spec.Template.ImagePullSecrets
 		if rp.Spec.Template.AutomountServiceAccountToken != nil {
 			d.Spec.Template.Spec.AutomountServiceAccountToken = rp.Spec.Template.AutomountServiceAccountToken
 		}
 
-		varDir := "var-dir"
-		workDir := "work-dir"
+		varDir := constants.VarDirVolumeName
+		workDir := constants.WorkDirVolumeName
 		volumes := append(rp.Spec.Template.Volumes, corev1.Volume{
 			Name: varDir,
 			VolumeSource: corev1.VolumeSource{
 				EmptyDir: &corev1.EmptyDirVolumeSource{},
 			},
 		})
-		if rp.Spec.WorkVolume == nil {
-			// use emptyDir (default)
+		mode := makeContainerMode(rp)
+		workVolumeClaimTemplate := rp.Spec.WorkVolumeClaimTemplate
+		if workVolumeClaimTemplate == nil && mode != nil {
+			workVolumeClaimTemplate = mode.workVolumeClaimTemplate
+		}
+		switch {
+		case workVolumeClaimTemplate != nil:
+			// The PVC is created for each pod by Kubernetes, and it is owned by the pod.
+			tmpl := workVolumeClaimTemplate
+			spec := tmpl.Spec.DeepCopy()
+			if spec.VolumeMode == nil {
+				// Set the default value here not to update the Deployment in every reconciliation.
+				spec.VolumeMode = ptr.To(corev1.PersistentVolumeFilesystem)
+			}
 			volumes = append(volumes, corev1.Volume{
 				Name: workDir,
 				VolumeSource: corev1.VolumeSource{
-					EmptyDir: &corev1.EmptyDirVolumeSource{},
+					Ephemeral: &corev1.EphemeralVolumeSource{
+						VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+							ObjectMeta: metav1.ObjectMeta{
+								Labels:      mergeMap(tmpl.ObjectMeta.Labels, labelSet(rp)),
+								Annotations: tmpl.ObjectMeta.Annotations,
+							},
+							Spec: *spec,
+						},
+					},
 				},
 			})
-		} else {
+		case rp.Spec.WorkVolume != nil:
 			volumes = append(volumes, corev1.Volume{
 				Name:         workDir,
 				VolumeSource: *rp.Spec.WorkVolume,
 			})
+		default:
+			// use emptyDir (default)
+			volumes = append(volumes, corev1.Volume{
+				Name: workDir,
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			})
 		}
 
-		volumes = append(volumes, corev1.Volume{
-			Name: rp.GetRunnerSecretName(),
-			VolumeSource: corev1.VolumeSource{
-				Secret: &corev1.SecretVolumeSource{
-					SecretName: rp.GetRunnerSecretName(),
+		for i := range rp.Spec.Caches {
+			c := &rp.Spec.Caches[i]
+			volumes = append(volumes, corev1.Volume{
+				Name:         cacheVolumeName(c),
+				VolumeSource: cacheVolumeSource(rp, c),
+			})
+		}
+
+		if mode != nil {
+			volumes = append(volumes, mode.volumes...)
+			if mode.serviceAccountName != "" {
+				d.Spec.Template.Spec.ServiceAccountName = mode.serviceAccountName
+			}
+		}
+
+		if !rp.Spec.JITConfig {
+			volumes = append(volumes, corev1.Volume{
+				Name: rp.GetRunnerSecretName(),
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: rp.GetRunnerSecretName(),
+					},
 				},
-			},
-		})
+			})
+		}
+		if r.runnerAPIKey != nil {
+			volumes = append(volumes, corev1.Volume{
+				Name: rp.GetAPITokenSecretName(),
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: rp.GetAPITokenSecretName(),
+					},
+				},
+			})
+		}
 		d.Spec.Template.Spec.Volumes = volumes
 
 		d.Spec.Template.Spec.NodeSelector = rp.Spec.Template.NodeSelector
 		d.Spec.Template.Spec.Tolerations = rp.Spec.Template.Tolerations
+		d.Spec.Template.Spec.Affinity = rp.Spec.Template.Affinity
+		d.Spec.Template.Spec.TopologySpreadConstraints = rp.Spec.Template.TopologySpreadConstraints
+		d.Spec.Template.Spec.PriorityClassName = rp.Spec.Template.PriorityClassName
+		d.Spec.Template.Spec.RuntimeClassName = rp.Spec.Template.RuntimeClassName
+		d.Spec.Template.Spec.HostAliases = rp.Spec.Template.HostAliases
+		d.Spec.Template.Spec.DNSConfig = rp.Spec.Template.DNSConfig
+		if rp.Spec.Template.TerminationGracePeriodSeconds != nil {
+			d.Spec.Template.Spec.TerminationGracePeriodSeconds = rp.Spec.Template.TerminationGracePeriodSeconds
+		}
+		// Set the default values here not to update the Deployment in every reconciliation.
+		d.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirst
+		if rp.Spec.Template.DNSPolicy != "" {
+			d.Spec.Template.Spec.DNSPolicy = rp.Spec.Template.DNSPolicy
+		}
+		podSecurityContext := &corev1.PodSecurityContext{}
+		if rp.Spec.Template.SecurityContext != nil {
+			podSecurityContext = rp.Spec.Template.SecurityContext.DeepCopy()
+		}
+		if mode != nil {
+			for _, g := range mode.supplementalGroups {
+				if !slices.Contains(podSecurityContext.SupplementalGroups, g) {
+					podSecurityContext.SupplementalGroups = append(podSecurityContext.SupplementalGroups, g)
+				}
+			}
+		}
+		d.Spec.Template.Spec.SecurityContext = podSecurityContext
+		d.Spec.Template.Spec.InitContainers = mergeContainers(d.Spec.Template.Spec.InitContainers, rp.Spec.Template.InitContainers)
 
 		r.addRunnerContainerIfNotExists(d)
 		runnerContainer := r.findRunnerContainer(d)
@@ -358,11 +754,35 @@ func (r *RunnerPoolReconciler) reconcileDeployment(ctx context.Context, log logr
 			Name:      workDir,
 			MountPath: constants.RunnerWorkDirPath,
 		})
-		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      rp.GetRunnerSecretName(),
-			ReadOnly:  true,
-			MountPath: filepath.Join(constants.RunnerVarDirPath, constants.SecretsDirName),
-		})
+		if !rp.Spec.JITConfig {
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      rp.GetRunnerSecretName(),
+				ReadOnly:  true,
+				MountPath: filepath.Join(constants.RunnerVarDirPath, constants.SecretsDirName),
+			})
+		}
+		if r.runnerAPIKey != nil {
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      rp.GetAPITokenSecretName(),
+				ReadOnly:  true,
+				MountPath: filepath.Join(constants.RunnerVarDirPath, constants.APITokenDirName),
+			})
+		}
+		for i := range rp.Spec.Caches {
+			c := &rp.Spec.Caches[i]
+			mountPath := c.MountPath
+			if c.KeyByRepository {
+				// The directory of the repository is linked to the mount path by the job-started command.
+				mountPath = filepath.Join(constants.RepositoryCachesDirPath, c.Name)
+			}
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      cacheVolumeName(c),
+				MountPath: mountPath,
+			})
+		}
+		if mode != nil {
+			volumeMounts = append(volumeMounts, mode.runnerMounts...)
+		}
 		runnerContainer.VolumeMounts = volumeMounts
 
 		runnerContainer.EnvFrom = rp.Spec.Template.RunnerContainer.EnvFrom
@@ -373,6 +793,14 @@ func (r *RunnerPoolReconciler) reconcileDeployment(ctx context.Context, log logr
 		}
 		runnerContainer.Env = env
 
+		// The runner container is always the first container, followed by the additional containers.
+		containers := []corev1.Container{*runnerContainer}
+		additionalContainers := rp.Spec.Template.Containers
+		if mode != nil && mode.sidecar != nil {
+			additionalContainers = append(slices.Clone(additionalContainers), *mode.sidecar)
+		}
+		d.Spec.Template.Spec.Containers = append(containers, mergeContainers(d.Spec.Template.Spec.Containers, additionalContainers)...)
+
 		updated = d.Spec.DeepCopy()
 		return ctrl.SetControllerReference(rp, d, r.scheme)
 	})
@@ -392,6 +820,211 @@ func (r *RunnerPoolReconciler) reconcileDeployment(ctx context.Context, log logr
 	return nil
 }
 
+// mergeContainers returns the containers specified in the RunnerPool.
+// The current containers are kept if they are not changed, because they have the fields defaulted by the API server.
+func mergeContainers(current, desired []corev1.Container) []corev1.Container {
+	var containers []corev1.Container
+	for _, c := range desired {
+		idx := slices.IndexFunc(current, func(cur corev1.Container) bool { return cur.Name == c.Name })
+		if idx >= 0 && equality.Semantic.DeepDerivative(c, current[idx]) {
+			containers = append(containers, current[idx])
+			continue
+		}
+		containers = append(containers, c)
+	}
+	return containers
+}
+
+func cacheVolumeName(c *meowsv1alpha1.CacheConfig) string {
+	return "cache-" + c.Name
+}
+
+func cacheVolumeSource(rp *meowsv1alpha1.RunnerPool, c *meowsv1alpha1.CacheConfig) corev1.VolumeSource {
+	switch {
+	case c.HostPath != "":
+		return corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{
+				Path: c.HostPath,
+				Type: ptr.To(corev1.HostPathDirectoryOrCreate),
+			},
+		}
+	case c.ClaimName != "":
+		return corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: c.ClaimName,
+			},
+		}
+	default:
+		return corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: rp.GetCacheClaimName(c.Name),
+			},
+		}
+	}
+}
+
+func componentLabelSet(rp *meowsv1alpha1.RunnerPool, component string) map[string]string {
+	return map[string]string{
+		constants.AppNameLabelKey:      constants.AppName,
+		constants.AppComponentLabelKey: component,
+		constants.AppInstanceLabelKey:  rp.Name,
+	}
+}
+
+// reconcileCaches creates the PersistentVolumeClaims of the caches and the CronJob to evict them.
+func (r *RunnerPoolReconciler) reconcileCaches(ctx context.Context, log logr.Logger, rp *meowsv1alpha1.RunnerPool) error {
+	claims := map[string]bool{}
+	for i := range rp.Spec.Caches {
+		c := &rp.Spec.Caches[i]
+		if c.VolumeClaimSpec == nil {
+			continue
+		}
+		name := rp.GetCacheClaimName(c.Name)
+		claims[name] = true
+
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := r.Get(ctx, types.NamespacedName{Namespace: rp.Namespace, Name: name}, pvc)
+		if err == nil {
+			// Most fields of the PersistentVolumeClaim are immutable, so the existing claim is kept as it is.
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		pvc.SetNamespace(rp.Namespace)
+		pvc.SetName(name)
+		pvc.Labels = componentLabelSet(rp, constants.AppComponentCache)
+		pvc.Spec = *c.VolumeClaimSpec.DeepCopy()
+		if err := ctrl.SetControllerReference(rp, pvc, r.scheme); err != nil {
+			return err
+		}
+		if err := r.Create(ctx, pvc); err != nil {
+			return err
+		}
+		log.Info("created persistent volume claim for cache", "name", name)
+	}
+
+	// Delete the claims of the caches removed from the RunnerPool.
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	err := r.List(ctx, pvcList, client.InNamespace(rp.Namespace), client.MatchingLabels(componentLabelSet(rp, constants.AppComponentCache)))
+	if err != nil {
+		return err
+	}
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if claims[pvc.Name] || !metav1.IsControlledBy(pvc, rp) {
+			continue
+		}
+		if err := r.Delete(ctx, pvc); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		log.Info("deleted persistent volume claim for cache", "name", pvc.Name)
+	}
+
+	return r.reconcileCacheEvictionCronJob(ctx, log, rp)
+}
+
+func (r *RunnerPoolReconciler) reconcileCacheEvictionCronJob(ctx context.Context, log logr.Logger, rp *meowsv1alpha1.RunnerPool) error {
+	cj := &batchv1.CronJob{}
+	cj.SetNamespace(rp.Namespace)
+	cj.SetName(rp.GetCacheEvictionCronJobName())
+
+	image := rp.Spec.Template.RunnerContainer.Image
+	if image == "" {
+		image = r.runnerImage
+	}
+	var volumes []corev1.Volume
+	var containers []corev1.Container
+	for i := range rp.Spec.Caches {
+		c := &rp.Spec.Caches[i]
+		if c.SizeLimit == nil {
+			continue
+		}
+		dir := filepath.Join(constants.RepositoryCachesDirPath, c.Name)
+		depth := 1
+		if c.KeyByRepository {
+			depth = 2
+		}
+		volumes = append(volumes, corev1.Volume{
+			Name:         cacheVolumeName(c),
+			VolumeSource: cacheVolumeSource(rp, c),
+		})
+		containers = append(containers, corev1.Container{
+			Name:            "evict-" + c.Name,
+			Image:           image,
+			ImagePullPolicy: rp.Spec.Template.RunnerContainer.ImagePullPolicy,
+			Command: []string{
+				"meows", "cache", "evict",
+				"--size-limit", c.SizeLimit.String(),
+				"--depth", strconv.Itoa(depth),
+				dir,
+			},
+			// Run as the same user as the runner to remove the files created by the jobs.
+			SecurityContext: rp.Spec.Template.RunnerContainer.SecurityContext,
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      cacheVolumeName(c),
+					MountPath: dir,
+				},
+			},
+		})
+	}
+
+	if len(containers) == 0 {
+		err := r.Delete(ctx, cj)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		log.Info("deleted cronjob for cache eviction")
+		return nil
+	}
+
+	labels := componentLabelSet(rp, constants.AppComponentCacheEviction)
+	spec := batchv1.CronJobSpec{
+		Schedule:          cacheEvictionSchedule,
+		ConcurrencyPolicy: batchv1.ForbidConcurrent,
+		JobTemplate: batchv1.JobTemplateSpec{
+			Spec: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: labels,
+					},
+					Spec: corev1.PodSpec{
+						RestartPolicy:                corev1.RestartPolicyOnFailure,
+						AutomountServiceAccountToken: ptr.To(false),
+						ImagePullSecrets:             rp.Spec.Template.ImagePullSecrets,
+						NodeSelector:                 rp.Spec.Template.NodeSelector,
+						Tolerations:                  rp.Spec.Template.Tolerations,
+						SecurityContext:              rp.Spec.Template.SecurityContext,
+						Volumes:                      volumes,
+						Containers:                   containers,
+					},
+				},
+			},
+		},
+	}
+
+	op, err := ctrl.CreateOrUpdate(ctx, r.Client, cj, func() error {
+		cj.Labels = mergeMap(cj.GetLabels(), labels)
+		// Compare ignoring the fields defaulted by the API server not to update the CronJob in every reconciliation.
+		if !equality.Semantic.DeepDerivative(spec, cj.Spec) {
+			cj.Spec = spec
+		}
+		return ctrl.SetControllerReference(rp, cj, r.scheme)
+	})
+	if err != nil {
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		log.Info("reconciled cronjob for cache eviction", "operation", string(op))
+	}
+	return nil
+}
+
 func (r *RunnerPoolReconciler) findRunnerContainer(d *appsv1.Deployment) *corev1.Container {
 	for i := range d.Spec.Template.Spec.Containers {
 		c := &d.Spec.Template.Spec.Containers[i]
@@ -418,6 +1051,13 @@ func (r *RunnerPoolReconciler) addRunnerContainerIfNotExists(d *appsv1.Deploymen
 func (r *RunnerPoolReconciler) makeRunnerContainerEnv(rp *meowsv1alpha1.RunnerPool) ([]corev1.EnvVar, error) {
 	option := runner.Option{
 		SetupCommand: rp.Spec.SetupCommand,
+		Labels:       rp.Spec.Labels,
+		RunnerGroup:  rp.Spec.RunnerGroup,
+		GitHubURL:    rp.Spec.GitHubURL,
+		JITConfig:    rp.Spec.JITConfig,
+
+		TerminationGracePeriodSeconds: rp.Spec.Template.TerminationGracePeriodSeconds,
+		MaxListenerRetries:            rp.Spec.MaxListenerRetries,
 	}
 	optionJson, err := json.Marshal(&option)
 	if err != nil {
@@ -453,18 +1093,49 @@ func (r *RunnerPoolReconciler) makeRunnerContainerEnv(rp *meowsv1alpha1.RunnerPo
 		},
 	}
 
-	if rp.IsOrgLevel() {
+	switch {
+	case rp.IsEnterpriseLevel():
+		envs = append(envs, corev1.EnvVar{
+			Name:  constants.RunnerEnterpriseEnvName,
+			Value: rp.Spec.Enterprise,
+		})
+	case rp.IsOrgLevel():
 		envs = append(envs, corev1.EnvVar{
 			Name:  constants.RunnerOrgEnvName,
 			Value: rp.Spec.Organization,
 		})
-	} else {
+	default:
 		envs = append(envs, corev1.EnvVar{
 			Name:  constants.RunnerRepoEnvName,
 			Value: rp.Spec.Repository,
 		})
 	}
 
+	if mode := makeContainerMode(rp); mode != nil {
+		envs = append(envs, mode.runnerEnv...)
+	}
+
+	var caches []runner.RepositoryCache
+	for _, c := range rp.Spec.Caches {
+		switch {
+		case c.KeyByRepository:
+			caches = append(caches, runner.RepositoryCache{Name: c.Name, MountPath: c.MountPath})
+		case c.SizeLimit != nil:
+			// The shared cache is stamped for the eviction.
+			caches = append(caches, runner.RepositoryCache{Name: c.Name, MountPath: c.MountPath, Shared: true})
+		}
+	}
+	if len(caches) != 0 {
+		cachesJson, err := json.Marshal(caches)
+		if err != nil {
+			return nil, err
+		}
+		envs = append(envs, corev1.EnvVar{
+			Name:  constants.RepositoryCachesEnvName,
+			Value: string(cachesJson),
+		})
+	}
+
 	// NOTE:
 	// We need not ignore the reserved environment variables here.
 	// Since the reserved environment variables are checked in the validating webhook.
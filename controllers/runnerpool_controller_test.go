@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"errors"
+	"os"
 	"path/filepath"
 	"regexp"
 	"time"
@@ -10,11 +11,16 @@ import (
 	constants "github.com/cybozu-go/meows"
 	meowsv1alpha1 "github.com/cybozu-go/meows/api/v1alpha1"
 	"github.com/cybozu-go/meows/github"
+	"github.com/cybozu-go/meows/runner"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -102,14 +108,19 @@ func (m *secretUpdaterMock) StopAll() {
 
 var _ = Describe("RunnerPool reconciler", func() {
 	namespace := "runnerpool-ns"
+	sharedNamespace := "runnerpool-shared-ns"
+	tenantNamespace := "runnerpool-tenant-ns"
 	runnerPoolName := "runnerpool-1"
 	secretName := "runner-token-" + runnerPoolName
+	apiTokenSecretName := "runner-api-token-" + runnerPoolName
+	runnerAPIKey := []byte("0123456789abcdef0123456789abcdef")
 	deploymentName := "runnerpool-1"
 	defaultRunnerImage := "sample:latest"
 	serviceAccountName := "customized-sa"
 	wait := 10 * time.Second
 	var mockManager *runnerManagerMock
 	var mockUpdater *secretUpdaterMock
+	var reconciler *RunnerPoolReconciler
 
 	ctx := context.Background()
 	var mgrCtx context.Context
@@ -129,6 +140,33 @@ var _ = Describe("RunnerPool reconciler", func() {
 		mockManager = newRunnerManagerMock()
 		mockUpdater = newSecretUpdaterMock(mgr.GetClient())
 
+		credDir := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(credDir, "token"), []byte("file-pat"), 0600)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(credDir, namespace, "github-pat"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(credDir, namespace, "github-pat", "token"), []byte("provided-pat"), 0600)).To(Succeed())
+		sharedCredentials := &SharedCredentials{
+			Namespace: sharedNamespace,
+			Default:   "default-cred",
+			Credentials: map[string]*SharedCredential{
+				"default-cred": {
+					SecretName:    "shared-default-cred",
+					NamespaceRule: regexp.MustCompile(`^` + tenantNamespace + `$`),
+				},
+				"shared-cred": {
+					SecretName:    "shared-cred",
+					NamespaceRule: regexp.MustCompile(`^` + namespace + `$`),
+				},
+				"file-cred": {
+					Path:          credDir,
+					NamespaceRule: regexp.MustCompile(`^` + namespace + `$`),
+				},
+				"unpermitted-cred": {
+					SecretName:    "shared-cred",
+					NamespaceRule: regexp.MustCompile(`^` + tenantNamespace + `$`),
+				},
+			},
+		}
+
 		r := NewRunnerPoolReconciler(
 			ctrl.Log,
 			mgr.GetClient(),
@@ -138,8 +176,15 @@ var _ = Describe("RunnerPool reconciler", func() {
 			SecretUpdater(mockUpdater),
 			regexp.MustCompile(`^test-org$`),
 			regexp.MustCompile(`^test-org/.*`),
+			regexp.MustCompile(`^test-enterprise$`),
+			sharedCredentials,
+			map[string]CredentialProvider{
+				"file": NewFileCredentialProvider(credDir),
+			},
+			runnerAPIKey,
 		)
 		Expect(r.SetupWithManager(mgr)).To(Succeed())
+		reconciler = r
 
 		mgrCtx, mgrCancel = context.WithCancel(context.Background())
 		go func() {
@@ -178,6 +223,23 @@ var _ = Describe("RunnerPool reconciler", func() {
 			"token": "dummy-pat",
 		}
 		Expect(k8sClient.Create(ctx, patSecret)).To(Succeed())
+
+		By("creating shared credential secrets")
+		createNamespaces(ctx, sharedNamespace, tenantNamespace)
+		sharedSecret := new(corev1.Secret)
+		sharedSecret.SetName("shared-cred")
+		sharedSecret.SetNamespace(sharedNamespace)
+		sharedSecret.StringData = map[string]string{
+			"token": "shared-pat",
+		}
+		Expect(k8sClient.Create(ctx, sharedSecret)).To(Succeed())
+		defaultSecret := new(corev1.Secret)
+		defaultSecret.SetName("shared-default-cred")
+		defaultSecret.SetNamespace(sharedNamespace)
+		defaultSecret.StringData = map[string]string{
+			"token": "default-pat",
+		}
+		Expect(k8sClient.Create(ctx, defaultSecret)).To(Succeed())
 	})
 
 	It("should create Deployment from minimal RunnerPool", func() {
@@ -210,6 +272,14 @@ var _ = Describe("RunnerPool reconciler", func() {
 			"Name": Equal(runnerPoolName),
 		}))
 
+		By("getting the created Secret for the api token")
+		apiToken := new(corev1.Secret)
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: apiTokenSecretName, Namespace: namespace}, apiToken)).To(Succeed())
+		Expect(apiToken.OwnerReferences).To(HaveLen(1))
+		Expect(apiToken.Data).To(MatchAllKeys(Keys{
+			constants.APITokenFileName: Equal([]byte(runner.APIToken(runnerAPIKey, namespace, runnerPoolName))),
+		}))
+
 		By("getting the created Deployment")
 		d := new(appsv1.Deployment)
 		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, d)).To(Succeed())
@@ -254,6 +324,9 @@ var _ = Describe("RunnerPool reconciler", func() {
 				"2": MatchFields(IgnoreExtras, Fields{
 					"Name": Equal(secretName),
 				}),
+				"3": MatchFields(IgnoreExtras, Fields{
+					"Name": Equal(apiTokenSecretName),
+				}),
 			}),
 			"NodeSelector": HaveLen(0),
 			"Tolerations":  HaveLen(0),
@@ -322,6 +395,10 @@ var _ = Describe("RunnerPool reconciler", func() {
 					"Name":      Equal(secretName),
 					"MountPath": Equal(filepath.Join(constants.RunnerVarDirPath, constants.SecretsDirName)),
 				}),
+				"3": MatchFields(IgnoreExtras, Fields{
+					"Name":      Equal(apiTokenSecretName),
+					"MountPath": Equal(filepath.Join(constants.RunnerVarDirPath, constants.APITokenDirName)),
+				}),
 			}),
 		}))
 
@@ -338,10 +415,21 @@ var _ = Describe("RunnerPool reconciler", func() {
 
 		By("checking credentials have been passed to sub-processes")
 		Expect(mockManager.githubCreds[namespace+"/"+runnerPoolName]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"GitHubURL":         Equal("https://github.com"),
 			"AppID":             Equal(int64(1234)),
 			"AppInstallationID": Equal(int64(5678)),
 			"PrivateKey":        Equal([]byte("dummy-private-key")),
 		})))
+
+		By("checking the conditions")
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(rp.Status.Conditions, meowsv1alpha1.ConditionCredentialReady)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(rp.Status.Conditions, meowsv1alpha1.ConditionRegistrationTokenReady)).To(BeTrue())
+		// The deployment controller is not running in this test.
+		Expect(meta.FindStatusCondition(rp.Status.Conditions, meowsv1alpha1.ConditionDeploymentAvailable)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Status": Equal(metav1.ConditionUnknown),
+			"Reason": Equal("DeploymentProgressing"),
+		})))
 		Expect(mockUpdater.githubCreds[namespace+"/"+runnerPoolName]).To(PointTo(MatchFields(IgnoreExtras, Fields{
 			"AppID":             Equal(int64(1234)),
 			"AppInstallationID": Equal(int64(5678)),
@@ -363,6 +451,10 @@ var _ = Describe("RunnerPool reconciler", func() {
 		rp.Spec.CredentialSecretName = "github-cred-foo"
 		rp.Spec.Replicas = 3
 		rp.Spec.SetupCommand = []string{"command", "arg1", "args2"}
+		rp.Spec.Labels = []string{"large", "ubuntu-22.04"}
+		rp.Spec.RunnerGroup = "test-group"
+		rp.Spec.GitHubURL = "https://github.example.com"
+		rp.Spec.MaxListenerRetries = ptr.To[int32](3)
 		rp.Spec.Notification.Slack.Enable = true
 		rp.Spec.Notification.Slack.Channel = "#test"
 		rp.Spec.Notification.ExtendDuration = "20m"
@@ -498,6 +590,9 @@ var _ = Describe("RunnerPool reconciler", func() {
 				"4": MatchFields(IgnoreExtras, Fields{
 					"Name": Equal(secretName),
 				}),
+				"5": MatchFields(IgnoreExtras, Fields{
+					"Name": Equal(apiTokenSecretName),
+				}),
 			}),
 			"NodeSelector": MatchAllKeys(Keys{
 				"kubernetes.io/hostname": Equal("worker"),
@@ -564,7 +659,7 @@ var _ = Describe("RunnerPool reconciler", func() {
 				}),
 				"3": MatchFields(IgnoreExtras, Fields{
 					"Name":  Equal(constants.RunnerOptionEnvName),
-					"Value": Equal("{\"setup_command\":[\"command\",\"arg1\",\"args2\"]}"),
+					"Value": Equal("{\"setup_command\":[\"command\",\"arg1\",\"args2\"],\"labels\":[\"large\",\"ubuntu-22.04\"],\"runner_group\":\"test-group\",\"github_url\":\"https://github.example.com\",\"max_listener_retries\":3}"),
 				}),
 				"4": MatchFields(IgnoreExtras, Fields{
 					"Name":  Equal(constants.RunnerOrgEnvName),
@@ -610,6 +705,10 @@ var _ = Describe("RunnerPool reconciler", func() {
 					"Name":      Equal(secretName),
 					"MountPath": Equal(filepath.Join(constants.RunnerVarDirPath, constants.SecretsDirName)),
 				}),
+				"5": MatchFields(IgnoreExtras, Fields{
+					"Name":      Equal(apiTokenSecretName),
+					"MountPath": Equal(filepath.Join(constants.RunnerVarDirPath, constants.APITokenDirName)),
+				}),
 			}),
 		}))
 
@@ -626,6 +725,7 @@ var _ = Describe("RunnerPool reconciler", func() {
 
 		By("checking credentials have been passed to sub-processes")
 		Expect(mockManager.githubCreds[namespace+"/"+runnerPoolName]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"GitHubURL":           Equal("https://github.example.com"),
 			"PersonalAccessToken": Equal("dummy-pat"),
 		})))
 		Expect(mockUpdater.githubCreds[namespace+"/"+runnerPoolName]).To(PointTo(MatchFields(IgnoreExtras, Fields{
@@ -640,6 +740,936 @@ var _ = Describe("RunnerPool reconciler", func() {
 		Expect(mockUpdater.started).NotTo(HaveKey(namespace + "/" + runnerPoolName))
 	})
 
+	It("should report the condition when the credential secret is not found", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.CredentialSecretName = "not-found"
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("checking the conditions")
+		Eventually(func(g Gomega) {
+			rp := new(meowsv1alpha1.RunnerPool)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp)).To(Succeed())
+			g.Expect(meta.FindStatusCondition(rp.Status.Conditions, meowsv1alpha1.ConditionCredentialReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Status": Equal(metav1.ConditionFalse),
+				"Reason": Equal("GetCredentialFailed"),
+			})))
+			g.Expect(rp.Status.Bound).To(BeFalse())
+		}).Should(Succeed())
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
+
+	It("should create Deployment with the additional pod template fields", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.Template.InitContainers = []corev1.Container{
+			{Name: "init", Image: "busybox", Command: []string{"true"}},
+		}
+		rp.Spec.Template.Containers = []corev1.Container{
+			{
+				Name:  "dind",
+				Image: "docker:dind",
+				SecurityContext: &corev1.SecurityContext{
+					Privileged: ptr.To(true),
+				},
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: constants.WorkDirVolumeName, MountPath: constants.RunnerWorkDirPath},
+				},
+			},
+		}
+		rp.Spec.Template.Affinity = &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "kubernetes.io/arch", Operator: corev1.NodeSelectorOpIn, Values: []string{"amd64"}},
+							},
+						},
+					},
+				},
+			},
+		}
+		rp.Spec.Template.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{
+			{
+				MaxSkew:           1,
+				TopologyKey:       "kubernetes.io/hostname",
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{constants.AppInstanceLabelKey: runnerPoolName},
+				},
+			},
+		}
+		rp.Spec.Template.PriorityClassName = "high-priority"
+		rp.Spec.Template.RuntimeClassName = ptr.To("gvisor")
+		rp.Spec.Template.HostAliases = []corev1.HostAlias{
+			{IP: "10.0.0.1", Hostnames: []string{"registry.example.com"}},
+		}
+		rp.Spec.Template.DNSPolicy = corev1.DNSNone
+		rp.Spec.Template.DNSConfig = &corev1.PodDNSConfig{
+			Nameservers: []string{"10.0.0.53"},
+		}
+		rp.Spec.Template.SecurityContext = &corev1.PodSecurityContext{
+			FSGroup: ptr.To[int64](10000),
+		}
+		rp.Spec.Template.TerminationGracePeriodSeconds = ptr.To[int64](3600)
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("getting the created Deployment")
+		d := new(appsv1.Deployment)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, d)
+		}).Should(Succeed())
+
+		By("checking the pod template")
+		Expect(d.Spec.Template.Spec).To(MatchFields(IgnoreExtras, Fields{
+			"InitContainers": MatchAllElementsWithIndex(IndexIdentity, Elements{
+				"0": MatchFields(IgnoreExtras, Fields{
+					"Name":    Equal("init"),
+					"Command": Equal([]string{"true"}),
+				}),
+			}),
+			"Containers": MatchAllElementsWithIndex(IndexIdentity, Elements{
+				"0": MatchFields(IgnoreExtras, Fields{
+					"Name": Equal(constants.RunnerContainerName),
+				}),
+				"1": MatchFields(IgnoreExtras, Fields{
+					"Name":  Equal("dind"),
+					"Image": Equal("docker:dind"),
+					"VolumeMounts": ConsistOf(corev1.VolumeMount{
+						Name:      constants.WorkDirVolumeName,
+						MountPath: constants.RunnerWorkDirPath,
+					}),
+				}),
+			}),
+			"Affinity":                      Equal(rp.Spec.Template.Affinity),
+			"TopologySpreadConstraints":     Equal(rp.Spec.Template.TopologySpreadConstraints),
+			"PriorityClassName":             Equal("high-priority"),
+			"RuntimeClassName":              PointTo(Equal("gvisor")),
+			"HostAliases":                   Equal(rp.Spec.Template.HostAliases),
+			"DNSPolicy":                     Equal(corev1.DNSNone),
+			"DNSConfig":                     Equal(rp.Spec.Template.DNSConfig),
+			"SecurityContext":               Equal(rp.Spec.Template.SecurityContext),
+			"TerminationGracePeriodSeconds": PointTo(BeNumerically("==", 3600)),
+		}))
+		Expect(d.Spec.Template.Spec.Containers[0].Env).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Name":  Equal(constants.RunnerOptionEnvName),
+			"Value": ContainSubstring(`"termination_grace_period_seconds":3600`),
+		})))
+
+		By("checking the Deployment is not updated by the following reconciliations")
+		Eventually(func() error {
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp); err != nil {
+				return err
+			}
+			rp.Annotations = map[string]string{"reconcile": "again"}
+			return k8sClient.Update(ctx, rp)
+		}).Should(Succeed())
+		Consistently(func(g Gomega) {
+			current := new(appsv1.Deployment)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, current)).To(Succeed())
+			g.Expect(current.Generation).To(Equal(d.Generation))
+		}, wait).Should(Succeed())
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
+
+	It("should create Deployment with the dind container mode", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.ContainerMode = meowsv1alpha1.ContainerModeDind
+		rp.Spec.Sidecar.Resources = corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("1"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("getting the created Deployment")
+		d := new(appsv1.Deployment)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, d)
+		}).Should(Succeed())
+
+		By("checking the sidecar and the runner container")
+		podSpec := d.Spec.Template.Spec
+		Expect(podSpec.Volumes).To(ContainElements(
+			MatchFields(IgnoreExtras, Fields{"Name": Equal("docker-certs")}),
+			MatchFields(IgnoreExtras, Fields{"Name": Equal("docker-data")}),
+		))
+		Expect(podSpec.Containers).To(HaveLen(2))
+		Expect(podSpec.Containers[1]).To(MatchFields(IgnoreExtras, Fields{
+			"Name":  Equal(constants.DockerContainerName),
+			"Image": Equal(constants.DefaultDockerImage),
+			"SecurityContext": PointTo(MatchFields(IgnoreExtras, Fields{
+				"Privileged": PointTo(BeTrue()),
+			})),
+			"Resources": Equal(rp.Spec.Sidecar.Resources),
+			"VolumeMounts": ConsistOf(
+				corev1.VolumeMount{Name: "docker-certs", MountPath: "/certs"},
+				corev1.VolumeMount{Name: "docker-data", MountPath: "/var/lib/docker"},
+				corev1.VolumeMount{Name: "work-dir", MountPath: constants.RunnerWorkDirPath},
+			),
+		}))
+		runnerContainer := podSpec.Containers[0]
+		Expect(runnerContainer.VolumeMounts).To(ContainElement(
+			corev1.VolumeMount{Name: "docker-certs", MountPath: "/certs", ReadOnly: true},
+		))
+		Expect(runnerContainer.Env).To(ContainElements(
+			corev1.EnvVar{Name: "DOCKER_HOST", Value: "tcp://localhost:2376"},
+			corev1.EnvVar{Name: "DOCKER_TLS_VERIFY", Value: "1"},
+			corev1.EnvVar{Name: "DOCKER_CERT_PATH", Value: "/certs/client"},
+		))
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
+
+	It("should create Deployment with the rootless-buildkit container mode", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.ContainerMode = meowsv1alpha1.ContainerModeRootlessBuildkit
+		rp.Spec.Sidecar.Image = "moby/buildkit:master-rootless"
+		rp.Spec.Template.SecurityContext = &corev1.PodSecurityContext{
+			SupplementalGroups: []int64{2000},
+		}
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("getting the created Deployment")
+		d := new(appsv1.Deployment)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, d)
+		}).Should(Succeed())
+
+		By("checking the sidecar and the runner container")
+		podSpec := d.Spec.Template.Spec
+		Expect(podSpec.SecurityContext.SupplementalGroups).To(Equal([]int64{2000, 1000}))
+		Expect(podSpec.Volumes).To(ContainElements(
+			MatchFields(IgnoreExtras, Fields{"Name": Equal("buildkit-socket")}),
+			MatchFields(IgnoreExtras, Fields{"Name": Equal("buildkit-data")}),
+		))
+		Expect(podSpec.Containers).To(HaveLen(2))
+		Expect(podSpec.Containers[1]).To(MatchFields(IgnoreExtras, Fields{
+			"Name":  Equal(constants.BuildkitContainerName),
+			"Image": Equal("moby/buildkit:master-rootless"),
+			"Args":  ContainElement("unix:///run/buildkit/buildkitd.sock"),
+			"SecurityContext": PointTo(MatchFields(IgnoreExtras, Fields{
+				"RunAsUser":  PointTo(BeNumerically("==", 1000)),
+				"Privileged": BeNil(),
+			})),
+		}))
+		runnerContainer := podSpec.Containers[0]
+		Expect(runnerContainer.VolumeMounts).To(ContainElement(
+			corev1.VolumeMount{Name: "buildkit-socket", MountPath: "/run/buildkit"},
+		))
+		Expect(runnerContainer.Env).To(ContainElement(
+			corev1.EnvVar{Name: "BUILDKIT_HOST", Value: "unix:///run/buildkit/buildkitd.sock"},
+		))
+
+		By("disabling the container mode")
+		Eventually(func() error {
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp); err != nil {
+				return err
+			}
+			rp.Spec.ContainerMode = meowsv1alpha1.ContainerModeNone
+			return k8sClient.Update(ctx, rp)
+		}).Should(Succeed())
+		Eventually(func(g Gomega) {
+			d := new(appsv1.Deployment)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, d)).To(Succeed())
+			g.Expect(d.Spec.Template.Spec.Containers).To(HaveLen(1))
+			g.Expect(d.Spec.Template.Spec.SecurityContext.SupplementalGroups).To(Equal([]int64{2000}))
+		}).Should(Succeed())
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
+
+	It("should create Deployment with the kubernetes container mode", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.ContainerMode = meowsv1alpha1.ContainerModeKubernetes
+		rp.Spec.SharedCredentialName = "shared-cred"
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("getting the created Deployment")
+		d := new(appsv1.Deployment)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, d)
+		}).Should(Succeed())
+
+		By("checking the runner pod")
+		saName := runnerPoolName + "-container-hooks"
+		podSpec := d.Spec.Template.Spec
+		Expect(podSpec.ServiceAccountName).To(Equal(saName))
+		Expect(podSpec.Containers).To(HaveLen(1))
+		Expect(podSpec.Volumes).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal("work-dir"),
+			"VolumeSource": MatchFields(IgnoreExtras, Fields{
+				"Ephemeral": PointTo(MatchFields(IgnoreExtras, Fields{
+					"VolumeClaimTemplate": PointTo(MatchFields(IgnoreExtras, Fields{
+						"Spec": MatchFields(IgnoreExtras, Fields{
+							"AccessModes": Equal([]corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}),
+							"Resources": MatchFields(IgnoreExtras, Fields{
+								"Requests": HaveKeyWithValue(corev1.ResourceStorage, resource.MustParse("10Gi")),
+							}),
+						}),
+					})),
+				})),
+			}),
+		})))
+		Expect(podSpec.Containers[0].Env).To(ContainElements(
+			corev1.EnvVar{Name: "ACTIONS_RUNNER_CONTAINER_HOOKS", Value: constants.RunnerContainerHooksPath},
+			corev1.EnvVar{Name: "ACTIONS_RUNNER_POD_NAME", Value: "$(POD_NAME)"},
+			corev1.EnvVar{Name: "ACTIONS_RUNNER_CLAIM_NAME", Value: "$(POD_NAME)-work-dir"},
+		))
+
+		By("checking the permissions for the container hooks")
+		Eventually(func(g Gomega) {
+			sa := new(corev1.ServiceAccount)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: saName, Namespace: namespace}, sa)).To(Succeed())
+			g.Expect(sa.OwnerReferences).To(HaveLen(1))
+
+			role := new(rbacv1.Role)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: saName, Namespace: namespace}, role)).To(Succeed())
+			g.Expect(role.Rules).To(ContainElements(
+				MatchFields(IgnoreExtras, Fields{
+					"Resources": Equal([]string{"pods"}),
+					"Verbs":     ConsistOf("get", "list", "create", "delete"),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Resources": Equal([]string{"pods/exec"}),
+					"Verbs":     ConsistOf("get", "create"),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Resources": Equal([]string{"secrets"}),
+					"Verbs":     ConsistOf("create", "delete"),
+				}),
+			))
+
+			rb := new(rbacv1.RoleBinding)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: saName, Namespace: namespace}, rb)).To(Succeed())
+			g.Expect(rb.RoleRef.Name).To(Equal(saName))
+			g.Expect(rb.Subjects).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Kind":      Equal("ServiceAccount"),
+				"Name":      Equal(saName),
+				"Namespace": Equal(namespace),
+			})))
+		}).Should(Succeed())
+
+		By("disabling the container mode")
+		Eventually(func() error {
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp); err != nil {
+				return err
+			}
+			rp.Spec.ContainerMode = meowsv1alpha1.ContainerModeNone
+			return k8sClient.Update(ctx, rp)
+		}).Should(Succeed())
+		Eventually(func(g Gomega) {
+			d := new(appsv1.Deployment)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, d)).To(Succeed())
+			g.Expect(d.Spec.Template.Spec.ServiceAccountName).To(BeEmpty())
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: saName, Namespace: namespace}, new(rbacv1.Role))
+			g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: saName, Namespace: namespace}, new(corev1.ServiceAccount))
+			g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		}).Should(Succeed())
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
+
+	It("should not delete the objects for the container hooks owned by others", func() {
+		By("creating the objects with the same name as the container hooks")
+		saName := runnerPoolName + "-container-hooks"
+		sa := &corev1.ServiceAccount{}
+		sa.Name = saName
+		sa.Namespace = namespace
+		Expect(k8sClient.Create(ctx, sa)).To(Succeed())
+		role := &rbacv1.Role{}
+		role.Name = saName
+		role.Namespace = namespace
+		Expect(k8sClient.Create(ctx, role)).To(Succeed())
+
+		By("deploying RunnerPool resource without the kubernetes container mode")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+		Eventually(func(g Gomega) {
+			rp := new(meowsv1alpha1.RunnerPool)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp)).To(Succeed())
+			g.Expect(rp.Status.Bound).To(BeTrue())
+		}).Should(Succeed())
+
+		By("checking the objects are not deleted")
+		Consistently(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: saName, Namespace: namespace}, new(corev1.ServiceAccount))).To(Succeed())
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: saName, Namespace: namespace}, new(rbacv1.Role))).To(Succeed())
+		}, 2*time.Second).Should(Succeed())
+
+		By("deleting the created resources")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+		Expect(k8sClient.Delete(ctx, sa)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, role)).To(Succeed())
+	})
+
+	It("should create Deployment with the work volume claim template", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.WorkVolumeClaimTemplate = &meowsv1alpha1.WorkVolumeClaimTemplate{
+			ObjectMeta: meowsv1alpha1.ObjectMeta{
+				Labels: map[string]string{"test-label": "test"},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				StorageClassName: ptr.To("fast"),
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse("10Gi"),
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("getting the created Deployment")
+		d := new(appsv1.Deployment)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, d)
+		}).Should(Succeed())
+
+		By("checking the work volume")
+		Expect(d.Spec.Template.Spec.Volumes).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal("work-dir"),
+			"VolumeSource": MatchFields(IgnoreExtras, Fields{
+				"Ephemeral": PointTo(MatchFields(IgnoreExtras, Fields{
+					"VolumeClaimTemplate": PointTo(MatchFields(IgnoreExtras, Fields{
+						"ObjectMeta": MatchFields(IgnoreExtras, Fields{
+							"Labels": MatchAllKeys(Keys{
+								constants.AppNameLabelKey:      Equal(constants.AppName),
+								constants.AppComponentLabelKey: Equal(constants.AppComponentRunner),
+								constants.AppInstanceLabelKey:  Equal(runnerPoolName),
+								"test-label":                   Equal("test"),
+							}),
+						}),
+						"Spec": MatchFields(IgnoreExtras, Fields{
+							"AccessModes":      ConsistOf(corev1.ReadWriteOnce),
+							"StorageClassName": PointTo(Equal("fast")),
+						}),
+					})),
+				})),
+			}),
+		})))
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
+
+	It("should mount the caches and create the cache eviction CronJob", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.Template.RunnerContainer.SecurityContext = &corev1.SecurityContext{
+			RunAsUser: ptr.To[int64](10000),
+		}
+		rp.Spec.Caches = []meowsv1alpha1.CacheConfig{
+			{
+				Name:            "go-mod",
+				MountPath:       "/home/runner/go/pkg/mod",
+				HostPath:        "/var/cache/meows/go-mod",
+				KeyByRepository: true,
+			},
+			{
+				Name:      "npm",
+				MountPath: "/home/runner/.npm",
+				ClaimName: "npm-cache",
+				SizeLimit: ptr.To(resource.MustParse("10Gi")),
+			},
+			{
+				Name:      "ccache",
+				MountPath: "/home/runner/.ccache",
+				VolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse("20Gi"),
+						},
+					},
+				},
+				KeyByRepository: true,
+				SizeLimit:       ptr.To(resource.MustParse("15Gi")),
+			},
+		}
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("getting the created Deployment")
+		d := new(appsv1.Deployment)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, d)
+		}).Should(Succeed())
+
+		By("checking the cache volumes")
+		Expect(d.Spec.Template.Spec.Volumes).To(ContainElements(
+			MatchFields(IgnoreExtras, Fields{
+				"Name": Equal("cache-go-mod"),
+				"VolumeSource": MatchFields(IgnoreExtras, Fields{
+					"HostPath": PointTo(MatchFields(IgnoreExtras, Fields{
+						"Path": Equal("/var/cache/meows/go-mod"),
+					})),
+				}),
+			}),
+			MatchFields(IgnoreExtras, Fields{
+				"Name": Equal("cache-npm"),
+				"VolumeSource": MatchFields(IgnoreExtras, Fields{
+					"PersistentVolumeClaim": PointTo(MatchFields(IgnoreExtras, Fields{
+						"ClaimName": Equal("npm-cache"),
+					})),
+				}),
+			}),
+			MatchFields(IgnoreExtras, Fields{
+				"Name": Equal("cache-ccache"),
+				"VolumeSource": MatchFields(IgnoreExtras, Fields{
+					"PersistentVolumeClaim": PointTo(MatchFields(IgnoreExtras, Fields{
+						"ClaimName": Equal(runnerPoolName + "-cache-ccache"),
+					})),
+				}),
+			}),
+		))
+		c := d.Spec.Template.Spec.Containers[0]
+		Expect(c.VolumeMounts).To(ContainElements(
+			corev1.VolumeMount{Name: "cache-go-mod", MountPath: "/meows-caches/go-mod"},
+			corev1.VolumeMount{Name: "cache-npm", MountPath: "/home/runner/.npm"},
+			corev1.VolumeMount{Name: "cache-ccache", MountPath: "/meows-caches/ccache"},
+		))
+		Expect(c.Env).To(ContainElement(corev1.EnvVar{
+			Name:  constants.RepositoryCachesEnvName,
+			Value: `[{"name":"go-mod","mount_path":"/home/runner/go/pkg/mod"},{"name":"npm","mount_path":"/home/runner/.npm","shared":true},{"name":"ccache","mount_path":"/home/runner/.ccache"}]`,
+		}))
+
+		By("checking the created PersistentVolumeClaim")
+		pvc := new(corev1.PersistentVolumeClaim)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName + "-cache-ccache", Namespace: namespace}, pvc)
+		}).Should(Succeed())
+		Expect(pvc.Spec.AccessModes).To(ConsistOf(corev1.ReadWriteMany))
+		Expect(pvc.Labels).To(HaveKeyWithValue(constants.AppComponentLabelKey, constants.AppComponentCache))
+		Expect(pvc.OwnerReferences).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+			"Kind": Equal("RunnerPool"),
+			"Name": Equal(runnerPoolName),
+		})))
+
+		By("checking the created CronJob")
+		cj := new(batchv1.CronJob)
+		Eventually(func() error {
+			return k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName + "-cache-eviction", Namespace: namespace}, cj)
+		}).Should(Succeed())
+		Expect(cj.Spec.ConcurrencyPolicy).To(Equal(batchv1.ForbidConcurrent))
+		podSpec := cj.Spec.JobTemplate.Spec.Template.Spec
+		Expect(podSpec.Volumes).To(HaveLen(2))
+		Expect(podSpec.Containers).To(ConsistOf(
+			MatchFields(IgnoreExtras, Fields{
+				"Name":            Equal("evict-npm"),
+				"Command":         Equal([]string{"meows", "cache", "evict", "--size-limit", "10Gi", "--depth", "1", "/meows-caches/npm"}),
+				"SecurityContext": Equal(rp.Spec.Template.RunnerContainer.SecurityContext),
+			}),
+			MatchFields(IgnoreExtras, Fields{
+				"Name":    Equal("evict-ccache"),
+				"Command": Equal([]string{"meows", "cache", "evict", "--size-limit", "15Gi", "--depth", "2", "/meows-caches/ccache"}),
+			}),
+		))
+
+		By("removing the caches")
+		Eventually(func() error {
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp); err != nil {
+				return err
+			}
+			rp.Spec.Caches = rp.Spec.Caches[:1]
+			return k8sClient.Update(ctx, rp)
+		}).Should(Succeed())
+		Eventually(func() bool {
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName + "-cache-eviction", Namespace: namespace}, &batchv1.CronJob{})
+			return apierrors.IsNotFound(err)
+		}).Should(BeTrue())
+		Eventually(func(g Gomega) {
+			pvc := new(corev1.PersistentVolumeClaim)
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName + "-cache-ccache", Namespace: namespace}, pvc)
+			if apierrors.IsNotFound(err) {
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(pvc.DeletionTimestamp).NotTo(BeNil())
+		}).Should(Succeed())
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
+
+	It("should pass the rotated credential to sub-processes", func() {
+		By("creating a credential secret")
+		credSecret := new(corev1.Secret)
+		credSecret.SetName("github-cred-rotated")
+		credSecret.SetNamespace(namespace)
+		credSecret.StringData = map[string]string{
+			"token": "old-pat",
+		}
+		Expect(k8sClient.Create(ctx, credSecret)).To(Succeed())
+
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.CredentialSecretName = "github-cred-rotated"
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("waiting the RunnerPool become Bound")
+		Eventually(func(g Gomega) {
+			rp := new(meowsv1alpha1.RunnerPool)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp)).To(Succeed())
+			g.Expect(rp.Status.Bound).To(BeTrue())
+		}).Should(Succeed())
+		Expect(mockManager.githubCreds[namespace+"/"+runnerPoolName]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"PersonalAccessToken": Equal("old-pat"),
+		})))
+
+		By("rotating the credential")
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "github-cred-rotated", Namespace: namespace}, credSecret)).To(Succeed())
+		credSecret.Data = map[string][]byte{
+			"token": []byte("new-pat"),
+		}
+		Expect(k8sClient.Update(ctx, credSecret)).To(Succeed())
+
+		By("checking the rotated credential has been passed to sub-processes")
+		Eventually(func(g Gomega) {
+			g.Expect(mockManager.githubCreds[namespace+"/"+runnerPoolName]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"PersonalAccessToken": Equal("new-pat"),
+			})))
+			g.Expect(mockUpdater.githubCreds[namespace+"/"+runnerPoolName]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"PersonalAccessToken": Equal("new-pat"),
+			})))
+		}).Should(Succeed())
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+		Expect(k8sClient.Delete(ctx, credSecret)).To(Succeed())
+	})
+
+	It("should pass the App credential without the installation ID with the owner", func() {
+		By("creating a credential secret without the installation ID")
+		credSecret := new(corev1.Secret)
+		credSecret.SetName("github-app-without-installation")
+		credSecret.SetNamespace(namespace)
+		credSecret.StringData = map[string]string{
+			"app-id":          "1234",
+			"app-private-key": "dummy-private-key",
+		}
+		Expect(k8sClient.Create(ctx, credSecret)).To(Succeed())
+
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.CredentialSecretName = "github-app-without-installation"
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("checking the credential has been passed to sub-processes")
+		Eventually(func(g Gomega) {
+			rp := new(meowsv1alpha1.RunnerPool)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp)).To(Succeed())
+			g.Expect(rp.Status.Bound).To(BeTrue())
+		}).Should(Succeed())
+		Expect(mockManager.githubCreds[namespace+"/"+runnerPoolName]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"AppID":             BeEquivalentTo(1234),
+			"AppInstallationID": BeEquivalentTo(0),
+			"Owner":             Equal("test-org"),
+			"Repository":        Equal("test-repo"),
+		})))
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+		Expect(k8sClient.Delete(ctx, credSecret)).To(Succeed())
+	})
+
+	It("should use the shared credentials permitted for the namespace", func() {
+		testCases := map[string]string{
+			"shared-cred": "shared-pat",
+			"file-cred":   "file-pat",
+		}
+		for credName, pat := range testCases {
+			By("deploying RunnerPool resource with " + credName)
+			rp := makeRunnerPool(runnerPoolName, namespace)
+			rp.Spec.Repository = "test-org/test-repo"
+			rp.Spec.SharedCredentialName = credName
+			Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+			By("checking the shared credential has been passed to sub-processes")
+			Eventually(func(g Gomega) {
+				rp := new(meowsv1alpha1.RunnerPool)
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp)).To(Succeed())
+				g.Expect(rp.Status.Bound).To(BeTrue())
+			}).Should(Succeed())
+			Expect(mockManager.githubCreds[namespace+"/"+runnerPoolName]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"PersonalAccessToken": Equal(pat),
+			})))
+
+			By("deleting the created RunnerPool")
+			deleteRunnerPool(ctx, runnerPoolName, namespace)
+		}
+	})
+
+	It("should not use the credential secret in the namespace with the kubernetes container mode", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.ContainerMode = meowsv1alpha1.ContainerModeKubernetes
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("checking the conditions")
+		Eventually(func(g Gomega) {
+			rp := new(meowsv1alpha1.RunnerPool)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp)).To(Succeed())
+			g.Expect(meta.FindStatusCondition(rp.Status.Conditions, meowsv1alpha1.ConditionCredentialReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Status":  Equal(metav1.ConditionFalse),
+				"Reason":  Equal("GetCredentialFailed"),
+				"Message": ContainSubstring("containerMode kubernetes"),
+			})))
+			g.Expect(rp.Status.Bound).To(BeFalse())
+		}).Should(Succeed())
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, new(appsv1.Deployment))).NotTo(Succeed())
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
+
+	It("should not share the namespace with the kubernetes container mode", func() {
+		otherName := "runnerpool-2"
+		getCondition := func(name string) func(g Gomega) {
+			return func(g Gomega) {
+				rp := new(meowsv1alpha1.RunnerPool)
+				g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, rp)).To(Succeed())
+				g.Expect(meta.FindStatusCondition(rp.Status.Conditions, meowsv1alpha1.ConditionDeploymentAvailable)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+					"Status":  Equal(metav1.ConditionFalse),
+					"Reason":  Equal("NamespaceNotDedicated"),
+					"Message": ContainSubstring("dedicated namespace"),
+				})))
+			}
+		}
+
+		By("deploying RunnerPool resources")
+		other := makeRunnerPool(otherName, namespace)
+		other.Spec.Repository = "test-org/test-repo"
+		Expect(k8sClient.Create(ctx, other)).To(Succeed())
+		Eventually(func(g Gomega) {
+			rp := new(meowsv1alpha1.RunnerPool)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: otherName, Namespace: namespace}, rp)).To(Succeed())
+			g.Expect(rp.Status.Bound).To(BeTrue())
+		}).Should(Succeed())
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.ContainerMode = meowsv1alpha1.ContainerModeKubernetes
+		rp.Spec.SharedCredentialName = "shared-cred"
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("checking the RunnerPool of the kubernetes container mode is refused")
+		Eventually(getCondition(runnerPoolName)).Should(Succeed())
+		Expect(mockManager.started).NotTo(HaveKey(namespace + "/" + runnerPoolName))
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, new(appsv1.Deployment))).NotTo(Succeed())
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: apiTokenSecretName, Namespace: namespace}, new(corev1.Secret))).NotTo(Succeed())
+
+		By("updating the other RunnerPool")
+		Eventually(func() error {
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: otherName, Namespace: namespace}, other); err != nil {
+				return err
+			}
+			other.Spec.Labels = []string{"updated"}
+			return k8sClient.Update(ctx, other)
+		}).Should(Succeed())
+
+		By("checking the other RunnerPool is also refused")
+		Eventually(getCondition(otherName)).Should(Succeed())
+
+		By("deleting the created RunnerPools")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+		deleteRunnerPool(ctx, otherName, namespace)
+	})
+
+	It("should not use the shared credential not permitted for the namespace", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.SharedCredentialName = "unpermitted-cred"
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("checking the conditions")
+		Eventually(func(g Gomega) {
+			rp := new(meowsv1alpha1.RunnerPool)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp)).To(Succeed())
+			g.Expect(meta.FindStatusCondition(rp.Status.Conditions, meowsv1alpha1.ConditionCredentialReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Status":  Equal(metav1.ConditionFalse),
+				"Reason":  Equal("GetCredentialFailed"),
+				"Message": ContainSubstring("not permitted"),
+			})))
+			g.Expect(rp.Status.Bound).To(BeFalse())
+		}).Should(Succeed())
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
+
+	It("should use the default shared credential when the namespace does not have the credential secret", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, tenantNamespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("checking the default credential has been passed to sub-processes")
+		Eventually(func(g Gomega) {
+			rp := new(meowsv1alpha1.RunnerPool)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: tenantNamespace}, rp)).To(Succeed())
+			g.Expect(rp.Status.Bound).To(BeTrue())
+		}).Should(Succeed())
+		Expect(mockManager.githubCreds[tenantNamespace+"/"+runnerPoolName]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"PersonalAccessToken": Equal("default-pat"),
+		})))
+
+		By("rotating the default credential")
+		s := new(corev1.Secret)
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "shared-default-cred", Namespace: sharedNamespace}, s)).To(Succeed())
+		s.Data = map[string][]byte{
+			"token": []byte("rotated-default-pat"),
+		}
+		Expect(k8sClient.Update(ctx, s)).To(Succeed())
+		Eventually(func(g Gomega) {
+			g.Expect(mockManager.githubCreds[tenantNamespace+"/"+runnerPoolName]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"PersonalAccessToken": Equal("rotated-default-pat"),
+			})))
+		}).Should(Succeed())
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, tenantNamespace)
+	})
+
+	It("should read the credential from the credential provider", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.CredentialProvider = &meowsv1alpha1.CredentialProviderSpec{Type: "file", Path: "github-pat"}
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("checking the credential has been passed to sub-processes")
+		Eventually(func(g Gomega) {
+			rp := new(meowsv1alpha1.RunnerPool)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp)).To(Succeed())
+			g.Expect(rp.Status.Bound).To(BeTrue())
+		}).Should(Succeed())
+		Expect(mockManager.githubCreds[namespace+"/"+runnerPoolName]).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"PersonalAccessToken": Equal("provided-pat"),
+		})))
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
+
+	It("should report the condition when the credential provider is not configured", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.CredentialProvider = &meowsv1alpha1.CredentialProviderSpec{Type: "vault", Path: "github-pat"}
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("checking the conditions")
+		Eventually(func(g Gomega) {
+			rp := new(meowsv1alpha1.RunnerPool)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp)).To(Succeed())
+			g.Expect(meta.FindStatusCondition(rp.Status.Conditions, meowsv1alpha1.ConditionCredentialReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Status":  Equal(metav1.ConditionFalse),
+				"Reason":  Equal("GetCredentialFailed"),
+				"Message": ContainSubstring("not configured"),
+			})))
+		}).Should(Succeed())
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
+
+	It("should create Deployment without the registration token for just-in-time runners", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.JITConfig = true
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("checking the conditions")
+		Eventually(func(g Gomega) {
+			rp := new(meowsv1alpha1.RunnerPool)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp)).To(Succeed())
+			g.Expect(meta.FindStatusCondition(rp.Status.Conditions, meowsv1alpha1.ConditionRegistrationTokenReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Status": Equal(metav1.ConditionTrue),
+				"Reason": Equal("JITConfigEnabled"),
+			})))
+			g.Expect(rp.Status.Bound).To(BeTrue())
+		}).Should(Succeed())
+		Expect(mockUpdater.started).NotTo(HaveKey(namespace + "/" + runnerPoolName))
+
+		By("checking the deployment")
+		d := new(appsv1.Deployment)
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, d)).To(Succeed())
+		for _, v := range d.Spec.Template.Spec.Volumes {
+			Expect(v.Name).NotTo(Equal(secretName))
+		}
+		c := d.Spec.Template.Spec.Containers[0]
+		for _, m := range c.VolumeMounts {
+			Expect(m.Name).NotTo(Equal(secretName))
+		}
+		Expect(c.Env).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Name":  Equal(constants.RunnerOptionEnvName),
+			"Value": ContainSubstring(`"jit_config":true`),
+		})))
+
+		By("checking the registration token secret does not exist")
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: secretName, Namespace: namespace}, new(corev1.Secret))
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
+
+	It("should not create Deployment for just-in-time runners without the runner api key", func() {
+		By("disabling the runner api key")
+		reconciler.runnerAPIKey = nil
+
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Repository = "test-org/test-repo"
+		rp.Spec.JITConfig = true
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("checking the conditions")
+		Eventually(func(g Gomega) {
+			rp := new(meowsv1alpha1.RunnerPool)
+			g.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp)).To(Succeed())
+			g.Expect(meta.FindStatusCondition(rp.Status.Conditions, meowsv1alpha1.ConditionRegistrationTokenReady)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Status":  Equal(metav1.ConditionFalse),
+				"Reason":  Equal("RunnerAPIKeyNotConfigured"),
+				"Message": ContainSubstring("--runner-api-key-file"),
+			})))
+			g.Expect(rp.Status.Bound).To(BeFalse())
+		}).Should(Succeed())
+
+		By("checking the deployment does not exist")
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, new(appsv1.Deployment))
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
+
 	It("should not create Deployment from unpermitted repository", func() {
 		By("deploying RunnerPool resource")
 		rp := makeRunnerPool(runnerPoolName, namespace)
@@ -685,4 +1715,62 @@ var _ = Describe("RunnerPool reconciler", func() {
 		By("deleting the created RunnerPool")
 		deleteRunnerPool(ctx, runnerPoolName, namespace)
 	})
+
+	It("should create Deployment from enterprise-level RunnerPool", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Enterprise = "test-enterprise"
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("waiting the RunnerPool become Bound")
+		Eventually(func() error {
+			rp := new(meowsv1alpha1.RunnerPool)
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp); err != nil {
+				return err
+			}
+			if !rp.Status.Bound {
+				return errors.New(`status "bound" should be true`)
+			}
+			return nil
+		}).Should(Succeed())
+		Expect(mockManager.started).To(HaveKey(namespace + "/" + runnerPoolName))
+		Expect(mockUpdater.started).To(HaveKey(namespace + "/" + runnerPoolName))
+
+		By("checking the environment variables of the runner container")
+		d := new(appsv1.Deployment)
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: namespace}, d)).To(Succeed())
+		env := d.Spec.Template.Spec.Containers[0].Env
+		Expect(env).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Name":  Equal(constants.RunnerEnterpriseEnvName),
+			"Value": Equal("test-enterprise"),
+		})))
+		Expect(env).NotTo(ContainElement(MatchFields(IgnoreExtras, Fields{"Name": Equal(constants.RunnerOrgEnvName)})))
+		Expect(env).NotTo(ContainElement(MatchFields(IgnoreExtras, Fields{"Name": Equal(constants.RunnerRepoEnvName)})))
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
+
+	It("should not create Deployment from unpermitted enterprise", func() {
+		By("deploying RunnerPool resource")
+		rp := makeRunnerPool(runnerPoolName, namespace)
+		rp.Spec.Enterprise = "test-enterprise2"
+		Expect(k8sClient.Create(ctx, rp)).To(Succeed())
+
+		By("waiting the RunnerPool become Bound")
+		Consistently(func() error {
+			rp := new(meowsv1alpha1.RunnerPool)
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: runnerPoolName, Namespace: namespace}, rp); err != nil {
+				return err
+			}
+			if rp.Status.Bound {
+				return errors.New(`status "bound" should not be true`)
+			}
+			return nil
+		}).Should(Succeed())
+		time.Sleep(wait) // Wait for the reconciliation to run a few times. Please check the controller's log.
+
+		By("deleting the created RunnerPool")
+		deleteRunnerPool(ctx, runnerPoolName, namespace)
+	})
 })
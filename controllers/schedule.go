@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"time"
+
+	meowsv1alpha1 "github.com/cybozu-go/meows/api/v1alpha1"
+)
+
+// scheduleLookbackWindows are the windows to look for the last start time of a schedule.
+// They are tried from the shortest one, so that a frequent schedule is evaluated with a few iterations.
+var scheduleLookbackWindows = []time.Duration{
+	time.Hour,
+	24 * time.Hour,
+	8 * 24 * time.Hour,
+	32 * 24 * time.Hour,
+	367 * 24 * time.Hour,
+}
+
+// findActiveSchedule returns the schedule which has started most recently.
+// If none of the schedules has started within a year, it returns nil.
+func findActiveSchedule(schedules []meowsv1alpha1.ScheduleConfig, now time.Time) *meowsv1alpha1.ScheduleConfig {
+	var active *meowsv1alpha1.ScheduleConfig
+	var activeStartedAt time.Time
+	for i := range schedules {
+		sc := &schedules[i]
+		startedAt, ok := lastStartTime(sc, now)
+		if !ok {
+			continue
+		}
+		if active == nil || startedAt.After(activeStartedAt) {
+			active = sc
+			activeStartedAt = startedAt
+		}
+	}
+	return active
+}
+
+func lastStartTime(sc *meowsv1alpha1.ScheduleConfig, now time.Time) (time.Time, bool) {
+	// The schedules are validated by the webhook, so errors are unexpected here.
+	sched, err := sc.ParseCron()
+	if err != nil {
+		return time.Time{}, false
+	}
+	loc, err := time.LoadLocation(sc.TimeZone)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	now = now.In(loc)
+	for _, w := range scheduleLookbackWindows {
+		t := sched.Next(now.Add(-w))
+		if t.IsZero() {
+			// The schedule never starts, such as "0 0 30 2 *".
+			return time.Time{}, false
+		}
+		if t.After(now) {
+			continue
+		}
+		for {
+			next := sched.Next(t)
+			if next.IsZero() || next.After(now) {
+				return t, true
+			}
+			t = next
+		}
+	}
+	return time.Time{}, false
+}
+
+// scheduledReplicas returns the number of runner pods to accept a new job, and the name of the active schedule.
+// If no schedule is active, it returns the given replicas and an empty name.
+func scheduledReplicas(schedules []meowsv1alpha1.ScheduleConfig, replicas int32, now time.Time) (int32, string) {
+	active := findActiveSchedule(schedules, now)
+	if active == nil {
+		return replicas, ""
+	}
+	return active.Replicas, active.Name
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"time"
+
+	meowsv1alpha1 "github.com/cybozu-go/meows/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schedule", func() {
+	schedules := []meowsv1alpha1.ScheduleConfig{
+		{Name: "office-hours", Cron: "0 9 * * 1-5", TimeZone: "Asia/Tokyo", Replicas: 10},
+		{Name: "night", Cron: "0 19 * * 1-5", TimeZone: "Asia/Tokyo", Replicas: 2},
+		{Name: "monthly", Cron: "0 12 3 * *", Replicas: 5},
+	}
+
+	DescribeTable("should find the active schedule",
+		func(now string, expectedReplicas int32, expectedName string) {
+			t, err := time.Parse(time.RFC3339, now)
+			Expect(err).NotTo(HaveOccurred())
+			replicas, name := scheduledReplicas(schedules, 1, t)
+			Expect(replicas).To(Equal(expectedReplicas))
+			Expect(name).To(Equal(expectedName))
+		},
+		// 2024-07-01 is Monday.
+		Entry("in the office hours", "2024-07-01T10:00:00+09:00", int32(10), "office-hours"),
+		Entry("just when the office hours start", "2024-07-02T09:00:00+09:00", int32(10), "office-hours"),
+		Entry("at night", "2024-07-02T08:59:59+09:00", int32(2), "night"),
+		Entry("on weekend", "2024-07-07T10:00:00+09:00", int32(2), "night"),
+		Entry("after the monthly schedule starts", "2024-07-03T22:00:00+09:00", int32(5), "monthly"),
+	)
+
+	It("should return the given replicas if no schedule is active", func() {
+		replicas, name := scheduledReplicas(nil, 3, time.Now())
+		Expect(replicas).To(BeNumerically("==", 3))
+		Expect(name).To(BeEmpty())
+
+		replicas, name = scheduledReplicas([]meowsv1alpha1.ScheduleConfig{
+			{Name: "leap-day", Cron: "0 0 29 2 *", Replicas: 5},
+		}, 3, time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC))
+		Expect(replicas).To(BeNumerically("==", 3))
+		Expect(name).To(BeEmpty())
+	})
+})
@@ -13,10 +13,12 @@ import (
 	"github.com/cybozu-go/meows/metrics"
 	"github.com/cybozu-go/well"
 	"github.com/go-logr/logr"
+	gogithub "github.com/google/go-github/v80/github"
 	"github.com/prometheus/client_golang/prometheus"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -31,16 +33,18 @@ type SecretUpdater interface {
 type secretUpdater struct {
 	log                 logr.Logger
 	k8sClient           client.Client
+	recorder            events.EventRecorder
 	githubClientFactory github.ClientFactory
 	mu                  sync.Mutex
 	stopped             bool
 	processes           map[string]*updateProcess
 }
 
-func NewSecretUpdater(log logr.Logger, k8sClient client.Client, githubClientFactory github.ClientFactory) SecretUpdater {
+func NewSecretUpdater(log logr.Logger, k8sClient client.Client, recorder events.EventRecorder, githubClientFactory github.ClientFactory) SecretUpdater {
 	return &secretUpdater{
 		log:                 log.WithName("SecretUpdater"),
 		k8sClient:           k8sClient,
+		recorder:            recorder,
 		githubClientFactory: githubClientFactory,
 		processes:           map[string]*updateProcess{},
 	}
@@ -63,11 +67,24 @@ func (u *secretUpdater) Start(rp *meowsv1alpha1.RunnerPool, cred *github.ClientC
 		process := newUpdateProcess(
 			u.log.WithValues("runnerpool", rpNamespacedName),
 			u.k8sClient,
+			u.recorder,
 			githubClient,
+			cred,
 			rp,
 		)
 		process.start()
 		u.processes[rpNamespacedName] = process
+		return nil
+	}
+
+	process := u.processes[rpNamespacedName]
+	if !process.getGitHubCredential().Equal(cred) {
+		githubClient, err := u.githubClientFactory.New(cred)
+		if err != nil {
+			return fmt.Errorf("failed to create a github client; %w", err)
+		}
+		process.setGitHubClient(githubClient, cred)
+		process.log.Info("reloaded the github credential")
 	}
 	return nil
 }
@@ -97,16 +114,31 @@ func (u *secretUpdater) StopAll() {
 	u.stopped = true
 }
 
+// Reasons and actions of the events recorded by the secret updater.
+const (
+	reasonUpdatedToken      = "UpdatedRegistrationToken"
+	reasonUpdateTokenFailed = "UpdateRegistrationTokenFailed"
+
+	actionUpdateToken = "UpdateRegistrationToken"
+)
+
 type updateProcess struct {
 	// Given from outside. Not update internally.
-	log          logr.Logger
-	k8sClient    client.Client
+	log         logr.Logger
+	k8sClient   client.Client
+	recorder    events.EventRecorder
+	rpNamespace string
+	rpName      string
+	rpRef       *corev1.ObjectReference
+	secretName  string
+	owner       string
+	repo        string
+	enterprise  string
+
+	// Replaced when the credential is rotated.
 	githubClient github.Client
-	rpNamespace  string
-	rpName       string
-	secretName   string
-	owner        string
-	repo         string
+	githubCred   *github.ClientCredential
+	mu           sync.Mutex
 
 	// Update internally.
 	env               *well.Environment
@@ -115,17 +147,21 @@ type updateProcess struct {
 	deleteMetrics     func()
 }
 
-func newUpdateProcess(log logr.Logger, k8sClient client.Client, githubClient github.Client, rp *meowsv1alpha1.RunnerPool) *updateProcess {
+func newUpdateProcess(log logr.Logger, k8sClient client.Client, recorder events.EventRecorder, githubClient github.Client, githubCred *github.ClientCredential, rp *meowsv1alpha1.RunnerPool) *updateProcess {
 	rpNamespacedName := types.NamespacedName{Namespace: rp.Namespace, Name: rp.Name}.String()
 	return &updateProcess{
 		log:               log,
 		k8sClient:         k8sClient,
+		recorder:          recorder,
 		githubClient:      githubClient,
+		githubCred:        githubCred,
 		rpNamespace:       rp.Namespace,
 		rpName:            rp.Name,
+		rpRef:             runnerPoolReference(rp),
 		secretName:        rp.GetRunnerSecretName(),
 		owner:             rp.GetOwner(),
 		repo:              rp.GetRepository(),
+		enterprise:        rp.Spec.Enterprise,
 		retryCountMetrics: metrics.RunnerPoolSecretRetryCount.WithLabelValues(rpNamespacedName),
 		deleteMetrics: func() {
 			metrics.RunnerPoolSecretRetryCount.DeleteLabelValues(rpNamespacedName)
@@ -133,6 +169,25 @@ func newUpdateProcess(log logr.Logger, k8sClient client.Client, githubClient git
 	}
 }
 
+func (p *updateProcess) getGitHubClient() github.Client {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.githubClient
+}
+
+func (p *updateProcess) getGitHubCredential() *github.ClientCredential {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.githubCred
+}
+
+func (p *updateProcess) setGitHubClient(githubClient github.Client, cred *github.ClientCredential) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.githubClient = githubClient
+	p.githubCred = cred
+}
+
 func (p *updateProcess) start() {
 	ctx, cancel := context.WithCancel(context.Background())
 	p.cancel = cancel
@@ -188,12 +243,14 @@ func (p *updateProcess) run(ctx context.Context) {
 		expiresAt, err := p.updateSecret(ctx, s)
 		if err != nil {
 			p.log.Error(err, "failed to update secret, retry after 1 minutes")
+			p.recorder.Eventf(p.rpRef, s, corev1.EventTypeWarning, reasonUpdateTokenFailed, actionUpdateToken, "failed to update registration token: %v", err)
 			p.retryCountMetrics.Inc()
 			waitTime = time.Minute
 			continue
 		}
 
 		p.log.Info("secret is successfully updated", "expiresAt", expiresAt.Format(time.RFC3339))
+		p.recorder.Eventf(p.rpRef, s, corev1.EventTypeNormal, reasonUpdatedToken, actionUpdateToken, "updated registration token which expires at %s", expiresAt.Format(time.RFC3339))
 		waitTime = time.Second
 	}
 }
@@ -227,8 +284,15 @@ func (p *updateProcess) needUpdate(s *corev1.Secret) (bool, time.Time) {
 	return false, updateTime
 }
 
+func (p *updateProcess) createRegistrationToken(ctx context.Context) (*gogithub.RegistrationToken, error) {
+	if p.enterprise != "" {
+		return p.getGitHubClient().CreateEnterpriseRegistrationToken(ctx, p.enterprise)
+	}
+	return p.getGitHubClient().CreateRegistrationToken(ctx, p.owner, p.repo)
+}
+
 func (p *updateProcess) updateSecret(ctx context.Context, s *corev1.Secret) (time.Time, error) {
-	runnerToken, err := p.githubClient.CreateRegistrationToken(ctx, p.owner, p.repo)
+	runnerToken, err := p.createRegistrationToken(ctx)
 	if err != nil {
 		return time.Time{}, fmt.Errorf("failed to create actions registration token; %w", err)
 	}
@@ -10,6 +10,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -39,7 +40,8 @@ var _ = Describe("SecretUpdater", func() {
 
 		githubClientFactory := github.NewFakeClientFactory()
 		githubClientFactory.SetExpiredAtDuration(100 * time.Hour)
-		secretUpdater := NewSecretUpdater(ctrl.Log, k8sClient, githubClientFactory)
+		recorder := events.NewFakeRecorder(10)
+		secretUpdater := NewSecretUpdater(ctrl.Log, k8sClient, recorder, githubClientFactory)
 
 		for _, tc := range testCase {
 			rp := makeRunnerPoolWithOrganization(tc.name, "secretupdater-test", "test-org")
@@ -66,6 +68,9 @@ var _ = Describe("SecretUpdater", func() {
 			expectedExpiresAt := time.Now().Add(100 * time.Hour)
 			Expect(tm).To(BeTemporally("~", expectedExpiresAt, 20*time.Second), tc.name)
 
+			By("checking the event was recorded")
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal UpdatedRegistrationToken ")), tc.name)
+
 			By("stopping secret updater")
 			secretUpdater.Stop(rp)
 		}
@@ -106,7 +111,7 @@ var _ = Describe("SecretUpdater", func() {
 
 		githubClientFactory := github.NewFakeClientFactory()
 		githubClientFactory.SetExpiredAtDuration(100 * time.Hour)
-		secretUpdater := NewSecretUpdater(ctrl.Log, k8sClient, githubClientFactory)
+		secretUpdater := NewSecretUpdater(ctrl.Log, k8sClient, &events.FakeRecorder{}, githubClientFactory)
 
 		for _, tc := range testCase {
 			rp := makeRunnerPoolWithRepository(tc.name, "secretupdater-test", "owner/test-repo")
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SharedCredentials is the set of the GitHub credentials shared among the RunnerPool namespaces.
+// The shared credentials are stored in the controller's namespace or in the controller's files,
+// so the App private keys are not copied into the RunnerPool namespaces.
+type SharedCredentials struct {
+	// Namespace is the namespace of the Secrets of the shared credentials.
+	Namespace string
+
+	// Default is the name of the shared credential used by the RunnerPools whose namespace does not have the credential secret.
+	// If this is empty, the RunnerPools should have their own credential secret or specify a shared credential.
+	Default string
+
+	// Credentials maps the names of the shared credentials to their sources.
+	Credentials map[string]*SharedCredential
+}
+
+// SharedCredential is the source of a shared credential and the rule of the namespaces permitted to use it.
+type SharedCredential struct {
+	// SecretName is the name of the Secret in the namespace of the shared credentials.
+	SecretName string
+
+	// Path is the directory containing the files named as the keys of the credential secret, such as a mounted Secret.
+	// If this is not empty, SecretName is ignored.
+	Path string
+
+	// NamespaceRule is the rule of the namespaces permitted to use the credential.
+	// It should match the whole namespace name. Use CompileNamespaceRule to compile it.
+	NamespaceRule *regexp.Regexp
+}
+
+// CompileNamespaceRule compiles the rule of the namespaces permitted to use a shared credential.
+// The rule is anchored to match the whole namespace name, so that `team-a` does not permit `team-a-sandbox`.
+func CompileNamespaceRule(rule string) (*regexp.Regexp, error) {
+	return regexp.Compile(`^(?:` + rule + `)$`)
+}
+
+func (s *SharedCredentials) hasDefault() bool {
+	return s != nil && s.Default != ""
+}
+
+// usedBy returns the names of the shared credentials read from the secret.
+func (s *SharedCredentials) usedBy(secret client.Object) []string {
+	if s == nil || secret.GetNamespace() != s.Namespace {
+		return nil
+	}
+	var names []string
+	for name, c := range s.Credentials {
+		if c.Path == "" && c.SecretName == secret.GetName() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// get reads the data of the shared credential for the RunnerPool in the namespace.
+func (s *SharedCredentials) get(ctx context.Context, c client.Client, name, namespace string) (map[string][]byte, error) {
+	if s == nil {
+		return nil, fmt.Errorf("shared credential %q is not found", name)
+	}
+	sc, ok := s.Credentials[name]
+	if !ok {
+		return nil, fmt.Errorf("shared credential %q is not found", name)
+	}
+	if sc.NamespaceRule == nil || !sc.NamespaceRule.MatchString(namespace) {
+		return nil, fmt.Errorf("namespace %q is not permitted to use shared credential %q", namespace, name)
+	}
+
+	if sc.Path != "" {
+		data, err := readCredentialFiles(sc.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read shared credential %q; %w", name, err)
+		}
+		return data, nil
+	}
+
+	secret := &corev1.Secret{}
+	err := c.Get(ctx, types.NamespacedName{Namespace: s.Namespace, Name: sc.SecretName}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret of shared credential %q; %w", name, err)
+	}
+	return secret.Data, nil
+}
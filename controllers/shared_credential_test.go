@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shared credentials", func() {
+	DescribeTable("should match the whole namespace name",
+		func(rule, namespace string, expected bool) {
+			re, err := CompileNamespaceRule(rule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(re.MatchString(namespace)).To(Equal(expected))
+		},
+		Entry("exact name", "team-a", "team-a", true),
+		Entry("substring", "team-a", "team-a-sandbox", false),
+		Entry("substring in the middle", "team-a", "evil-team-a", false),
+		Entry("pattern", "team-.*", "team-b", true),
+		Entry("alternation", "team-a|team-b", "team-b-sandbox", false),
+		Entry("already anchored", "^team-a$", "team-a", true),
+	)
+
+	It("should reject an invalid rule", func() {
+		_, err := CompileNamespaceRule("team-(")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,245 @@
+package github
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	constants "github.com/cybozu-go/meows"
+	"github.com/cybozu-go/meows/metrics"
+)
+
+type withoutCacheKey struct{}
+
+// WithoutCache returns a context to make the cached clients request GitHub without using the cached listings.
+// The listings requested with this context are stored in the cache.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutCacheKey{}, true)
+}
+
+func isWithoutCache(ctx context.Context) bool {
+	v, _ := ctx.Value(withoutCacheKey{}).(bool)
+	return v
+}
+
+type cacheEntry struct {
+	done      chan struct{}
+	value     any
+	err       error
+	fetchedAt time.Time
+}
+
+// listCache keeps the listings for the ttl, and makes the concurrent callers share one request.
+type listCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+func (c *listCache) get(ctx context.Context, key string, fetch func() (any, error)) (any, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok && !isWithoutCache(ctx) {
+		c.mu.Unlock()
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if e.err == nil && time.Since(e.fetchedAt) < c.ttl {
+			metrics.IncrementGitHubCacheCount(true)
+			return e.value, nil
+		}
+		c.mu.Lock()
+		// Another caller may have started a new request after the entry expired.
+		if cur := c.entries[key]; cur != e {
+			c.mu.Unlock()
+			return c.get(ctx, key, fetch)
+		}
+	}
+	e = &cacheEntry{done: make(chan struct{})}
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	metrics.IncrementGitHubCacheCount(false)
+	e.value, e.err = fetch()
+	e.fetchedAt = time.Now()
+	close(e.done)
+
+	if e.err != nil {
+		c.invalidate(key, e)
+	}
+	return e.value, e.err
+}
+
+// invalidate removes the entry for the key. If e is not nil, it removes the entry only if it is e.
+func (c *listCache) invalidate(key string, e *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e != nil && c.entries[key] != e {
+		return
+	}
+	delete(c.entries, key)
+}
+
+type cachedFactory struct {
+	factory ClientFactory
+	cache   *listCache
+}
+
+// NewCachedFactory returns a ClientFactory whose clients share the listings of runners and queued jobs
+// in the same repository, organization or enterprise for the ttl.
+// So the RunnerPools in the same scope do not request the same listings to GitHub on every check.
+// The listings are shared only among the clients created with the same credential,
+// so a client with an invalid credential does not get the listings requested by the others.
+// The cached listings are discarded when the runners in the scope are removed or registered through the clients.
+func NewCachedFactory(factory ClientFactory, ttl time.Duration) ClientFactory {
+	return &cachedFactory{
+		factory: factory,
+		cache: &listCache{
+			ttl:     ttl,
+			entries: map[string]*cacheEntry{},
+		},
+	}
+}
+
+func (f *cachedFactory) New(cred *ClientCredential) (Client, error) {
+	c, err := f.factory.New(cred)
+	if err != nil {
+		return nil, err
+	}
+	server := strings.TrimSuffix(cred.GitHubURL, "/")
+	if server == "" {
+		server = constants.DefaultGitHubURL
+	}
+	return &cachedClient{
+		Client:     c,
+		cache:      f.cache,
+		server:     server,
+		credential: cacheCredentialName(cred),
+	}, nil
+}
+
+// cacheCredentialName returns the name to identify the credential in the cache keys.
+// For a GitHub App, it contains the hash of the private key,
+// because the clients with the same App ID may have a wrong or revoked key.
+func cacheCredentialName(cred *ClientCredential) string {
+	name := credentialName(cred)
+	if len(cred.PersonalAccessToken) != 0 {
+		return name
+	}
+	key := cred.PrivateKey
+	if len(key) == 0 {
+		key = []byte(cred.PrivateKeyPath)
+	}
+	sum := sha256.Sum256(key)
+	return name + "-" + hex.EncodeToString(sum[:8])
+}
+
+// cachedClient is a Client which caches the listings in the cache shared by the factory.
+type cachedClient struct {
+	Client
+	cache      *listCache
+	server     string
+	credential string
+}
+
+func (c *cachedClient) runnersKey(scope string) string {
+	return "runners " + c.credential + " " + c.server + "/" + scope
+}
+
+func (c *cachedClient) jobsKey(scope string) string {
+	return "jobs " + c.credential + " " + c.server + "/" + scope
+}
+
+func (c *cachedClient) listRunners(ctx context.Context, key string, labels []string, fetch func() ([]*Runner, error)) ([]*Runner, error) {
+	v, err := c.cache.get(ctx, key, func() (any, error) {
+		return fetch()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var runners []*Runner
+	for _, r := range v.([]*Runner) {
+		if !r.hasLabels(labels) {
+			continue
+		}
+		// Copy the runner not to let the callers modify the cached one.
+		copied := *r
+		runners = append(runners, &copied)
+	}
+	return runners, nil
+}
+
+func (c *cachedClient) ListRunners(ctx context.Context, owner, repo string, labels []string) ([]*Runner, error) {
+	return c.listRunners(ctx, c.runnersKey(genKey(owner, repo)), labels, func() ([]*Runner, error) {
+		return c.Client.ListRunners(ctx, owner, repo, nil)
+	})
+}
+
+func (c *cachedClient) ListEnterpriseRunners(ctx context.Context, enterprise string, labels []string) ([]*Runner, error) {
+	return c.listRunners(ctx, c.runnersKey(genEnterpriseKey(enterprise)), labels, func() ([]*Runner, error) {
+		return c.Client.ListEnterpriseRunners(ctx, enterprise, nil)
+	})
+}
+
+func (c *cachedClient) RemoveRunner(ctx context.Context, owner, repo string, runnerID int64) error {
+	defer c.cache.invalidate(c.runnersKey(genKey(owner, repo)), nil)
+	return c.Client.RemoveRunner(ctx, owner, repo, runnerID)
+}
+
+func (c *cachedClient) RemoveEnterpriseRunner(ctx context.Context, enterprise string, runnerID int64) error {
+	defer c.cache.invalidate(c.runnersKey(genEnterpriseKey(enterprise)), nil)
+	return c.Client.RemoveEnterpriseRunner(ctx, enterprise, runnerID)
+}
+
+func (c *cachedClient) GenerateJITConfig(ctx context.Context, owner, repo, name string, runnerGroupID int64, labels []string) (string, error) {
+	defer c.cache.invalidate(c.runnersKey(genKey(owner, repo)), nil)
+	return c.Client.GenerateJITConfig(ctx, owner, repo, name, runnerGroupID, labels)
+}
+
+func (c *cachedClient) GenerateEnterpriseJITConfig(ctx context.Context, enterprise, name string, runnerGroupID int64, labels []string) (string, error) {
+	defer c.cache.invalidate(c.runnersKey(genEnterpriseKey(enterprise)), nil)
+	return c.Client.GenerateEnterpriseJITConfig(ctx, enterprise, name, runnerGroupID, labels)
+}
+
+// queuedJobs is the cached result of ListQueuedJobs.
+type queuedJobs struct {
+	jobs      []*Job
+	truncated bool
+}
+
+func (c *cachedClient) ListQueuedJobs(ctx context.Context, owner, repo string, labels []string, opts PollingOptions) ([]*Job, bool, error) {
+	key := c.jobsKey(genKey(owner, repo))
+	if repo == "" {
+		// The polled repositories of the organization depend on the options.
+		key += fmt.Sprintf(" %d %s", opts.maxRepositories(), opts.window())
+	}
+	v, err := c.cache.get(ctx, key, func() (any, error) {
+		jobs, truncated, err := c.Client.ListQueuedJobs(ctx, owner, repo, nil, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &queuedJobs{jobs: jobs, truncated: truncated}, nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	cached := v.(*queuedJobs)
+	var jobs []*Job
+	for _, j := range cached.jobs {
+		if !hasLabels(j.Labels, labels) {
+			continue
+		}
+		copied := *j
+		jobs = append(jobs, &copied)
+	}
+	return jobs, cached.truncated, nil
+}
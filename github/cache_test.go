@@ -0,0 +1,333 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingClient counts the requests of the listings, and fails them while err is set.
+type countingClient struct {
+	Client
+	runnerCalls atomic.Int32
+	jobCalls    atomic.Int32
+	err         error
+	delay       time.Duration
+}
+
+func (c *countingClient) ListRunners(ctx context.Context, owner, repo string, labels []string) ([]*Runner, error) {
+	c.runnerCalls.Add(1)
+	time.Sleep(c.delay)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.Client.ListRunners(ctx, owner, repo, labels)
+}
+
+func (c *countingClient) ListEnterpriseRunners(ctx context.Context, enterprise string, labels []string) ([]*Runner, error) {
+	c.runnerCalls.Add(1)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.Client.ListEnterpriseRunners(ctx, enterprise, labels)
+}
+
+func (c *countingClient) ListQueuedJobs(ctx context.Context, owner, repo string, labels []string, opts PollingOptions) ([]*Job, bool, error) {
+	c.jobCalls.Add(1)
+	if c.err != nil {
+		return nil, false, c.err
+	}
+	return c.Client.ListQueuedJobs(ctx, owner, repo, labels, opts)
+}
+
+// countingFactory returns the same countingClient for all credentials.
+type countingFactory struct {
+	client *countingClient
+}
+
+func (f *countingFactory) New(cred *ClientCredential) (Client, error) {
+	return f.client, nil
+}
+
+func newCountingFactory() (*countingClient, ClientFactory) {
+	fake := NewFakeClientFactory()
+	fake.SetRunners(map[string][]*Runner{
+		"owner/repo": {
+			{ID: 1, Name: "runner1", Labels: []string{"a"}},
+			{ID: 2, Name: "runner2", Labels: []string{"a", "b"}},
+		},
+	})
+	fake.SetQueuedJobs(map[string][]*Job{
+		"owner/repo": {
+			{ID: 1, Labels: []string{"a"}},
+		},
+		"owner": {
+			{ID: 2, Repo: "repo1", Labels: []string{"a"}},
+			{ID: 3, Repo: "repo2", Labels: []string{"a"}},
+		},
+	})
+	fakeClient, _ := fake.New(nil)
+	client := &countingClient{Client: fakeClient}
+	return client, &countingFactory{client: client}
+}
+
+func TestCachedClient(t *testing.T) {
+	ctx := context.Background()
+	pat := &ClientCredential{PersonalAccessToken: "pat"}
+
+	testCases := []struct {
+		title string
+		ttl   time.Duration
+		run   func(t *testing.T, c Client, client *countingClient)
+		calls int32
+	}{
+		{
+			title: "hit",
+			ttl:   time.Hour,
+			run: func(t *testing.T, c Client, client *countingClient) {
+				for range 3 {
+					runners, err := c.ListRunners(ctx, "owner", "repo", []string{"b"})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if len(runners) != 1 || runners[0].Name != "runner2" {
+						t.Errorf("unexpected runners: %v", runners)
+					}
+				}
+			},
+			calls: 1,
+		},
+		{
+			title: "miss for another scope",
+			ttl:   time.Hour,
+			run: func(t *testing.T, c Client, client *countingClient) {
+				c.ListRunners(ctx, "owner", "repo", nil)
+				c.ListRunners(ctx, "owner", "", nil)
+				c.ListEnterpriseRunners(ctx, "owner", nil)
+			},
+			calls: 3,
+		},
+		{
+			title: "shared by concurrent callers",
+			ttl:   time.Hour,
+			run: func(t *testing.T, c Client, client *countingClient) {
+				client.delay = 100 * time.Millisecond
+				var wg sync.WaitGroup
+				for range 10 {
+					wg.Go(func() {
+						if _, err := c.ListRunners(ctx, "owner", "repo", nil); err != nil {
+							t.Error(err)
+						}
+					})
+				}
+				wg.Wait()
+			},
+			calls: 1,
+		},
+		{
+			title: "expired",
+			ttl:   10 * time.Millisecond,
+			run: func(t *testing.T, c Client, client *countingClient) {
+				c.ListRunners(ctx, "owner", "repo", nil)
+				time.Sleep(20 * time.Millisecond)
+				c.ListRunners(ctx, "owner", "repo", nil)
+			},
+			calls: 2,
+		},
+		{
+			title: "without cache",
+			ttl:   time.Hour,
+			run: func(t *testing.T, c Client, client *countingClient) {
+				c.ListRunners(ctx, "owner", "repo", nil)
+				c.ListRunners(WithoutCache(ctx), "owner", "repo", nil)
+				c.ListRunners(ctx, "owner", "repo", nil)
+			},
+			calls: 2,
+		},
+		{
+			title: "invalidated by RemoveRunner",
+			ttl:   time.Hour,
+			run: func(t *testing.T, c Client, client *countingClient) {
+				c.ListRunners(ctx, "owner", "repo", nil)
+				if err := c.RemoveRunner(ctx, "owner", "repo", 1); err != nil {
+					t.Fatal(err)
+				}
+				runners, _ := c.ListRunners(ctx, "owner", "repo", nil)
+				if len(runners) != 1 {
+					t.Errorf("removed runner is listed: %v", runners)
+				}
+			},
+			calls: 2,
+		},
+		{
+			title: "invalidated by GenerateJITConfig",
+			ttl:   time.Hour,
+			run: func(t *testing.T, c Client, client *countingClient) {
+				c.ListRunners(ctx, "owner", "repo", nil)
+				if _, err := c.GenerateJITConfig(ctx, "owner", "repo", "runner3", DefaultRunnerGroupID, nil); err != nil {
+					t.Fatal(err)
+				}
+				runners, _ := c.ListRunners(ctx, "owner", "repo", nil)
+				if len(runners) != 3 {
+					t.Errorf("registered runner is not listed: %v", runners)
+				}
+			},
+			calls: 2,
+		},
+		{
+			title: "error not cached",
+			ttl:   time.Hour,
+			run: func(t *testing.T, c Client, client *countingClient) {
+				client.err = errors.New("failed")
+				if _, err := c.ListRunners(ctx, "owner", "repo", nil); err == nil {
+					t.Error("error is not returned")
+				}
+				client.err = nil
+				if _, err := c.ListRunners(ctx, "owner", "repo", nil); err != nil {
+					t.Error(err)
+				}
+				c.ListRunners(ctx, "owner", "repo", nil)
+			},
+			calls: 2,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			client, factory := newCountingFactory()
+			c, err := NewCachedFactory(factory, tt.ttl).New(pat)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.run(t, c, client)
+			if n := client.runnerCalls.Load(); n != tt.calls {
+				t.Errorf("ListRunners is called %d times, want %d", n, tt.calls)
+			}
+		})
+	}
+}
+
+func TestCachedClientQueuedJobs(t *testing.T) {
+	ctx := context.Background()
+	client, factory := newCountingFactory()
+	c, err := NewCachedFactory(factory, time.Hour).New(&ClientCredential{PersonalAccessToken: "pat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, labels := range [][]string{{"a"}, {"b"}, nil} {
+		if _, _, err := c.ListQueuedJobs(ctx, "owner", "repo", labels, PollingOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	jobs, _, _ := c.ListQueuedJobs(ctx, "owner", "repo", []string{"b"}, PollingOptions{})
+	if len(jobs) != 0 {
+		t.Errorf("unexpected jobs: %v", jobs)
+	}
+	if n := client.jobCalls.Load(); n != 1 {
+		t.Errorf("ListQueuedJobs is called %d times, want 1", n)
+	}
+}
+
+func TestCachedClientQueuedJobsOfOrganization(t *testing.T) {
+	ctx := context.Background()
+	client, factory := newCountingFactory()
+	c, err := NewCachedFactory(factory, time.Hour).New(&ClientCredential{PersonalAccessToken: "pat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		opts      PollingOptions
+		count     int
+		truncated bool
+	}{
+		{opts: PollingOptions{}, count: 2},
+		{opts: PollingOptions{MaxRepositories: 1}, count: 1, truncated: true},
+		{opts: PollingOptions{MaxRepositories: 1}, count: 1, truncated: true},
+		{opts: PollingOptions{Window: time.Hour}, count: 2},
+	}
+	for _, tt := range testCases {
+		jobs, truncated, err := c.ListQueuedJobs(ctx, "owner", "", []string{"a"}, tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(jobs) != tt.count || truncated != tt.truncated {
+			t.Errorf("%d jobs (truncated %v) are listed with %+v, want %d (truncated %v)", len(jobs), truncated, tt.opts, tt.count, tt.truncated)
+		}
+	}
+	// The results are cached for each of the options.
+	if n := client.jobCalls.Load(); n != 3 {
+		t.Errorf("ListQueuedJobs is called %d times, want 3", n)
+	}
+}
+
+func TestCachedClientCredentials(t *testing.T) {
+	ctx := context.Background()
+	testCases := []struct {
+		title  string
+		creds  []*ClientCredential
+		shared bool
+	}{
+		{
+			title: "same pat",
+			creds: []*ClientCredential{
+				{PersonalAccessToken: "pat"},
+				{PersonalAccessToken: "pat"},
+			},
+			shared: true,
+		},
+		{
+			title: "different pats",
+			creds: []*ClientCredential{
+				{PersonalAccessToken: "pat"},
+				{PersonalAccessToken: "revoked-pat"},
+			},
+		},
+		{
+			title: "same app",
+			creds: []*ClientCredential{
+				{AppID: 1, AppInstallationID: 2, PrivateKey: []byte("key")},
+				{AppID: 1, AppInstallationID: 2, PrivateKey: []byte("key")},
+			},
+			shared: true,
+		},
+		{
+			title: "different app keys",
+			creds: []*ClientCredential{
+				{AppID: 1, AppInstallationID: 2, PrivateKey: []byte("key")},
+				{AppID: 1, AppInstallationID: 2, PrivateKey: []byte("wrong-key")},
+			},
+		},
+		{
+			title: "different servers",
+			creds: []*ClientCredential{
+				{PersonalAccessToken: "pat"},
+				{PersonalAccessToken: "pat", GitHubURL: "https://github.example.com"},
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			client, factory := newCountingFactory()
+			cached := NewCachedFactory(factory, time.Hour)
+			for _, cred := range tt.creds {
+				c, err := cached.New(cred)
+				if err != nil {
+					t.Fatal(err)
+				}
+				c.ListRunners(ctx, "owner", "repo", nil)
+			}
+
+			expected := int32(len(tt.creds))
+			if tt.shared {
+				expected = 1
+			}
+			if n := client.runnerCalls.Load(); n != expected {
+				t.Errorf("ListRunners is called %d times, want %d", n, expected)
+			}
+		})
+	}
+}
@@ -1,20 +1,61 @@
 package github
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
+	constants "github.com/cybozu-go/meows"
 	"github.com/google/go-github/v80/github"
 	"golang.org/x/oauth2"
 )
 
 const statusOnline = "online"
 
+// Statuses of workflow runs and jobs.
+const (
+	statusQueued     = "queued"
+	statusInProgress = "in_progress"
+)
+
+// Default limits of the repositories polled for the queued jobs of an organization.
+const (
+	DefaultMaxPolledRepositories = 30
+	DefaultPollingWindow         = 24 * time.Hour
+)
+
+// PollingOptions limits the repositories polled for the queued jobs of an organization.
+// GitHub does not provide an API to list the workflow runs of an organization,
+// so each polled repository costs two requests to list the runs and one request for each run.
+type PollingOptions struct {
+	// MaxRepositories is the max number of the polled repositories. Defaults to DefaultMaxPolledRepositories.
+	MaxRepositories int
+
+	// Window is the duration to look back on the pushes of the polled repositories. Defaults to DefaultPollingWindow.
+	Window time.Duration
+}
+
+func (o PollingOptions) maxRepositories() int {
+	if o.MaxRepositories <= 0 {
+		return DefaultMaxPolledRepositories
+	}
+	return o.MaxRepositories
+}
+
+func (o PollingOptions) window() time.Duration {
+	if o.Window <= 0 {
+		return DefaultPollingWindow
+	}
+	return o.Window
+}
+
 type Runner struct {
 	ID     int64
 	Name   string
@@ -38,31 +79,106 @@ func convert(ghRunner *github.Runner) *Runner {
 }
 
 func (r *Runner) hasLabels(labels []string) bool {
+	return hasLabels(r.Labels, labels)
+}
+
+// Job represents a workflow job.
+type Job struct {
+	ID     int64
+	RunID  int64
+	Repo   string
+	Labels []string
+}
+
+// RunnerGroup represents a self-hosted runner group of an organization.
+type RunnerGroup struct {
+	ID   int64
+	Name string
+}
+
+// DefaultRunnerGroupID is the ID of the Default runner group.
+const DefaultRunnerGroupID int64 = 1
+
+// Visibility of a runner group which is available only for the selected repositories.
+const runnerGroupVisibilitySelected = "selected"
+
+// IsNotFound returns true if GitHub responded that the resource is not found.
+func IsNotFound(err error) bool {
+	var errRes *github.ErrorResponse
+	return errors.As(err, &errRes) && errRes.Response != nil && errRes.Response.StatusCode == http.StatusNotFound
+}
+
+func hasLabels(actual, required []string) bool {
 	actualLabelMap := map[string]struct{}{}
-	for _, l := range r.Labels {
+	for _, l := range actual {
 		actualLabelMap[l] = struct{}{}
 	}
-	for _, required := range labels {
-		if _, ok := actualLabelMap[required]; !ok {
+	for _, r := range required {
+		if _, ok := actualLabelMap[r]; !ok {
 			return false
 		}
 	}
 	return true
 }
 
+// genKey returns the key to identify the repository or the organization.
+func genKey(owner, repo string) string {
+	if repo == "" {
+		return owner
+	}
+	return owner + "/" + repo
+}
+
+// genEnterpriseKey returns the key to identify the enterprise.
+func genEnterpriseKey(enterprise string) string {
+	return "enterprises/" + enterprise
+}
+
 // Client generates token for GitHub Action selfhosted runner
 type Client interface {
 	CreateRegistrationToken(context.Context, string, string) (*github.RegistrationToken, error)
 	ListRunners(context.Context, string, string, []string) ([]*Runner, error)
 	RemoveRunner(context.Context, string, string, int64) error
+	ListQueuedJobs(context.Context, string, string, []string, PollingOptions) ([]*Job, bool, error)
+	GetJobURL(context.Context, string, string, int64, int64, string) (string, error)
+	ListRunnerGroups(context.Context, string) ([]*RunnerGroup, error)
+	CreateRunnerGroup(context.Context, string, string) (*RunnerGroup, error)
+	GenerateJITConfig(context.Context, string, string, string, int64, []string) (string, error)
+	CreateEnterpriseRegistrationToken(context.Context, string) (*github.RegistrationToken, error)
+	ListEnterpriseRunners(context.Context, string, []string) ([]*Runner, error)
+	RemoveEnterpriseRunner(context.Context, string, int64) error
+	GenerateEnterpriseJITConfig(context.Context, string, string, int64, []string) (string, error)
 }
 
 type ClientCredential struct {
+	// GitHubURL is the URL of the GitHub server. If this is empty, GitHub.com is used.
+	GitHubURL string
+
 	PersonalAccessToken string
 	AppID               int64
 	AppInstallationID   int64
 	PrivateKey          []byte
 	PrivateKeyPath      string
+
+	// Owner and Repository are the organization or the repository to look up the installation of the GitHub App
+	// when AppInstallationID is 0.
+	Owner      string
+	Repository string
+}
+
+// Equal returns true if the two credentials have the same contents.
+func (c *ClientCredential) Equal(other *ClientCredential) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return c.GitHubURL == other.GitHubURL &&
+		c.PersonalAccessToken == other.PersonalAccessToken &&
+		c.AppID == other.AppID &&
+		c.AppInstallationID == other.AppInstallationID &&
+		bytes.Equal(c.PrivateKey, other.PrivateKey) &&
+		c.PrivateKeyPath == other.PrivateKeyPath &&
+		c.Owner == other.Owner &&
+		c.Repository == other.Repository
 }
 
 // ClientFactory is a factory of Clients.
@@ -70,61 +186,131 @@ type ClientFactory interface {
 	New(*ClientCredential) (Client, error)
 }
 
-type defaultFactory struct{}
+type defaultFactory struct {
+	mu            sync.Mutex
+	limiters      map[string]*rateLimiter
+	installations *installationCache
+}
 
+// NewFactory returns a ClientFactory.
+// The clients created with the same credential share the rate limit state, and suspend the requests while it is exceeded.
+// If the credential of a GitHub App does not have the installation ID, the factory looks up the installation
+// on the owner of the credential, and caches it until GitHub rejects it.
 func NewFactory() ClientFactory {
-	return &defaultFactory{}
+	return &defaultFactory{
+		limiters: map[string]*rateLimiter{},
+		installations: &installationCache{
+			entries: map[string]installationEntry{},
+		},
+	}
 }
 
 func (f *defaultFactory) New(cred *ClientCredential) (Client, error) {
+	server := strings.TrimSuffix(cred.GitHubURL, "/")
+	if server == "" {
+		server = constants.DefaultGitHubURL
+	}
+
 	switch {
 	case len(cred.PersonalAccessToken) != 0:
-		return newClientFromPAT(cred.PersonalAccessToken), nil
-	case len(cred.PrivateKey) != 0:
-		return newClientFromAppKey(cred.AppID, cred.AppInstallationID, cred.PrivateKey)
-	case len(cred.PrivateKeyPath) != 0:
-		return newClientFromAppKeyFile(cred.AppID, cred.AppInstallationID, cred.PrivateKeyPath)
+		limiter := f.rateLimiter(server, credentialName(cred))
+		return newClientFromPAT(cred.GitHubURL, cred.PersonalAccessToken, limiter)
+	case len(cred.PrivateKey) != 0 || len(cred.PrivateKeyPath) != 0:
+		// The App JWT has its own rate limit apart from the installations.
+		insID, err := f.installations.installationID(server, cred, f.rateLimiter(server, appCredentialName(cred.AppID, 0)))
+		if err != nil {
+			return nil, err
+		}
+		limiter := f.rateLimiter(server, appCredentialName(cred.AppID, insID))
+		invalidate := f.installations.invalidator(server, cred)
+		if len(cred.PrivateKey) != 0 {
+			return newClientFromAppKey(cred.GitHubURL, cred.AppID, insID, cred.PrivateKey, limiter, invalidate)
+		}
+		return newClientFromAppKeyFile(cred.GitHubURL, cred.AppID, insID, cred.PrivateKeyPath, limiter, invalidate)
 	default:
 		return nil, errors.New("invalid credential")
 	}
 }
 
+func (f *defaultFactory) rateLimiter(server, name string) *rateLimiter {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := server + " " + name
+	l, ok := f.limiters[key]
+	if !ok {
+		l = &rateLimiter{server: server, credential: name}
+		f.limiters[key] = l
+	}
+	return l
+}
+
 // clientWrapper is a wrapper of GitHub client.
 type clientWrapper struct {
 	client *github.Client
 }
 
+// newGitHubClient creates a go-github client for the GitHub server.
+// For GitHub Enterprise Server, the API is served under `/api/v3/` of the server URL.
+func newGitHubClient(httpClient *http.Client, githubURL string) (*github.Client, error) {
+	client := github.NewClient(httpClient)
+	if githubURL == "" || strings.TrimSuffix(githubURL, "/") == constants.DefaultGitHubURL {
+		return client, nil
+	}
+	return client.WithEnterpriseURLs(githubURL, githubURL)
+}
+
 // newClientFromPAT creates GitHub Actions Client from a personal access token (PAT).
-func newClientFromPAT(pat string) Client {
+func newClientFromPAT(githubURL, pat string, limiter *rateLimiter) (Client, error) {
 	ctx := context.Background()
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: pat},
 	)
 	tc := oauth2.NewClient(ctx, ts)
-	return &clientWrapper{
-		client: github.NewClient(tc),
+	tc.Transport = &rateLimitTransport{base: tc.Transport, limiter: limiter}
+	client, err := newGitHubClient(tc, githubURL)
+	if err != nil {
+		return nil, err
 	}
+	return &clientWrapper{
+		client: client,
+	}, nil
 }
 
 // newClientFromAppKey creates GitHub Actions Client from a private key of a GitHub app.
-func newClientFromAppKey(appID, appInstallationID int64, privateKey []byte) (Client, error) {
+// If invalidate is not nil, it is called when the installation is rejected.
+func newClientFromAppKey(githubURL string, appID, appInstallationID int64, privateKey []byte, limiter *rateLimiter, invalidate func()) (Client, error) {
 	rt, err := ghinstallation.New(http.DefaultTransport, appID, appInstallationID, privateKey)
 	if err != nil {
 		return nil, err
 	}
-	return &clientWrapper{
-		client: github.NewClient(&http.Client{Transport: rt}),
-	}, nil
+	return newClientFromAppTransport(githubURL, rt, limiter, invalidate)
 }
 
 // newClientFromAPIKey creates GitHub Actions Client from a private key of a GitHub app.
-func newClientFromAppKeyFile(appID, appInstallationID int64, privateKeyPath string) (Client, error) {
+// If invalidate is not nil, it is called when the installation is rejected.
+func newClientFromAppKeyFile(githubURL string, appID, appInstallationID int64, privateKeyPath string, limiter *rateLimiter, invalidate func()) (Client, error) {
 	rt, err := ghinstallation.NewKeyFromFile(http.DefaultTransport, appID, appInstallationID, privateKeyPath)
 	if err != nil {
 		return nil, err
 	}
+	return newClientFromAppTransport(githubURL, rt, limiter, invalidate)
+}
+
+func newClientFromAppTransport(githubURL string, rt *ghinstallation.Transport, limiter *rateLimiter, invalidate func()) (Client, error) {
+	var base http.RoundTripper = rt
+	if invalidate != nil {
+		base = &installationTransport{base: rt, invalidate: invalidate}
+	}
+	client, err := newGitHubClient(&http.Client{Transport: &rateLimitTransport{base: base, limiter: limiter}}, githubURL)
+	if err != nil {
+		return nil, err
+	}
+	// The installation token has to be issued by the same server.
+	rt.BaseURL = strings.TrimSuffix(client.BaseURL.String(), "/")
 	return &clientWrapper{
-		client: github.NewClient(&http.Client{Transport: rt}),
+		client: client,
 	}, nil
 }
 
@@ -145,6 +331,10 @@ func (c *clientWrapper) CreateRegistrationToken(ctx context.Context, owner, repo
 			repo,
 		)
 	}
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return nil, rateLimitErr
+	}
 	if e, ok := err.(*url.Error); ok {
 		// When url.Error came back, it was because the raw Responce leaked out as a string.
 		return nil, fmt.Errorf("failed to create registration token: %s %s", e.Op, e.URL)
@@ -201,7 +391,6 @@ func (c *clientWrapper) ListRunners(ctx context.Context, owner, repo string, lab
 		}
 
 		opts.ListOptions.Page = res.NextPage
-		time.Sleep(500 * time.Microsecond)
 	}
 	return runners, nil
 }
@@ -232,3 +421,319 @@ func (c *clientWrapper) RemoveRunner(ctx context.Context, owner, repo string, ru
 	}
 	return nil
 }
+
+// ListQueuedJobs lists the workflow jobs waiting for a runner which has all the labels.
+// If repo is empty, it lists the jobs in the repositories of the organization which are pushed within
+// the window of opts, up to the max number of the most recently pushed ones.
+// It also returns true if some repositories pushed within the window are not polled because of the max number.
+func (c *clientWrapper) ListQueuedJobs(ctx context.Context, owner, repo string, labels []string, opts PollingOptions) ([]*Job, bool, error) {
+	repos := []string{repo}
+	var truncated bool
+	if repo == "" {
+		var err error
+		repos, truncated, err = c.listActiveOrganizationRepositories(ctx, owner, time.Now().Add(-opts.window()), opts.maxRepositories())
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	var jobs []*Job
+	for _, r := range repos {
+		// A queued job may belong to a workflow run which is already in progress.
+		for _, status := range []string{statusQueued, statusInProgress} {
+			runIDs, err := c.listWorkflowRuns(ctx, owner, r, status)
+			if err != nil {
+				return nil, false, err
+			}
+			for _, runID := range runIDs {
+				list, err := c.listQueuedJobsOfRun(ctx, owner, r, runID, labels)
+				if err != nil {
+					return nil, false, err
+				}
+				jobs = append(jobs, list...)
+			}
+		}
+	}
+	return jobs, truncated, nil
+}
+
+// GetJobURL returns the HTML URL of the job in the workflow run which is run by the runner.
+// If attempt is 0, the latest attempt of the workflow run is searched.
+func (c *clientWrapper) GetJobURL(ctx context.Context, owner, repo string, runID, attempt int64, runnerName string) (string, error) {
+	opts := github.ListOptions{PerPage: 100}
+	for {
+		var list *github.Jobs
+		var res *github.Response
+		var err error
+		if attempt == 0 {
+			list, res, err = c.client.Actions.ListWorkflowJobs(ctx, owner, repo, runID, &github.ListWorkflowJobsOptions{Filter: "latest", ListOptions: opts})
+		} else {
+			list, res, err = c.client.Actions.ListWorkflowJobsAttempt(ctx, owner, repo, runID, attempt, &opts)
+		}
+		if err != nil {
+			return "", err
+		}
+		if res.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("invalid status code %d", res.StatusCode)
+		}
+
+		for _, j := range list.Jobs {
+			if j.GetRunnerName() == runnerName {
+				return j.GetHTMLURL(), nil
+			}
+		}
+		if res.NextPage == 0 {
+			break
+		}
+
+		opts.Page = res.NextPage
+	}
+	return "", fmt.Errorf("job run by %s is not found in workflow run %d", runnerName, runID)
+}
+
+// listActiveOrganizationRepositories lists at most limit repositories of the organization pushed after since,
+// in the order of the latest push. It also returns true if more repositories are pushed after since.
+func (c *clientWrapper) listActiveOrganizationRepositories(ctx context.Context, org string, since time.Time, limit int) ([]string, bool, error) {
+	var repos []string
+
+	opts := github.RepositoryListByOrgOptions{Sort: "pushed", Direction: "desc", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		list, res, err := c.client.Repositories.ListByOrg(ctx, org, &opts)
+		if err != nil {
+			return nil, false, err
+		}
+		if res.StatusCode != http.StatusOK {
+			return nil, false, fmt.Errorf("invalid status code %d", res.StatusCode)
+		}
+
+		for _, r := range list {
+			if r.GetPushedAt().Before(since) {
+				return repos, false, nil
+			}
+			if r.GetArchived() {
+				continue
+			}
+			if len(repos) >= limit {
+				return repos, true, nil
+			}
+			repos = append(repos, r.GetName())
+		}
+		if res.NextPage == 0 {
+			break
+		}
+
+		opts.ListOptions.Page = res.NextPage
+	}
+	return repos, false, nil
+}
+
+func (c *clientWrapper) listWorkflowRuns(ctx context.Context, owner, repo, status string) ([]int64, error) {
+	var runIDs []int64
+
+	opts := github.ListWorkflowRunsOptions{Status: status, ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		list, res, err := c.client.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, &opts)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("invalid status code %d", res.StatusCode)
+		}
+
+		for _, run := range list.WorkflowRuns {
+			runIDs = append(runIDs, run.GetID())
+		}
+		if res.NextPage == 0 {
+			break
+		}
+
+		opts.ListOptions.Page = res.NextPage
+	}
+	return runIDs, nil
+}
+
+func (c *clientWrapper) listQueuedJobsOfRun(ctx context.Context, owner, repo string, runID int64, labels []string) ([]*Job, error) {
+	var jobs []*Job
+
+	opts := github.ListWorkflowJobsOptions{Filter: "latest", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		list, res, err := c.client.Actions.ListWorkflowJobs(ctx, owner, repo, runID, &opts)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("invalid status code %d", res.StatusCode)
+		}
+
+		for _, j := range list.Jobs {
+			if j.GetStatus() != statusQueued || !hasLabels(j.Labels, labels) {
+				continue
+			}
+			jobs = append(jobs, &Job{
+				ID:     j.GetID(),
+				RunID:  runID,
+				Repo:   repo,
+				Labels: j.Labels,
+			})
+		}
+		if res.NextPage == 0 {
+			break
+		}
+
+		opts.ListOptions.Page = res.NextPage
+	}
+	return jobs, nil
+}
+
+// ListRunnerGroups lists the self-hosted runner groups of the organization.
+func (c *clientWrapper) ListRunnerGroups(ctx context.Context, org string) ([]*RunnerGroup, error) {
+	var groups []*RunnerGroup
+
+	opts := github.ListOrgRunnerGroupOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		list, res, err := c.client.Actions.ListOrganizationRunnerGroups(ctx, org, &opts)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("invalid status code %d", res.StatusCode)
+		}
+
+		for _, g := range list.RunnerGroups {
+			groups = append(groups, &RunnerGroup{
+				ID:   g.GetID(),
+				Name: g.GetName(),
+			})
+		}
+		if res.NextPage == 0 {
+			break
+		}
+
+		opts.ListOptions.Page = res.NextPage
+	}
+	return groups, nil
+}
+
+// CreateRunnerGroup creates a self-hosted runner group in the organization.
+// The group is created without any allowed repositories, so that the administrators of the organization select them.
+func (c *clientWrapper) CreateRunnerGroup(ctx context.Context, org, name string) (*RunnerGroup, error) {
+	g, res, err := c.client.Actions.CreateOrganizationRunnerGroup(ctx, org, github.CreateRunnerGroupRequest{
+		Name:       github.Ptr(name),
+		Visibility: github.Ptr(runnerGroupVisibilitySelected),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("invalid status code %d", res.StatusCode)
+	}
+	return &RunnerGroup{
+		ID:   g.GetID(),
+		Name: g.GetName(),
+	}, nil
+}
+
+// GenerateJITConfig registers a runner and returns the encoded just-in-time configuration for it.
+// The configuration can be used only by one runner, and it is passed to `Runner.Listener run --jitconfig`.
+func (c *clientWrapper) GenerateJITConfig(ctx context.Context, owner, repo, name string, runnerGroupID int64, labels []string) (string, error) {
+	req := &github.GenerateJITConfigRequest{
+		Name:          name,
+		RunnerGroupID: runnerGroupID,
+		Labels:        labels,
+	}
+
+	var config *github.JITRunnerConfig
+	var res *github.Response
+	var err error
+	if repo == "" {
+		config, res, err = c.client.Actions.GenerateOrgJITConfig(ctx, owner, req)
+	} else {
+		config, res, err = c.client.Actions.GenerateRepoJITConfig(ctx, owner, repo, req)
+	}
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("invalid status code %d", res.StatusCode)
+	}
+	return config.GetEncodedJITConfig(), nil
+}
+
+// CreateEnterpriseRegistrationToken creates an Actions token to register self-hosted runner to the enterprise.
+func (c *clientWrapper) CreateEnterpriseRegistrationToken(ctx context.Context, enterprise string) (*github.RegistrationToken, error) {
+	token, res, err := c.client.Enterprise.CreateRegistrationToken(ctx, enterprise)
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return nil, rateLimitErr
+	}
+	if e, ok := err.(*url.Error); ok {
+		// When url.Error came back, it was because the raw Responce leaked out as a string.
+		return nil, fmt.Errorf("failed to create registration token: %s %s", e.Op, e.URL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("invalid status code %d", res.StatusCode)
+	}
+
+	return token, nil
+}
+
+// ListEnterpriseRunners lists registered self-hosted runners for the enterprise.
+func (c *clientWrapper) ListEnterpriseRunners(ctx context.Context, enterprise string, labels []string) ([]*Runner, error) {
+	var runners []*Runner
+
+	opts := github.ListRunnersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		list, res, err := c.client.Enterprise.ListRunners(ctx, enterprise, &opts)
+		if err != nil {
+			return nil, err
+		}
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("invalid status code %d", res.StatusCode)
+		}
+
+		for _, ghRunner := range list.Runners {
+			r := convert(ghRunner)
+			if !r.hasLabels(labels) {
+				continue
+			}
+			runners = append(runners, r)
+		}
+		if res.NextPage == 0 {
+			break
+		}
+
+		opts.ListOptions.Page = res.NextPage
+	}
+	return runners, nil
+}
+
+// RemoveEnterpriseRunner deletes an Actions runner of the enterprise.
+func (c *clientWrapper) RemoveEnterpriseRunner(ctx context.Context, enterprise string, runnerID int64) error {
+	res, err := c.client.Enterprise.RemoveRunner(ctx, enterprise, runnerID)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("invalid status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+// GenerateEnterpriseJITConfig registers a runner to the enterprise and returns the encoded just-in-time configuration for it.
+func (c *clientWrapper) GenerateEnterpriseJITConfig(ctx context.Context, enterprise, name string, runnerGroupID int64, labels []string) (string, error) {
+	config, res, err := c.client.Enterprise.GenerateEnterpriseJITConfig(ctx, enterprise, &github.GenerateJITConfigRequest{
+		Name:          name,
+		RunnerGroupID: runnerGroupID,
+		Labels:        labels,
+	})
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("invalid status code %d", res.StatusCode)
+	}
+	return config.GetEncodedJITConfig(), nil
+}
@@ -0,0 +1,163 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestListQueuedJobsOfOrganization(t *testing.T) {
+	now := time.Now()
+	var repos []map[string]any
+	for i := range DefaultMaxPolledRepositories + 10 {
+		repos = append(repos, map[string]any{
+			"name":      fmt.Sprintf("active%d", i),
+			"pushed_at": now.Add(-time.Duration(i) * time.Minute).Format(time.RFC3339),
+		})
+	}
+	repos[1]["archived"] = true
+	repos = append(repos, map[string]any{
+		"name":      "inactive",
+		"pushed_at": now.Add(-DefaultPollingWindow - time.Hour).Format(time.RFC3339),
+	})
+
+	testCases := []struct {
+		title     string
+		repos     []map[string]any
+		opts      PollingOptions
+		expected  []string
+		count     int
+		truncated bool
+	}{
+		{
+			title:    "active repositories",
+			repos:    append(repos[:3:3], repos[len(repos)-1]),
+			expected: []string{"active0", "active2"},
+			count:    2,
+		},
+		{
+			title:    "limit equal to the active repositories",
+			repos:    append(repos[:3:3], repos[len(repos)-1]),
+			opts:     PollingOptions{MaxRepositories: 2},
+			expected: []string{"active0", "active2"},
+			count:    2,
+		},
+		{
+			title:     "capped",
+			repos:     repos,
+			count:     DefaultMaxPolledRepositories,
+			truncated: true,
+		},
+		{
+			title:     "custom limit",
+			repos:     repos,
+			opts:      PollingOptions{MaxRepositories: 5},
+			expected:  []string{"active0", "active2", "active3", "active4", "active5"},
+			count:     5,
+			truncated: true,
+		},
+		{
+			title: "custom window",
+			repos: repos,
+			opts:  PollingOptions{MaxRepositories: 100, Window: 10 * time.Minute},
+			count: 9,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			var mu sync.Mutex
+			var polled []string
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v3/orgs/org/repos", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("sort") != "pushed" || r.URL.Query().Get("direction") != "desc" {
+					t.Errorf("repositories are not sorted by push: %s", r.URL.RawQuery)
+				}
+				json.NewEncoder(w).Encode(tt.repos)
+			})
+			mux.HandleFunc("GET /api/v3/repos/org/{repo}/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				polled = append(polled, r.PathValue("repo"))
+				mu.Unlock()
+				json.NewEncoder(w).Encode(map[string]any{"total_count": 0, "workflow_runs": []any{}})
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			c, err := newClientFromPAT(server.URL, "pat", &rateLimiter{server: server.URL, credential: "test"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, truncated, err := c.ListQueuedJobs(context.Background(), "org", "", nil, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if truncated != tt.truncated {
+				t.Errorf("truncated is %v, want %v", truncated, tt.truncated)
+			}
+
+			polled = slices.Compact(polled)
+			if tt.expected != nil && !slices.Equal(polled, tt.expected) {
+				t.Errorf("polled %v, want %v", polled, tt.expected)
+			}
+			if len(polled) != tt.count {
+				t.Errorf("%d repositories are polled, want %d", len(polled), tt.count)
+			}
+			for _, r := range polled {
+				if !strings.HasPrefix(r, "active") {
+					t.Errorf("inactive repository %s is polled", r)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateJITConfigNotFound(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/orgs/org/actions/runners/generate-jitconfig", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			RunnerGroupID int64 `json:"runner_group_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.RunnerGroupID != DefaultRunnerGroupID {
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"encoded_jit_config": "config"}`))
+	})
+	mux.HandleFunc("/api/v3/orgs/org/actions/runners/registration-token", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Forbidden"}`, http.StatusForbidden)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c, err := newClientFromPAT(server.URL, "pat", &rateLimiter{server: server.URL, credential: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	config, err := c.GenerateJITConfig(ctx, "org", "", "runner", DefaultRunnerGroupID, nil)
+	if err != nil || config != "config" {
+		t.Fatalf("unexpected result: %q, %v", config, err)
+	}
+	_, err = c.GenerateJITConfig(ctx, "org", "", "runner", 2, nil)
+	if !IsNotFound(err) {
+		t.Errorf("not found error is not detected: %v", err)
+	}
+	_, err = c.CreateRegistrationToken(ctx, "org", "")
+	if err == nil || IsNotFound(err) {
+		t.Errorf("forbidden error is detected as not found: %v", err)
+	}
+}
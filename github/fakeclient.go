@@ -3,6 +3,8 @@ package github
 import (
 	"context"
 	"errors"
+	"net/http"
+	"slices"
 	"sync"
 	"time"
 
@@ -12,23 +14,22 @@ import (
 type FakeClientFactory struct {
 	mu                sync.Mutex
 	runners           map[string][]*Runner
+	queuedJobs        map[string][]*Job
+	jobURLs           map[string]string
+	runnerGroups      map[string][]*RunnerGroup
 	expiredAtDuration time.Duration
 }
 
 func NewFakeClientFactory() *FakeClientFactory {
 	return &FakeClientFactory{
 		runners:           map[string][]*Runner{},
+		queuedJobs:        map[string][]*Job{},
+		jobURLs:           map[string]string{},
+		runnerGroups:      map[string][]*RunnerGroup{},
 		expiredAtDuration: 1 * time.Hour,
 	}
 }
 
-func genKey(owner, repo string) string {
-	if repo == "" {
-		return owner
-	}
-	return owner + "/" + repo
-}
-
 func (f *FakeClientFactory) New(_ *ClientCredential) (Client, error) {
 	return &FakeClient{parent: f}, nil
 }
@@ -48,11 +49,18 @@ func (f *FakeClientFactory) createRegistrationToken(ctx context.Context, owner,
 
 // ListRunners returns dummy list.
 func (f *FakeClientFactory) ListRunners(ctx context.Context, owner, repo string, labels []string) ([]*Runner, error) {
+	return f.listRunners(genKey(owner, repo), labels)
+}
+
+// ListEnterpriseRunners returns dummy list.
+func (f *FakeClientFactory) ListEnterpriseRunners(ctx context.Context, enterprise string, labels []string) ([]*Runner, error) {
+	return f.listRunners(genEnterpriseKey(enterprise), labels)
+}
+
+func (f *FakeClientFactory) listRunners(key string, labels []string) ([]*Runner, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	key := genKey(owner, repo)
-
 	ret := []*Runner{}
 	runners := f.runners[key]
 	for _, r := range runners {
@@ -65,11 +73,18 @@ func (f *FakeClientFactory) ListRunners(ctx context.Context, owner, repo string,
 
 // RemoveRunner does not delete anything and returns success.
 func (f *FakeClientFactory) RemoveRunner(ctx context.Context, owner, repo string, runnerID int64) error {
+	return f.removeRunner(genKey(owner, repo), runnerID)
+}
+
+// RemoveEnterpriseRunner does not delete anything and returns success.
+func (f *FakeClientFactory) RemoveEnterpriseRunner(ctx context.Context, enterprise string, runnerID int64) error {
+	return f.removeRunner(genEnterpriseKey(enterprise), runnerID)
+}
+
+func (f *FakeClientFactory) removeRunner(key string, runnerID int64) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	key := genKey(owner, repo)
-
 	// skip existence and nil check below because this is mock
 	runners := f.runners[key]
 	for i, v := range runners {
@@ -81,6 +96,134 @@ func (f *FakeClientFactory) RemoveRunner(ctx context.Context, owner, repo string
 	return errors.New("not exist")
 }
 
+// ListQueuedJobs returns dummy list.
+// For an organization, only the jobs of the first repositories up to the max number of opts are listed,
+// in the order of the dummy list.
+func (f *FakeClientFactory) ListQueuedJobs(ctx context.Context, owner, repo string, labels []string, opts PollingOptions) ([]*Job, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := genKey(owner, repo)
+
+	ret := []*Job{}
+	polled := map[string]bool{}
+	var truncated bool
+	for _, j := range f.queuedJobs[key] {
+		if repo == "" && !polled[j.Repo] {
+			if len(polled) >= opts.maxRepositories() {
+				truncated = true
+				continue
+			}
+			polled[j.Repo] = true
+		}
+		if hasLabels(j.Labels, labels) {
+			ret = append(ret, j)
+		}
+	}
+	return ret, truncated, nil
+}
+
+func (f *FakeClientFactory) SetQueuedJobs(jobs map[string][]*Job) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.queuedJobs = jobs
+}
+
+// GetJobURL returns the dummy URL of the job run by the runner.
+func (f *FakeClientFactory) GetJobURL(ctx context.Context, owner, repo string, runID, attempt int64, runnerName string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, ok := f.jobURLs[runnerName]
+	if !ok {
+		return "", errors.New("not exist")
+	}
+	return u, nil
+}
+
+// SetJobURLs sets the dummy URLs of the jobs keyed by the runner names.
+func (f *FakeClientFactory) SetJobURLs(urls map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.jobURLs = urls
+}
+
+// ListRunnerGroups returns dummy list.
+func (f *FakeClientFactory) ListRunnerGroups(ctx context.Context, org string) ([]*RunnerGroup, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]*RunnerGroup{}, f.runnerGroups[org]...), nil
+}
+
+// CreateRunnerGroup adds a dummy runner group.
+func (f *FakeClientFactory) CreateRunnerGroup(ctx context.Context, org, name string) (*RunnerGroup, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, g := range f.runnerGroups[org] {
+		if g.Name == name {
+			return nil, errors.New("already exists")
+		}
+	}
+	g := &RunnerGroup{
+		ID:   int64(len(f.runnerGroups[org]) + 1),
+		Name: name,
+	}
+	f.runnerGroups[org] = append(f.runnerGroups[org], g)
+	return g, nil
+}
+
+// GenerateJITConfig registers a dummy offline runner and returns a dummy config.
+// It returns the not found error if the runner group of the organization does not exist.
+func (f *FakeClientFactory) GenerateJITConfig(ctx context.Context, owner, repo, name string, runnerGroupID int64, labels []string) (string, error) {
+	if repo == "" && runnerGroupID != DefaultRunnerGroupID {
+		f.mu.Lock()
+		found := slices.ContainsFunc(f.runnerGroups[owner], func(g *RunnerGroup) bool { return g.ID == runnerGroupID })
+		f.mu.Unlock()
+		if !found {
+			return "", &github.ErrorResponse{
+				Response: &http.Response{StatusCode: http.StatusNotFound},
+				Message:  "Not Found",
+			}
+		}
+	}
+	return f.generateJITConfig(genKey(owner, repo), name, labels)
+}
+
+// GenerateEnterpriseJITConfig registers a dummy offline runner and returns a dummy config.
+func (f *FakeClientFactory) GenerateEnterpriseJITConfig(ctx context.Context, enterprise, name string, runnerGroupID int64, labels []string) (string, error) {
+	return f.generateJITConfig(genEnterpriseKey(enterprise), name, labels)
+}
+
+func (f *FakeClientFactory) generateJITConfig(key, name string, labels []string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var id int64
+	for _, r := range f.runners[key] {
+		if r.Name == name {
+			return "", errors.New("already exists")
+		}
+		id = max(id, r.ID)
+	}
+	f.runners[key] = append(f.runners[key], &Runner{
+		ID:     id + 1,
+		Name:   name,
+		Labels: labels,
+	})
+	return "fakejitconfig-" + name, nil
+}
+
+func (f *FakeClientFactory) SetRunnerGroups(groups map[string][]*RunnerGroup) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.runnerGroups = groups
+}
+
 func (f *FakeClientFactory) SetRunners(runners map[string][]*Runner) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -114,3 +257,48 @@ func (c *FakeClient) ListRunners(ctx context.Context, owner, repo string, labels
 func (c *FakeClient) RemoveRunner(ctx context.Context, owner, repo string, runnerID int64) error {
 	return c.parent.RemoveRunner(ctx, owner, repo, runnerID)
 }
+
+// ListQueuedJobs returns dummy list.
+func (c *FakeClient) ListQueuedJobs(ctx context.Context, owner, repo string, labels []string, opts PollingOptions) ([]*Job, bool, error) {
+	return c.parent.ListQueuedJobs(ctx, owner, repo, labels, opts)
+}
+
+// GetJobURL returns the dummy URL of the job run by the runner.
+func (c *FakeClient) GetJobURL(ctx context.Context, owner, repo string, runID, attempt int64, runnerName string) (string, error) {
+	return c.parent.GetJobURL(ctx, owner, repo, runID, attempt, runnerName)
+}
+
+// ListRunnerGroups returns dummy list.
+func (c *FakeClient) ListRunnerGroups(ctx context.Context, org string) ([]*RunnerGroup, error) {
+	return c.parent.ListRunnerGroups(ctx, org)
+}
+
+// CreateRunnerGroup adds a dummy runner group.
+func (c *FakeClient) CreateRunnerGroup(ctx context.Context, org, name string) (*RunnerGroup, error) {
+	return c.parent.CreateRunnerGroup(ctx, org, name)
+}
+
+// GenerateJITConfig registers a dummy offline runner and returns a dummy config.
+func (c *FakeClient) GenerateJITConfig(ctx context.Context, owner, repo, name string, runnerGroupID int64, labels []string) (string, error) {
+	return c.parent.GenerateJITConfig(ctx, owner, repo, name, runnerGroupID, labels)
+}
+
+// CreateEnterpriseRegistrationToken returns dummy token.
+func (c *FakeClient) CreateEnterpriseRegistrationToken(ctx context.Context, enterprise string) (*github.RegistrationToken, error) {
+	return c.parent.createRegistrationToken(ctx, enterprise, "")
+}
+
+// ListEnterpriseRunners returns dummy list.
+func (c *FakeClient) ListEnterpriseRunners(ctx context.Context, enterprise string, labels []string) ([]*Runner, error) {
+	return c.parent.ListEnterpriseRunners(ctx, enterprise, labels)
+}
+
+// RemoveEnterpriseRunner does not delete anything and returns success.
+func (c *FakeClient) RemoveEnterpriseRunner(ctx context.Context, enterprise string, runnerID int64) error {
+	return c.parent.RemoveEnterpriseRunner(ctx, enterprise, runnerID)
+}
+
+// GenerateEnterpriseJITConfig registers a dummy offline runner and returns a dummy config.
+func (c *FakeClient) GenerateEnterpriseJITConfig(ctx context.Context, enterprise, name string, runnerGroupID int64, labels []string) (string, error) {
+	return c.parent.GenerateEnterpriseJITConfig(ctx, enterprise, name, runnerGroupID, labels)
+}
@@ -0,0 +1,138 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+)
+
+const (
+	// The installation of a GitHub App changes only when the App is reinstalled, so its ID is cached long.
+	installationCacheTTL = time.Hour
+
+	installationLookupTimeout = 30 * time.Second
+)
+
+type installationEntry struct {
+	id        int64
+	fetchedAt time.Time
+}
+
+// installationCache keeps the installation IDs of the GitHub Apps looked up with the App JWT.
+type installationCache struct {
+	mu      sync.Mutex
+	entries map[string]installationEntry
+}
+
+func installationKey(server string, cred *ClientCredential) string {
+	return server + " " + strconv.FormatInt(cred.AppID, 10) + " " + cred.Owner
+}
+
+// installationID returns the installation ID of the App for the owner of the credential.
+// If the credential has the installation ID, it is returned as it is.
+// The lookup is sent through the limiter of the App JWT.
+func (c *installationCache) installationID(server string, cred *ClientCredential, limiter *rateLimiter) (int64, error) {
+	if cred.AppInstallationID != 0 {
+		return cred.AppInstallationID, nil
+	}
+	if cred.Owner == "" {
+		return 0, errors.New("app installation ID is required for the credential without the organization or the repository owner")
+	}
+
+	key := installationKey(server, cred)
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Since(e.fetchedAt) < installationCacheTTL {
+		return e.id, nil
+	}
+
+	id, err := findInstallation(cred, limiter)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = installationEntry{id: id, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return id, nil
+}
+
+// invalidator returns the function to forget the cached installation ID of the credential.
+// It returns nil if the credential has the installation ID.
+func (c *installationCache) invalidator(server string, cred *ClientCredential) func() {
+	if cred.AppInstallationID != 0 {
+		return nil
+	}
+	key := installationKey(server, cred)
+	return func() {
+		c.mu.Lock()
+		delete(c.entries, key)
+		c.mu.Unlock()
+	}
+}
+
+// installationTransport forgets the cached installation ID when GitHub rejects it,
+// e.g. the App is reinstalled, so that the next client looks up the installation again.
+type installationTransport struct {
+	base       http.RoundTripper
+	invalidate func()
+}
+
+func (t *installationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.base.RoundTrip(req)
+
+	// The installation token cannot be issued for the removed installation.
+	var httpErr *ghinstallation.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Response != nil {
+		switch httpErr.Response.StatusCode {
+		case http.StatusUnauthorized, http.StatusNotFound:
+			t.invalidate()
+		}
+	}
+	if err == nil && res.StatusCode == http.StatusUnauthorized {
+		t.invalidate()
+	}
+	return res, err
+}
+
+// findInstallation looks up the installation of the App on the organization or the repository with the App JWT.
+func findInstallation(cred *ClientCredential, limiter *rateLimiter) (int64, error) {
+	base := &rateLimitTransport{base: http.DefaultTransport, limiter: limiter}
+	var rt *ghinstallation.AppsTransport
+	var err error
+	if len(cred.PrivateKey) != 0 {
+		rt, err = ghinstallation.NewAppsTransport(base, cred.AppID, cred.PrivateKey)
+	} else {
+		rt, err = ghinstallation.NewAppsTransportKeyFromFile(base, cred.AppID, cred.PrivateKeyPath)
+	}
+	if err != nil {
+		return 0, err
+	}
+	client, err := newGitHubClient(&http.Client{Transport: rt}, cred.GitHubURL)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), installationLookupTimeout)
+	defer cancel()
+
+	if cred.Repository == "" {
+		ins, _, err := client.Apps.FindOrganizationInstallation(ctx, cred.Owner)
+		if err != nil {
+			return 0, fmt.Errorf("failed to find the app installation on organization %s: %w", cred.Owner, err)
+		}
+		return ins.GetID(), nil
+	}
+	ins, _, err := client.Apps.FindRepositoryInstallation(ctx, cred.Owner, cred.Repository)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find the app installation on repository %s/%s: %w", cred.Owner, cred.Repository, err)
+	}
+	return ins.GetID(), nil
+}
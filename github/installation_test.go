@@ -0,0 +1,263 @@
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeAppServer serves the APIs to look up the installations of an App and to issue their tokens.
+type fakeAppServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	lookups     int
+	tokens      []string
+	removed     map[string]bool
+	rateLimited bool
+}
+
+func newFakeAppServer() *fakeAppServer {
+	s := &fakeAppServer{removed: map[string]bool{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/orgs/{org}/installation", func(w http.ResponseWriter, r *http.Request) {
+		s.lookup(w, r.PathValue("org"), 11)
+	})
+	mux.HandleFunc("GET /api/v3/repos/{owner}/{repo}/installation", func(w http.ResponseWriter, r *http.Request) {
+		s.lookup(w, r.PathValue("owner")+"/"+r.PathValue("repo"), 22)
+	})
+	mux.HandleFunc("POST /api/v3/app/installations/{id}/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		id := r.PathValue("id")
+		s.tokens = append(s.tokens, id)
+		if s.removed[id] {
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{
+			"token":      "token-" + id,
+			"expires_at": time.Now().Add(time.Hour).Format(time.RFC3339),
+		})
+	})
+	mux.HandleFunc("GET /api/v3/orgs/{org}/actions/runners", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"total_count": 0, "runners": []any{}})
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *fakeAppServer) lookup(w http.ResponseWriter, target string, id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lookups++
+	if s.rateLimited {
+		w.Header().Set(headerRateLimitRemaining, "0")
+		w.Header().Set(headerRateLimitReset, strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	}
+	if s.removed[target] {
+		http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"id": id})
+}
+
+func (s *fakeAppServer) lookupCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lookups
+}
+
+func (s *fakeAppServer) lastToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.tokens) == 0 {
+		return ""
+	}
+	return s.tokens[len(s.tokens)-1]
+}
+
+func (s *fakeAppServer) remove(target string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removed[target] = true
+}
+
+func testPrivateKey(t *testing.T) []byte {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func TestInstallationLookup(t *testing.T) {
+	ctx := context.Background()
+	privateKey := testPrivateKey(t)
+
+	testCases := []struct {
+		title          string
+		owner          string
+		repo           string
+		installationID int64
+		run            func(t *testing.T, f *defaultFactory, s *fakeAppServer, cred *ClientCredential)
+		lookups        int
+		token          string
+	}{
+		{
+			title: "organization",
+			owner: "org",
+			run: func(t *testing.T, f *defaultFactory, s *fakeAppServer, cred *ClientCredential) {
+				for range 2 {
+					c, err := f.New(cred)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := c.ListRunners(ctx, "org", "", nil); err != nil {
+						t.Fatal(err)
+					}
+				}
+			},
+			lookups: 1,
+			token:   "11",
+		},
+		{
+			title: "repository",
+			owner: "owner",
+			repo:  "repo",
+			run: func(t *testing.T, f *defaultFactory, s *fakeAppServer, cred *ClientCredential) {
+				for range 2 {
+					c, err := f.New(cred)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := c.ListRunners(ctx, "org", "", nil); err != nil {
+						t.Fatal(err)
+					}
+				}
+			},
+			lookups: 1,
+			token:   "22",
+		},
+		{
+			title:          "configured installation",
+			owner:          "org",
+			installationID: 33,
+			run: func(t *testing.T, f *defaultFactory, s *fakeAppServer, cred *ClientCredential) {
+				c, err := f.New(cred)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := c.ListRunners(ctx, "org", "", nil); err != nil {
+					t.Fatal(err)
+				}
+			},
+			token: "33",
+		},
+		{
+			title: "expired",
+			owner: "org",
+			run: func(t *testing.T, f *defaultFactory, s *fakeAppServer, cred *ClientCredential) {
+				if _, err := f.New(cred); err != nil {
+					t.Fatal(err)
+				}
+				f.installations.mu.Lock()
+				for key, e := range f.installations.entries {
+					e.fetchedAt = e.fetchedAt.Add(-installationCacheTTL)
+					f.installations.entries[key] = e
+				}
+				f.installations.mu.Unlock()
+				if _, err := f.New(cred); err != nil {
+					t.Fatal(err)
+				}
+			},
+			lookups: 2,
+		},
+		{
+			title: "not found",
+			owner: "org",
+			run: func(t *testing.T, f *defaultFactory, s *fakeAppServer, cred *ClientCredential) {
+				s.remove("org")
+				for range 2 {
+					if _, err := f.New(cred); err == nil {
+						t.Error("error is not returned")
+					}
+				}
+			},
+			lookups: 2,
+		},
+		{
+			title: "invalidated by the removed installation",
+			owner: "org",
+			run: func(t *testing.T, f *defaultFactory, s *fakeAppServer, cred *ClientCredential) {
+				c, err := f.New(cred)
+				if err != nil {
+					t.Fatal(err)
+				}
+				s.remove("11")
+				if _, err := c.ListRunners(ctx, "org", "", nil); err == nil {
+					t.Error("error is not returned")
+				}
+				if _, err := f.New(cred); err != nil {
+					t.Fatal(err)
+				}
+			},
+			lookups: 2,
+			token:   "11",
+		},
+		{
+			title: "rate limited",
+			owner: "org",
+			run: func(t *testing.T, f *defaultFactory, s *fakeAppServer, cred *ClientCredential) {
+				s.rateLimited = true
+				if _, err := f.New(cred); err != nil {
+					t.Fatal(err)
+				}
+				f.installations.invalidator(strings.TrimSuffix(s.URL, "/"), cred)()
+				_, err := f.New(cred)
+				var rateLimitErr *RateLimitError
+				if !errors.As(err, &rateLimitErr) {
+					t.Errorf("RateLimitError is not returned: %v", err)
+				}
+			},
+			lookups: 1,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			s := newFakeAppServer()
+			defer s.Close()
+
+			f := NewFactory().(*defaultFactory)
+			cred := &ClientCredential{
+				GitHubURL:         s.URL,
+				AppID:             1,
+				AppInstallationID: tt.installationID,
+				PrivateKey:        privateKey,
+				Owner:             tt.owner,
+				Repository:        tt.repo,
+			}
+			tt.run(t, f, s, cred)
+
+			if n := s.lookupCount(); n != tt.lookups {
+				t.Errorf("installation is looked up %d times, want %d", n, tt.lookups)
+			}
+			if tt.token != "" && s.lastToken() != tt.token {
+				t.Errorf("token is issued for installation %s, want %s", s.lastToken(), tt.token)
+			}
+		})
+	}
+}
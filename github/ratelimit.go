@@ -0,0 +1,176 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cybozu-go/meows/metrics"
+)
+
+const (
+	headerRateLimitLimit     = "X-RateLimit-Limit"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRateLimitReset     = "X-RateLimit-Reset"
+	headerRateLimitResource  = "X-RateLimit-Resource"
+	headerRetryAfter         = "Retry-After"
+
+	rateLimitTypePrimary   = "primary"
+	rateLimitTypeSecondary = "secondary"
+
+	// GitHub asks to wait at least one minute when a secondary rate limit is exceeded without Retry-After,
+	// and to wait exponentially longer while it continues to be exceeded.
+	minSecondaryRateLimitBackoff = time.Minute
+	maxSecondaryRateLimitBackoff = 32 * time.Minute
+
+	// Size of the response body to read to check if it is the error of a secondary rate limit.
+	maxRateLimitErrorBodySize = 64 * 1024
+)
+
+// RateLimitError is returned without sending a request while the rate limit of the credential is exceeded.
+type RateLimitError struct {
+	ResetAt time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("GitHub API rate limit is exceeded; requests are suspended until %s", e.ResetAt.UTC().Format(time.RFC3339))
+}
+
+// rateLimiter keeps the rate limit state of a credential.
+// It is shared among the clients using the same credential, because GitHub limits the requests per credential.
+type rateLimiter struct {
+	server     string
+	credential string
+
+	mu               sync.Mutex
+	suspendedUntil   time.Time
+	secondaryBackoff time.Duration
+}
+
+// credentialName returns a name to identify the credential in logs and metrics without revealing the secret.
+func credentialName(cred *ClientCredential) string {
+	if len(cred.PersonalAccessToken) != 0 {
+		sum := sha256.Sum256([]byte(cred.PersonalAccessToken))
+		return "pat-" + hex.EncodeToString(sum[:4])
+	}
+	return appCredentialName(cred.AppID, cred.AppInstallationID)
+}
+
+func appCredentialName(appID, installationID int64) string {
+	return fmt.Sprintf("app-%d-%d", appID, installationID)
+}
+
+// suspended returns the time until when the requests should not be sent, or the zero time.
+func (l *rateLimiter) suspended(now time.Time) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.suspendedUntil) {
+		return l.suspendedUntil
+	}
+	return time.Time{}
+}
+
+// update records the rate limit state reported in the response.
+func (l *rateLimiter) update(now time.Time, res *http.Response) {
+	remaining, hasRemaining := parseIntHeader(res.Header, headerRateLimitRemaining)
+	reset, _ := parseIntHeader(res.Header, headerRateLimitReset)
+	resetAt := time.Unix(int64(reset), 0)
+	if hasRemaining {
+		limit, _ := parseIntHeader(res.Header, headerRateLimitLimit)
+		resource := res.Header.Get(headerRateLimitResource)
+		metrics.UpdateGitHubRateLimitMetrics(l.server, l.credential, resource, limit, remaining, resetAt)
+	}
+
+	retryAfter, hasRetryAfter := parseIntHeader(res.Header, headerRetryAfter)
+	limited := res.StatusCode == http.StatusForbidden || res.StatusCode == http.StatusTooManyRequests
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch {
+	case limited && hasRetryAfter:
+		l.suspend(now.Add(time.Duration(retryAfter) * time.Second))
+		l.secondaryBackoff = nextSecondaryBackoff(l.secondaryBackoff)
+		metrics.IncrementGitHubRateLimitedCount(l.server, l.credential, rateLimitTypeSecondary)
+	case hasRemaining && remaining == 0:
+		// The primary rate limit is exhausted. This may be the response to the last allowed request.
+		l.suspend(resetAt)
+		if limited {
+			metrics.IncrementGitHubRateLimitedCount(l.server, l.credential, rateLimitTypePrimary)
+		}
+	case limited && isSecondaryRateLimitError(res):
+		l.secondaryBackoff = nextSecondaryBackoff(l.secondaryBackoff)
+		l.suspend(now.Add(l.secondaryBackoff))
+		metrics.IncrementGitHubRateLimitedCount(l.server, l.credential, rateLimitTypeSecondary)
+	case res.StatusCode < http.StatusBadRequest:
+		l.secondaryBackoff = 0
+	}
+}
+
+func (l *rateLimiter) suspend(until time.Time) {
+	if until.After(l.suspendedUntil) {
+		l.suspendedUntil = until
+	}
+}
+
+func nextSecondaryBackoff(backoff time.Duration) time.Duration {
+	if backoff == 0 {
+		return minSecondaryRateLimitBackoff
+	}
+	return min(backoff*2, maxSecondaryRateLimitBackoff)
+}
+
+// isSecondaryRateLimitError checks the error message in the response body, and restores the body for the caller.
+func isSecondaryRateLimitError(res *http.Response) bool {
+	if res.Body == nil {
+		return false
+	}
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxRateLimitErrorBodySize))
+	res.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), res.Body), res.Body}
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+func parseIntHeader(header http.Header, key string) (int, bool) {
+	v := header.Get(key)
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// rateLimitTransport suspends the requests while the rate limit is exceeded.
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	limiter *rateLimiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if until := t.limiter.suspended(time.Now()); !until.IsZero() {
+		return nil, &RateLimitError{ResetAt: until}
+	}
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.limiter.update(time.Now(), res)
+	return res, nil
+}
@@ -0,0 +1,161 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterUpdate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	reset := now.Add(30 * time.Minute)
+
+	type response struct {
+		status int
+		header map[string]string
+		body   string
+	}
+	testCases := []struct {
+		title     string
+		responses []response
+		expected  time.Time
+	}{
+		{
+			title: "remaining",
+			responses: []response{
+				{status: http.StatusOK, header: map[string]string{headerRateLimitRemaining: "10", headerRateLimitReset: strconv.FormatInt(reset.Unix(), 10)}},
+			},
+		},
+		{
+			title: "primary rate limit exhausted",
+			responses: []response{
+				{status: http.StatusOK, header: map[string]string{headerRateLimitRemaining: "0", headerRateLimitReset: strconv.FormatInt(reset.Unix(), 10)}},
+			},
+			expected: reset,
+		},
+		{
+			title: "primary rate limit exceeded",
+			responses: []response{
+				{status: http.StatusForbidden, header: map[string]string{headerRateLimitRemaining: "0", headerRateLimitReset: strconv.FormatInt(reset.Unix(), 10)}},
+			},
+			expected: reset,
+		},
+		{
+			title: "secondary rate limit with retry-after",
+			responses: []response{
+				{status: http.StatusTooManyRequests, header: map[string]string{headerRetryAfter: "30"}},
+			},
+			expected: now.Add(30 * time.Second),
+		},
+		{
+			title: "secondary rate limit without retry-after",
+			responses: []response{
+				{status: http.StatusForbidden, body: `{"message": "You have exceeded a secondary rate limit."}`},
+			},
+			expected: now.Add(minSecondaryRateLimitBackoff),
+		},
+		{
+			title: "continued secondary rate limit",
+			responses: []response{
+				{status: http.StatusForbidden, body: `{"message": "You have exceeded a secondary rate limit."}`},
+				{status: http.StatusForbidden, body: `{"message": "You have exceeded a secondary rate limit."}`},
+			},
+			expected: now.Add(2 * minSecondaryRateLimitBackoff),
+		},
+		{
+			title: "other forbidden error",
+			responses: []response{
+				{status: http.StatusForbidden, body: `{"message": "Resource not accessible by integration"}`},
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			l := &rateLimiter{server: "https://github.com", credential: "test"}
+			for _, r := range tt.responses {
+				res := &http.Response{
+					StatusCode: r.status,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader(r.body)),
+				}
+				for k, v := range r.header {
+					res.Header.Set(k, v)
+				}
+				l.update(now, res)
+
+				// The body is restored for the caller.
+				body, err := io.ReadAll(res.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(body) != r.body {
+					t.Errorf("body is not restored: %q", body)
+				}
+			}
+
+			actual := l.suspended(now)
+			if !actual.Equal(tt.expected) {
+				t.Errorf("suspended until %s, want %s", actual, tt.expected)
+			}
+			if !l.suspended(actual).IsZero() {
+				t.Errorf("still suspended at %s", actual)
+			}
+		})
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set(headerRateLimitLimit, "5000")
+		w.Header().Set(headerRateLimitRemaining, "0")
+		w.Header().Set(headerRateLimitReset, strconv.FormatInt(reset.Unix(), 10))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"total_count": 0, "runners": []}`))
+	}))
+	defer server.Close()
+
+	limiter := &rateLimiter{server: server.URL, credential: "test"}
+	c, err := newClientFromPAT(server.URL, "pat", limiter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err := c.ListRunners(ctx, "owner", "repo", nil); err != nil {
+		t.Fatalf("the last allowed request failed: %v", err)
+	}
+
+	// go-github tracks the rate limit only in each client, while the limiter
+	// is shared among the clients created for the same credential.
+	c, err = newClientFromPAT(server.URL, "pat", limiter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.ListRunners(ctx, "owner", "repo", nil)
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("RateLimitError is not returned: %v", err)
+	}
+	if rateLimitErr.ResetAt.Unix() != reset.Unix() {
+		t.Errorf("reset at %s, want %s", rateLimitErr.ResetAt, reset)
+	}
+
+	_, err = c.CreateRegistrationToken(ctx, "owner", "repo")
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("RateLimitError is not returned: %v", err)
+	}
+
+	if n := requests.Load(); n != 1 {
+		t.Errorf("%d requests are sent while the rate limit is exceeded", n-1)
+	}
+}
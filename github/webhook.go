@@ -0,0 +1,69 @@
+package github
+
+import (
+	"net/http"
+
+	"github.com/google/go-github/v80/github"
+)
+
+// Actions of `workflow_job` webhook events.
+const (
+	WorkflowJobActionQueued     = "queued"
+	WorkflowJobActionWaiting    = "waiting"
+	WorkflowJobActionInProgress = "in_progress"
+	WorkflowJobActionCompleted  = "completed"
+)
+
+// WorkflowJob represents a workflow job notified by a `workflow_job` webhook event.
+type WorkflowJob struct {
+	ID     int64
+	Action string
+	Labels []string
+}
+
+// NewWebhookHandler creates an http.Handler which receives webhook events from GitHub.
+// The payload of each request is verified with the HMAC signature using the given secret.
+// The function f is called for every `workflow_job` event. Other events are ignored.
+func NewWebhookHandler(secret []byte, f func(*WorkflowJob)) http.Handler {
+	return &webhookHandler{
+		secret: secret,
+		f:      f,
+	}
+}
+
+type webhookHandler struct {
+	secret []byte
+	f      func(*WorkflowJob)
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, err := github.ValidatePayload(req, h.secret)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	eventType := github.WebHookType(req)
+	if eventType != "workflow_job" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	event, err := github.ParseWebHook(eventType, payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ev := event.(*github.WorkflowJobEvent)
+	h.f(&WorkflowJob{
+		ID:     ev.GetWorkflowJob().GetID(),
+		Action: ev.GetAction(),
+		Labels: ev.GetWorkflowJob().Labels,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,168 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testWebhookSecret = "webhook-secret"
+
+func signPayload(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func workflowJobPayload(t *testing.T, action string, id int64, labels ...string) string {
+	t.Helper()
+	payload, err := json.Marshal(map[string]any{
+		"action": action,
+		"workflow_job": map[string]any{
+			"id":     id,
+			"labels": labels,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(payload)
+}
+
+func webhookRequest(event, payload, signature string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", event)
+	if signature != "" {
+		req.Header.Set("X-Hub-Signature-256", signature)
+	}
+	return req
+}
+
+func TestWebhookHandler(t *testing.T) {
+	queued := workflowJobPayload(t, WorkflowJobActionQueued, 1, "self-hosted", "ns/rp")
+	completed := workflowJobPayload(t, WorkflowJobActionCompleted, 1, "self-hosted", "ns/rp")
+	push := `{"ref": "refs/heads/main"}`
+
+	testCases := []struct {
+		title    string
+		req      *http.Request
+		status   int
+		expected []*WorkflowJob
+	}{
+		{
+			title:  "invalid method",
+			req:    httptest.NewRequest(http.MethodGet, "/webhook", nil),
+			status: http.StatusMethodNotAllowed,
+		},
+		{
+			title:  "missing signature",
+			req:    webhookRequest("workflow_job", queued, ""),
+			status: http.StatusUnauthorized,
+		},
+		{
+			title:  "signature with another secret",
+			req:    webhookRequest("workflow_job", queued, signPayload("wrong-secret", queued)),
+			status: http.StatusUnauthorized,
+		},
+		{
+			title:  "signature of another payload",
+			req:    webhookRequest("workflow_job", queued, signPayload(testWebhookSecret, completed)),
+			status: http.StatusUnauthorized,
+		},
+		{
+			title:  "malformed signature",
+			req:    webhookRequest("workflow_job", queued, "sha256=invalid"),
+			status: http.StatusUnauthorized,
+		},
+		{
+			title:  "ignored event",
+			req:    webhookRequest("push", push, signPayload(testWebhookSecret, push)),
+			status: http.StatusNoContent,
+		},
+		{
+			title:  "malformed payload",
+			req:    webhookRequest("workflow_job", `{"action": 1}`, signPayload(testWebhookSecret, `{"action": 1}`)),
+			status: http.StatusBadRequest,
+		},
+		{
+			title:  "queued job",
+			req:    webhookRequest("workflow_job", queued, signPayload(testWebhookSecret, queued)),
+			status: http.StatusNoContent,
+			expected: []*WorkflowJob{
+				{ID: 1, Action: WorkflowJobActionQueued, Labels: []string{"self-hosted", "ns/rp"}},
+			},
+		},
+		{
+			title:  "completed job",
+			req:    webhookRequest("workflow_job", completed, signPayload(testWebhookSecret, completed)),
+			status: http.StatusNoContent,
+			expected: []*WorkflowJob{
+				{ID: 1, Action: WorkflowJobActionCompleted, Labels: []string{"self-hosted", "ns/rp"}},
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			var jobs []*WorkflowJob
+			h := NewWebhookHandler([]byte(testWebhookSecret), func(job *WorkflowJob) {
+				jobs = append(jobs, job)
+			})
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, tt.req)
+			if w.Code != tt.status {
+				t.Errorf("status %d, want %d", w.Code, tt.status)
+			}
+			if len(jobs) != len(tt.expected) {
+				t.Fatalf("%d jobs are notified, want %d", len(jobs), len(tt.expected))
+			}
+			for i, job := range jobs {
+				e := tt.expected[i]
+				if job.ID != e.ID || job.Action != e.Action || !slices.Equal(job.Labels, e.Labels) {
+					t.Errorf("notified %+v, want %+v", job, e)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookHandlerPassesEvents(t *testing.T) {
+	// The handler passes the parsed events to the callback in the order of delivery.
+	// How the events change the queued jobs is tested with the queue of the controller.
+	var jobs []*WorkflowJob
+	h := NewWebhookHandler([]byte(testWebhookSecret), func(job *WorkflowJob) {
+		jobs = append(jobs, job)
+	})
+
+	expected := []*WorkflowJob{
+		{ID: 1, Action: WorkflowJobActionQueued, Labels: []string{"self-hosted", "ns/rp"}},
+		{ID: 1, Action: WorkflowJobActionInProgress, Labels: []string{"self-hosted", "ns/rp"}},
+		{ID: 2, Action: WorkflowJobActionQueued, Labels: []string{"self-hosted", "ns/rp2"}},
+		{ID: 1, Action: WorkflowJobActionCompleted, Labels: []string{"self-hosted", "ns/rp"}},
+	}
+	for _, e := range expected {
+		payload := workflowJobPayload(t, e.Action, e.ID, e.Labels...)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, webhookRequest("workflow_job", payload, signPayload(testWebhookSecret, payload)))
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("status %d, want %d", w.Code, http.StatusNoContent)
+		}
+	}
+
+	if len(jobs) != len(expected) {
+		t.Fatalf("%d jobs are passed, want %d", len(jobs), len(expected))
+	}
+	for i, job := range jobs {
+		e := expected[i]
+		if job.ID != e.ID || job.Action != e.Action || !slices.Equal(job.Labels, e.Labels) {
+			t.Errorf("passed %+v, want %+v", job, e)
+		}
+	}
+}
@@ -30,10 +30,17 @@ func testBootstrap() {
 		By("applying manifests")
 		stdout, stderr, err := kustomizeBuild("../config/crd")
 		Expect(err).ShouldNot(HaveOccurred(), "stdout: %s, stderr: %s, err: %v", stdout, stderr, err)
-		kubectlSafeWithInput(stdout, "apply", "-f", "-")
+		// The CRD is too large to be stored in the annotation of the client-side apply.
+		kubectlSafeWithInput(stdout, "apply", "--server-side", "-f", "-")
 	})
 
 	It("should deploy controller successfully", func() {
+		By("creating secret for runner api key")
+		kubectlSafe("create", "secret", "generic", "meows-runner-api-key",
+			"-n", controllerNS,
+			"--from-literal=key="+runnerAPIKey,
+		)
+
 		By("applying manifests")
 		stdout, stderr, err := kustomizeBuild("./manifests/controller")
 		Expect(err).ShouldNot(HaveOccurred(), "stdout: %s, stderr: %s, err: %v", stdout, stderr, err)
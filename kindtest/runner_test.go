@@ -52,13 +52,14 @@ func testRunner() {
 
 		By("checking status")
 		Expect(status).To(PointTo(MatchAllFields(Fields{
-			"State":        Equal("debugging"),
-			"Result":       Equal("success"),
-			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
-			"DeletionTime": BeNil(),
-			"Extend":       PointTo(BeFalse()),
-			"JobInfo":      Not(BeNil()),
-			"SlackChannel": Equal("#test2"),
+			"State":             Equal("debugging"),
+			"Result":            Equal("success"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
+			"DeletionTime":      BeNil(),
+			"Extend":            PointTo(BeFalse()),
+			"JobInfo":           Not(BeNil()),
+			"SlackChannel":      Equal("#test2"),
+			"JITConfigRequired": BeFalse(),
 		})))
 
 		By("confirming the pod terminating")
@@ -83,13 +84,14 @@ func testRunner() {
 
 		By("checking status")
 		Expect(status).To(PointTo(MatchAllFields(Fields{
-			"State":        Equal("debugging"),
-			"Result":       Equal("cancelled"),
-			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
-			"DeletionTime": BeNil(),
-			"Extend":       PointTo(BeFalse()),
-			"JobInfo":      Not(BeNil()),
-			"SlackChannel": BeEmpty(),
+			"State":             Equal("debugging"),
+			"Result":            Equal("cancelled"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
+			"DeletionTime":      BeNil(),
+			"Extend":            PointTo(BeFalse()),
+			"JobInfo":           Not(BeNil()),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
 		})))
 
 		By("confirming the pod terminating")
@@ -116,13 +118,14 @@ func testRunner() {
 		Eventually(func(g Gomega) {
 			_, status = waitJobCompletion(repoRunner1NS, repoRunnerPool1Name)
 			g.Expect(status).To(PointTo(MatchAllFields(Fields{
-				"State":        Equal("debugging"),
-				"Result":       Equal("failure"),
-				"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
-				"DeletionTime": Not(BeNil()),
-				"Extend":       PointTo(BeTrue()),
-				"JobInfo":      Not(BeNil()),
-				"SlackChannel": BeEmpty(),
+				"State":             Equal("debugging"),
+				"Result":            Equal("failure"),
+				"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
+				"DeletionTime":      Not(BeNil()),
+				"Extend":            PointTo(BeTrue()),
+				"JobInfo":           Not(BeNil()),
+				"SlackChannel":      BeEmpty(),
+				"JITConfigRequired": BeFalse(),
 			})))
 		}).Should(Succeed())
 
@@ -162,13 +165,14 @@ func testRunner() {
 		Eventually(func(g Gomega) {
 			_, status = waitJobCompletion(repoRunner2NS, repoRunnerPool2Name)
 			g.Expect(status).To(PointTo(MatchAllFields(Fields{
-				"State":        Equal("debugging"),
-				"Result":       Equal("failure"),
-				"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
-				"DeletionTime": Not(BeNil()),
-				"Extend":       PointTo(BeTrue()),
-				"JobInfo":      Not(BeNil()),
-				"SlackChannel": BeEmpty(),
+				"State":             Equal("debugging"),
+				"Result":            Equal("failure"),
+				"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
+				"DeletionTime":      Not(BeNil()),
+				"Extend":            PointTo(BeTrue()),
+				"JobInfo":           Not(BeNil()),
+				"SlackChannel":      BeEmpty(),
+				"JITConfigRequired": BeFalse(),
 			})))
 		}).Should(Succeed())
 
@@ -181,13 +185,14 @@ func testRunner() {
 
 		By("checking status")
 		Expect(status).To(PointTo(MatchAllFields(Fields{
-			"State":        Equal("debugging"),
-			"Result":       Equal("failure"),
-			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
-			"DeletionTime": PointTo(BeTemporally("==", extendTo)),
-			"Extend":       PointTo(BeTrue()),
-			"JobInfo":      Not(BeNil()),
-			"SlackChannel": BeEmpty(),
+			"State":             Equal("debugging"),
+			"Result":            Equal("failure"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
+			"DeletionTime":      PointTo(BeTemporally("==", extendTo)),
+			"Extend":            PointTo(BeTrue()),
+			"JobInfo":           Not(BeNil()),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
 		})))
 
 		By("confirming the pod terminating")
@@ -213,13 +218,14 @@ func testRunner() {
 
 		By("checking status")
 		Expect(status).To(PointTo(MatchAllFields(Fields{
-			"State":        Equal("debugging"),
-			"Result":       Equal("success"),
-			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
-			"DeletionTime": BeNil(),
-			"Extend":       PointTo(BeFalse()),
-			"JobInfo":      Not(BeNil()),
-			"SlackChannel": BeEmpty(),
+			"State":             Equal("debugging"),
+			"Result":            Equal("success"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
+			"DeletionTime":      BeNil(),
+			"Extend":            PointTo(BeFalse()),
+			"JobInfo":           Not(BeNil()),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
 		})))
 
 		By("confirming the pod terminating")
@@ -244,13 +250,14 @@ func testRunner() {
 
 		By("checking status")
 		Expect(status).To(PointTo(MatchAllFields(Fields{
-			"State":        Equal("debugging"),
-			"Result":       Equal("success"),
-			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
-			"DeletionTime": BeNil(),
-			"Extend":       PointTo(BeFalse()),
-			"JobInfo":      Not(BeNil()),
-			"SlackChannel": BeEmpty(),
+			"State":             Equal("debugging"),
+			"Result":            Equal("success"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
+			"DeletionTime":      BeNil(),
+			"Extend":            PointTo(BeFalse()),
+			"JobInfo":           Not(BeNil()),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
 		})))
 
 		By("confirming the pod terminating")
@@ -275,13 +282,14 @@ func testRunner() {
 
 		By("checking status")
 		Expect(status).To(PointTo(MatchAllFields(Fields{
-			"State":        Equal("debugging"),
-			"Result":       Equal("success"),
-			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
-			"DeletionTime": BeNil(),
-			"Extend":       PointTo(BeFalse()),
-			"JobInfo":      Not(BeNil()),
-			"SlackChannel": Equal("#test2"),
+			"State":             Equal("debugging"),
+			"Result":            Equal("success"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
+			"DeletionTime":      BeNil(),
+			"Extend":            PointTo(BeFalse()),
+			"JobInfo":           Not(BeNil()),
+			"SlackChannel":      Equal("#test2"),
+			"JITConfigRequired": BeFalse(),
 		})))
 
 		By("confirming the pod terminating")
@@ -306,13 +314,14 @@ func testRunner() {
 
 		By("checking status")
 		Expect(status).To(PointTo(MatchAllFields(Fields{
-			"State":        Equal("debugging"),
-			"Result":       Equal("success"),
-			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
-			"DeletionTime": BeNil(),
-			"Extend":       PointTo(BeFalse()),
-			"JobInfo":      Not(BeNil()),
-			"SlackChannel": Equal("#test1"),
+			"State":             Equal("debugging"),
+			"Result":            Equal("success"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 3*time.Second)),
+			"DeletionTime":      BeNil(),
+			"Extend":            PointTo(BeFalse()),
+			"JobInfo":           Not(BeNil()),
+			"SlackChannel":      Equal("#test1"),
+			"JITConfigRequired": BeFalse(),
 		})))
 
 		By("confirming the pod terminating")
@@ -32,6 +32,7 @@ var (
 	repoRunnerPool1Replicas = 3
 	repoRunnerPool2Replicas = 1
 	orgRunnerPool1Replicas  = 1
+	runnerAPIKey            = testID + "-runner-api-key"
 	githubClient            *github.Client
 )
 
@@ -2,18 +2,24 @@ package metrics
 
 import (
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 // Controller related metrics
 var (
-	RunnerPoolSecretRetryCount *prometheus.CounterVec
-	runnerPoolReplicas         *prometheus.GaugeVec
-	runnerOnlineVec            *prometheus.GaugeVec
-	runnerBusyVec              *prometheus.GaugeVec
-	runnerLabelSet             map[string]map[string]struct{} // runnerpool -> runner -> struct{}
-	runnerLabelSetMutex        sync.Mutex
+	RunnerPoolSecretRetryCount  *prometheus.CounterVec
+	runnerPoolReplicas          *prometheus.GaugeVec
+	runnerOnlineVec             *prometheus.GaugeVec
+	runnerBusyVec               *prometheus.GaugeVec
+	runnerLabelSet              map[string]map[string]struct{} // runnerpool -> runner -> struct{}
+	runnerLabelSetMutex         sync.Mutex
+	githubRateLimitVec          *prometheus.GaugeVec
+	githubRateLimitRemainingVec *prometheus.GaugeVec
+	githubRateLimitResetVec     *prometheus.GaugeVec
+	githubRateLimitedCountVec   *prometheus.CounterVec
+	githubCacheCountVec         *prometheus.CounterVec
 )
 
 func InitControllerMetrics(registry prometheus.Registerer) {
@@ -59,11 +65,66 @@ func InitControllerMetrics(registry prometheus.Registerer) {
 
 	runnerLabelSet = map[string]map[string]struct{}{}
 
+	githubRateLimitVec = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: githubSubsystem,
+			Name:      "ratelimit_limit",
+			Help:      "The maximum number of GitHub API requests per hour",
+		},
+		[]string{"server", "credential", "resource"},
+	)
+
+	githubRateLimitRemainingVec = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: githubSubsystem,
+			Name:      "ratelimit_remaining",
+			Help:      "The number of GitHub API requests remaining in the current rate limit window",
+		},
+		[]string{"server", "credential", "resource"},
+	)
+
+	githubRateLimitResetVec = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: githubSubsystem,
+			Name:      "ratelimit_reset_timestamp_seconds",
+			Help:      "The time when the current rate limit window of GitHub API resets, in Unix epoch seconds",
+		},
+		[]string{"server", "credential", "resource"},
+	)
+
+	githubRateLimitedCountVec = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: githubSubsystem,
+			Name:      "ratelimited_total",
+			Help:      "The number of GitHub API responses which reported that the rate limit was exceeded",
+		},
+		[]string{"server", "credential", "type"},
+	)
+
+	githubCacheCountVec = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: githubSubsystem,
+			Name:      "list_cache_total",
+			Help:      "The number of GitHub API listings served by the cache (hit) or requested to GitHub (miss)",
+		},
+		[]string{"result"},
+	)
+
 	registry.MustRegister(
 		RunnerPoolSecretRetryCount,
 		runnerPoolReplicas,
 		runnerOnlineVec,
 		runnerBusyVec,
+		githubRateLimitVec,
+		githubRateLimitRemainingVec,
+		githubRateLimitResetVec,
+		githubRateLimitedCountVec,
+		githubCacheCountVec,
 	)
 }
 
@@ -129,3 +190,33 @@ func DeleteAllRunnerMetrics(runnerpool string) {
 	}
 	delete(runnerLabelSet, runnerpool)
 }
+
+// The GitHub client is also used by the meows command, which does not initialize the metrics.
+// So the following functions do nothing if the metrics are not initialized.
+
+func UpdateGitHubRateLimitMetrics(server, credential, resource string, limit, remaining int, reset time.Time) {
+	if githubRateLimitVec == nil {
+		return
+	}
+	githubRateLimitVec.WithLabelValues(server, credential, resource).Set(float64(limit))
+	githubRateLimitRemainingVec.WithLabelValues(server, credential, resource).Set(float64(remaining))
+	githubRateLimitResetVec.WithLabelValues(server, credential, resource).Set(float64(reset.Unix()))
+}
+
+func IncrementGitHubRateLimitedCount(server, credential, limitType string) {
+	if githubRateLimitedCountVec == nil {
+		return
+	}
+	githubRateLimitedCountVec.WithLabelValues(server, credential, limitType).Inc()
+}
+
+func IncrementGitHubCacheCount(hit bool) {
+	if githubCacheCountVec == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	githubCacheCountVec.WithLabelValues(result).Inc()
+}
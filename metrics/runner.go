@@ -10,6 +10,7 @@ const (
 	controllerSubsystem = "controller"
 	runnerPoolSubsystem = "runnerpool"
 	runnerSubsystem     = "runner"
+	githubSubsystem     = "github"
 )
 
 var allRunnerPodState = []string{
@@ -17,12 +18,15 @@ var allRunnerPodState = []string{
 	constants.RunnerPodStateRunning,
 	constants.RunnerPodStateDebugging,
 	constants.RunnerPodStateStale,
+	constants.RunnerPodStateTerminating,
+	constants.RunnerPodStateFailed,
 }
 
 // Runner pod related metrics
 var (
 	podStateVec               *prometheus.GaugeVec
 	listenerExitStateCountVec *prometheus.CounterVec
+	jobInfoVec                *prometheus.GaugeVec
 )
 
 func InitRunnerPodMetrics(registry prometheus.Registerer, name string) {
@@ -51,9 +55,21 @@ func InitRunnerPodMetrics(registry prometheus.Registerer, name string) {
 		[]string{"state"},
 	)
 
+	jobInfoVec = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace:   metricsNamespace,
+			Subsystem:   runnerSubsystem,
+			Name:        "job_info",
+			Help:        "1 for the job run by the runner pod, which is described by the labels",
+			ConstLabels: labels,
+		},
+		[]string{"repository", "event", "result"},
+	)
+
 	registry.MustRegister(
 		podStateVec,
 		listenerExitStateCountVec,
+		jobInfoVec,
 	)
 }
 
@@ -70,3 +86,10 @@ func UpdateRunnerPodState(curState string) {
 func IncrementListenerExitState(state string) {
 	listenerExitStateCountVec.WithLabelValues(string(state)).Inc()
 }
+
+// SetRunnerJobInfo exposes the job run by the runner pod.
+// The values specific to each run, such as the SHA and the run ID, are not exposed as labels
+// because every job would create a new series. They are available in the status of the runner pod.
+func SetRunnerJobInfo(repository, event, result string) {
+	jobInfoVec.WithLabelValues(repository, event, result).Set(1)
+}
@@ -0,0 +1,81 @@
+package runner
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// minAPIKeyLength is the minimum length of the key to derive the API tokens.
+const minAPIKeyLength = 32
+
+// APIToken returns the token to authenticate the requests to the runner pods of the RunnerPool.
+// The token is derived from the key shared by the controller and the slack agent,
+// so they can authenticate to any runner pods without reading the Secrets of the tokens,
+// and the token leaked from a runner pod cannot be used for the other RunnerPools.
+func APIToken(key []byte, namespace, runnerPool string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(namespace + "/" + runnerPool))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ReadAPIKey reads the key to derive the API tokens from the file.
+func ReadAPIKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read api key file %q; %w", path, err)
+	}
+	key := bytes.TrimSpace(data)
+	if len(key) < minAPIKeyLength {
+		return nil, fmt.Errorf("api key in %q should be at least %d bytes", path, minAPIKeyLength)
+	}
+	return key, nil
+}
+
+// authenticate rejects the requests without the API token of the RunnerPool.
+// The token file is read for each request to follow the rotation of the Secret.
+// If the token is not mounted, i.e. the controller is not configured with the key, the authentication is disabled.
+func (r *Runner) authenticate(h http.HandlerFunc) http.Handler {
+	return r.authenticateWith(h, false)
+}
+
+// authenticateStrictly is like authenticate, but rejects all the requests if the token is not mounted.
+// It protects the endpoints which must not accept the requests from anyone other than the controller.
+func (r *Runner) authenticateStrictly(h http.HandlerFunc) http.Handler {
+	return r.authenticateWith(h, true)
+}
+
+func (r *Runner) authenticateWith(h http.HandlerFunc, strict bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		data, err := os.ReadFile(r.apiTokenPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			if strict {
+				http.Error(w, "api token is not configured", http.StatusForbidden)
+				return
+			}
+			h(w, req)
+			return
+		}
+		token := bytes.TrimSpace(data)
+		if err != nil || len(token) == 0 {
+			http.Error(w, "failed to read api token", http.StatusInternalServerError)
+			return
+		}
+
+		presented, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(presented), token) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		h(w, req)
+	})
+}
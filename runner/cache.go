@@ -0,0 +1,209 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	constants "github.com/cybozu-go/meows"
+)
+
+// cacheStampName is the file at the root of a shared cache, which is touched when a job starts.
+const cacheStampName = ".meows-last-used"
+
+// DefaultCacheJobWindow is the default duration during which the cache entries used by a job are protected from the eviction.
+const DefaultCacheJobWindow = 6 * time.Hour
+
+// RepositoryCache is a cache used by the jobs.
+// If Shared is false, the cache is kept for each repository.
+// The cache volume is mounted at `<RepositoryCachesDirPath>/<name>`,
+// and the directory of the repository in the volume is linked to the mount path.
+// If Shared is true, the cache volume is mounted at the mount path as it is, and it is only stamped for the eviction.
+type RepositoryCache struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mount_path"`
+	Shared    bool   `json:"shared,omitempty"`
+}
+
+// LinkRepositoryCaches links the directories of the repository in the caches to their mount paths,
+// and stamps the shared caches as used by the job.
+// The caches are read from the environment variable MEOWS_REPOSITORY_CACHES.
+func LinkRepositoryCaches(repository string) error {
+	raw := os.Getenv(constants.RepositoryCachesEnvName)
+	if raw == "" {
+		return nil
+	}
+	var caches []RepositoryCache
+	if err := json.Unmarshal([]byte(raw), &caches); err != nil {
+		return fmt.Errorf("failed to unmarshal %s; %w", constants.RepositoryCachesEnvName, err)
+	}
+	return linkRepositoryCaches(constants.RepositoryCachesDirPath, caches, repository)
+}
+
+func linkRepositoryCaches(root string, caches []RepositoryCache, repository string) error {
+	split := strings.Split(repository, "/")
+	if len(split) != 2 || !isValidPathElement(split[0]) || !isValidPathElement(split[1]) {
+		return fmt.Errorf("invalid repository name %q", repository)
+	}
+
+	now := time.Now()
+	for _, c := range caches {
+		if c.Shared {
+			// The entries used by the job are unknown, so the whole cache is stamped.
+			stamp := filepath.Join(c.MountPath, cacheStampName)
+			if err := os.WriteFile(stamp, nil, 0644); err != nil {
+				return fmt.Errorf("failed to stamp cache %s; %w", c.Name, err)
+			}
+			if err := os.Chtimes(stamp, now, now); err != nil {
+				return fmt.Errorf("failed to stamp cache %s; %w", c.Name, err)
+			}
+			continue
+		}
+
+		dir := filepath.Join(root, c.Name, split[0], split[1])
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create cache directory %s; %w", dir, err)
+		}
+		// Mark the directory as recently used for the eviction.
+		if err := os.Chtimes(dir, now, now); err != nil {
+			return fmt.Errorf("failed to touch cache directory %s; %w", dir, err)
+		}
+
+		// Replace the empty directory created by the image or the link created for the previous job.
+		if err := os.Remove(c.MountPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to replace %s with cache %s; %w", c.MountPath, c.Name, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(c.MountPath), 0755); err != nil {
+			return err
+		}
+		if err := os.Symlink(dir, c.MountPath); err != nil {
+			return fmt.Errorf("failed to link cache %s; %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+func isValidPathElement(s string) bool {
+	return s != "" && s != "." && s != ".." && !strings.ContainsAny(s, `/\`)
+}
+
+type cacheEntry struct {
+	path     string
+	size     int64
+	lastUsed time.Time
+}
+
+// EvictCache removes the least recently used entries in the cache directory until the total size is within the limit.
+// The entries are the files and the directories at the depth, e.g. `<owner>/<repo>` at the depth 2 for the caches kept for each repository.
+//
+// The entries of the caches kept for each repository are stamped when they are linked for a job, so they are ordered by the stamps.
+// The entries of a shared cache are ordered by the latest modification time in them, because the entries used by the jobs are unknown.
+// The entries used within the window are not removed because running jobs may hold them,
+// and a shared cache is not evicted at all while it is stamped within the window.
+// It returns the paths of the removed entries.
+func EvictCache(dir string, depth int, limit int64, window time.Duration) ([]string, error) {
+	if depth < 1 {
+		return nil, fmt.Errorf("invalid depth %d", depth)
+	}
+
+	inUse := time.Now().Add(-window)
+	info, err := os.Stat(filepath.Join(dir, cacheStampName))
+	if err == nil && info.ModTime().After(inUse) {
+		return nil, nil
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, strings.Repeat("*"+string(filepath.Separator), depth-1)+"*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []cacheEntry
+	var total int64
+	for _, p := range paths {
+		if filepath.Base(p) == cacheStampName {
+			continue
+		}
+		e, err := scanCacheEntry(p, depth > 1)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+		total += e.size
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].lastUsed.Before(entries[j].lastUsed)
+	})
+
+	var removed []string
+	for _, e := range entries {
+		if total <= limit {
+			break
+		}
+		if e.lastUsed.After(inUse) {
+			continue
+		}
+		if err := os.RemoveAll(e.path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s; %w", e.path, err)
+		}
+		removed = append(removed, e.path)
+		total -= e.size
+
+		// Remove the parent directories left empty, e.g. `<owner>`.
+		for parent := filepath.Dir(e.path); parent != filepath.Clean(dir); parent = filepath.Dir(parent) {
+			if os.Remove(parent) != nil {
+				break
+			}
+		}
+	}
+	return removed, nil
+}
+
+// scanCacheEntry sums the size of the files in the entry, and finds its last used time.
+// If stamped is true, the modification time of the entry itself is the stamp of the last use.
+// Otherwise, the latest modification time in the entry is used instead.
+func scanCacheEntry(p string, stamped bool) (cacheEntry, error) {
+	e := cacheEntry{path: p}
+	if stamped {
+		info, err := os.Stat(p)
+		if err != nil {
+			return e, fmt.Errorf("failed to scan %s; %w", p, err)
+		}
+		e.lastUsed = info.ModTime()
+	}
+	err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			// The file is removed by a running job.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			e.size += info.Size()
+		}
+		if !stamped && info.ModTime().After(e.lastUsed) {
+			e.lastUsed = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return e, fmt.Errorf("failed to scan %s; %w", p, err)
+	}
+	return e, nil
+}
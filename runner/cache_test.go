@@ -0,0 +1,150 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cache", func() {
+	It("should link the caches of the repository", func() {
+		dir := GinkgoT().TempDir()
+		root := filepath.Join(dir, "caches")
+		goMod := filepath.Join(dir, "home", "go", "pkg", "mod")
+		npm := filepath.Join(dir, "home", ".npm")
+		Expect(os.MkdirAll(npm, 0755)).To(Succeed())
+		caches := []RepositoryCache{
+			{Name: "go-mod", MountPath: goMod},
+			{Name: "npm", MountPath: npm},
+		}
+
+		By("linking the caches")
+		Expect(linkRepositoryCaches(root, caches, "owner/repo1")).To(Succeed())
+		Expect(os.Readlink(goMod)).To(Equal(filepath.Join(root, "go-mod", "owner", "repo1")))
+		Expect(os.Readlink(npm)).To(Equal(filepath.Join(root, "npm", "owner", "repo1")))
+		Expect(os.WriteFile(filepath.Join(goMod, "cached"), []byte("cached"), 0644)).To(Succeed())
+
+		By("linking the caches of another repository")
+		Expect(linkRepositoryCaches(root, caches, "owner/repo2")).To(Succeed())
+		Expect(os.Readlink(goMod)).To(Equal(filepath.Join(root, "go-mod", "owner", "repo2")))
+		Expect(filepath.Join(goMod, "cached")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(root, "go-mod", "owner", "repo1", "cached")).To(BeAnExistingFile())
+
+		By("linking the caches of an invalid repository")
+		Expect(linkRepositoryCaches(root, caches, "owner/..")).NotTo(Succeed())
+		Expect(linkRepositoryCaches(root, caches, "repo")).NotTo(Succeed())
+
+		By("linking the cache to a non-empty directory")
+		Expect(os.Remove(npm)).To(Succeed())
+		Expect(os.MkdirAll(npm, 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(npm, "file"), []byte("file"), 0644)).To(Succeed())
+		Expect(linkRepositoryCaches(root, caches, "owner/repo1")).NotTo(Succeed())
+	})
+
+	It("should stamp the shared caches", func() {
+		dir := GinkgoT().TempDir()
+		npm := filepath.Join(dir, "home", ".npm")
+		Expect(os.MkdirAll(npm, 0755)).To(Succeed())
+		stamp := filepath.Join(npm, cacheStampName)
+		old := time.Now().Add(-time.Hour)
+		Expect(os.WriteFile(stamp, nil, 0644)).To(Succeed())
+		Expect(os.Chtimes(stamp, old, old)).To(Succeed())
+
+		Expect(linkRepositoryCaches(filepath.Join(dir, "caches"), []RepositoryCache{{Name: "npm", MountPath: npm, Shared: true}}, "owner/repo1")).To(Succeed())
+		info, err := os.Lstat(npm)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.IsDir()).To(BeTrue())
+		info, err = os.Stat(stamp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.ModTime()).To(BeTemporally("~", time.Now(), time.Minute))
+	})
+
+	It("should evict the least recently used entries", func() {
+		dir := GinkgoT().TempDir()
+		now := time.Now()
+		for i, repo := range []string{"owner1/repo1", "owner1/repo2", "owner2/repo1"} {
+			p := filepath.Join(dir, repo)
+			Expect(os.MkdirAll(p, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(p, "data"), make([]byte, 100), 0644)).To(Succeed())
+			// owner2/repo1 is the oldest, and owner1/repo2 is the newest.
+			t := now.Add(-time.Duration([]int{2, 1, 3}[i]) * time.Hour)
+			Expect(os.Chtimes(filepath.Join(p, "data"), t, t)).To(Succeed())
+			Expect(os.Chtimes(p, t, t)).To(Succeed())
+		}
+
+		By("evicting nothing within the limit")
+		removed, err := EvictCache(dir, 2, 300, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(BeEmpty())
+
+		By("evicting the oldest entries")
+		removed, err = EvictCache(dir, 2, 150, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(Equal([]string{
+			filepath.Join(dir, "owner2", "repo1"),
+			filepath.Join(dir, "owner1", "repo1"),
+		}))
+		Expect(filepath.Join(dir, "owner1", "repo2", "data")).To(BeAnExistingFile())
+		Expect(filepath.Join(dir, "owner2")).NotTo(BeADirectory())
+		Expect(filepath.Join(dir, "owner1")).To(BeADirectory())
+
+		By("evicting the top-level entries")
+		// The directory was modified by the previous eviction.
+		old := now.Add(-time.Hour)
+		Expect(os.Chtimes(filepath.Join(dir, "owner1"), old, old)).To(Succeed())
+		removed, err = EvictCache(dir, 1, 0, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(Equal([]string{filepath.Join(dir, "owner1")}))
+		Expect(dir).To(BeADirectory())
+	})
+
+	It("should evict the entries of the repositories in the order of the last use", func() {
+		dir := GinkgoT().TempDir()
+		now := time.Now()
+		for i, repo := range []string{"owner/used", "owner/modified", "owner/running"} {
+			p := filepath.Join(dir, repo)
+			Expect(os.MkdirAll(p, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(p, "data"), make([]byte, 100), 0644)).To(Succeed())
+			// owner/modified has the newest file but is used before owner/used.
+			modified := now.Add(-time.Duration([]int{3, 1, 3}[i]) * time.Hour)
+			Expect(os.Chtimes(filepath.Join(p, "data"), modified, modified)).To(Succeed())
+			// owner/running is used by a running job.
+			used := now.Add(-[]time.Duration{time.Hour, 2 * time.Hour, time.Minute}[i])
+			Expect(os.Chtimes(p, used, used)).To(Succeed())
+		}
+
+		removed, err := EvictCache(dir, 2, 0, 30*time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(Equal([]string{
+			filepath.Join(dir, "owner", "modified"),
+			filepath.Join(dir, "owner", "used"),
+		}))
+		Expect(filepath.Join(dir, "owner", "running", "data")).To(BeAnExistingFile())
+	})
+
+	It("should not evict the shared cache used by the jobs", func() {
+		dir := GinkgoT().TempDir()
+		old := time.Now().Add(-time.Hour)
+		for _, name := range []string{"entry1", "entry2"} {
+			Expect(os.WriteFile(filepath.Join(dir, name), make([]byte, 100), 0644)).To(Succeed())
+			Expect(os.Chtimes(filepath.Join(dir, name), old, old)).To(Succeed())
+		}
+		stamp := filepath.Join(dir, cacheStampName)
+		Expect(os.WriteFile(stamp, nil, 0644)).To(Succeed())
+
+		By("evicting nothing while the cache is used")
+		removed, err := EvictCache(dir, 1, 0, 30*time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(BeEmpty())
+
+		By("evicting the entries after the window")
+		Expect(os.Chtimes(stamp, old, old)).To(Succeed())
+		removed, err = EvictCache(dir, 1, 0, 30*time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(removed).To(ConsistOf(filepath.Join(dir, "entry1"), filepath.Join(dir, "entry2")))
+		Expect(stamp).To(BeAnExistingFile())
+	})
+})
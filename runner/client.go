@@ -4,28 +4,48 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"time"
 
 	constants "github.com/cybozu-go/meows"
+	corev1 "k8s.io/api/core/v1"
 )
 
+// ErrJITConfigConflict is returned when the runner pod has already accepted another just-in-time configuration.
+var ErrJITConfigConflict = errors.New("jit config is already delivered")
+
 type Client interface {
-	PutDeletionTime(ctx context.Context, ip string, tm time.Time) error
-	GetStatus(ctx context.Context, ip string) (*Status, error)
+	PutDeletionTime(ctx context.Context, po *corev1.Pod, tm time.Time) error
+	GetStatus(ctx context.Context, po *corev1.Pod) (*Status, error)
+	PutJITConfig(ctx context.Context, po *corev1.Pod, config string) error
 }
 
 type clientImpl struct {
 	client http.Client
+	apiKey []byte
 }
 
-func NewClient() Client {
-	return &clientImpl{}
+// NewClient creates a client for the runner pods.
+// The client presents the API tokens derived from apiKey. If apiKey is nil, it sends the requests without the tokens.
+func NewClient(apiKey []byte) Client {
+	return &clientImpl{
+		apiKey: apiKey,
+	}
+}
+
+func (c *clientImpl) do(req *http.Request, po *corev1.Pod) (*http.Response, error) {
+	if c.apiKey != nil {
+		token := APIToken(c.apiKey, po.Namespace, po.Labels[constants.AppInstanceLabelKey])
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.client.Do(req)
 }
 
-func (c *clientImpl) PutDeletionTime(ctx context.Context, ip string, tm time.Time) error {
+func (c *clientImpl) PutDeletionTime(ctx context.Context, po *corev1.Pod, tm time.Time) error {
+	ip := po.Status.PodIP
 	b, err := json.Marshal(DeletionTimePayload{
 		DeletionTime: tm,
 	})
@@ -39,7 +59,7 @@ func (c *clientImpl) PutDeletionTime(ctx context.Context, ip string, tm time.Tim
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.client.Do(req)
+	res, err := c.do(req, po)
 	if err != nil {
 		return err
 	}
@@ -51,13 +71,14 @@ func (c *clientImpl) PutDeletionTime(ctx context.Context, ip string, tm time.Tim
 	return nil
 }
 
-func (c *clientImpl) GetStatus(ctx context.Context, ip string) (*Status, error) {
+func (c *clientImpl) GetStatus(ctx context.Context, po *corev1.Pod) (*Status, error) {
+	ip := po.Status.PodIP
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, getStatusURL(ip), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := c.client.Do(req)
+	res, err := c.do(req, po)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +99,37 @@ func (c *clientImpl) GetStatus(ctx context.Context, ip string) (*Status, error)
 	return &s, nil
 }
 
+func (c *clientImpl) PutJITConfig(ctx context.Context, po *corev1.Pod, config string) error {
+	ip := po.Status.PodIP
+	b, err := json.Marshal(JITConfigPayload{
+		EncodedJITConfig: config,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, getJITConfigURL(ip), bytes.NewBuffer(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.do(req, po)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	// Do not include the payload in the error, because the config is a credential.
+	if res.StatusCode == http.StatusConflict {
+		return fmt.Errorf("runner pod (%s) return %d; %w", ip, res.StatusCode, ErrJITConfigConflict)
+	}
+	if res.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("runner pod (%s) return %d", ip, res.StatusCode)
+	}
+	return nil
+}
+
 func getStatusURL(ip string) string {
 	return fmt.Sprintf("http://%s:%d/%s", ip, constants.RunnerListenPort, constants.StatusEndPoint)
 }
@@ -86,18 +138,25 @@ func getDeletionTimeURL(ip string) string {
 	return fmt.Sprintf("http://%s:%d/%s", ip, constants.RunnerListenPort, constants.DeletionTimeEndpoint)
 }
 
+func getJITConfigURL(ip string) string {
+	return fmt.Sprintf("http://%s:%d/%s", ip, constants.RunnerListenPort, constants.JITConfigEndpoint)
+}
+
 // FakeClient is a fake client
 type FakeClient struct {
-	statuses map[string]*Status
+	statuses   map[string]*Status
+	jitConfigs map[string]string
 }
 
 func NewFakeClient() *FakeClient {
 	return &FakeClient{
-		statuses: map[string]*Status{},
+		statuses:   map[string]*Status{},
+		jitConfigs: map[string]string{},
 	}
 }
 
-func (c *FakeClient) PutDeletionTime(ctx context.Context, ip string, tm time.Time) error {
+func (c *FakeClient) PutDeletionTime(ctx context.Context, po *corev1.Pod, tm time.Time) error {
+	ip := po.Status.PodIP
 	if _, ok := c.statuses[ip]; !ok {
 		return fmt.Errorf("[FakeClient.PutDeletionTime] runner pod (%s) status is not defined", ip)
 	}
@@ -105,7 +164,8 @@ func (c *FakeClient) PutDeletionTime(ctx context.Context, ip string, tm time.Tim
 	return nil
 }
 
-func (c *FakeClient) GetStatus(ctx context.Context, ip string) (*Status, error) {
+func (c *FakeClient) GetStatus(ctx context.Context, po *corev1.Pod) (*Status, error) {
+	ip := po.Status.PodIP
 	if st, ok := c.statuses[ip]; ok {
 		return st, nil
 	}
@@ -115,3 +175,26 @@ func (c *FakeClient) GetStatus(ctx context.Context, ip string) (*Status, error)
 func (c *FakeClient) SetStatus(ip string, st *Status) {
 	c.statuses[ip] = st
 }
+
+func (c *FakeClient) PutJITConfig(ctx context.Context, po *corev1.Pod, config string) error {
+	ip := po.Status.PodIP
+	st, ok := c.statuses[ip]
+	if !ok {
+		return fmt.Errorf("[FakeClient.PutJITConfig] runner pod (%s) status is not defined", ip)
+	}
+	if _, ok := c.jitConfigs[ip]; ok {
+		return fmt.Errorf("[FakeClient.PutJITConfig] runner pod (%s); %w", ip, ErrJITConfigConflict)
+	}
+	st.JITConfigRequired = false
+	c.jitConfigs[ip] = config
+	return nil
+}
+
+// SetJITConfig sets the configuration delivered by someone other than the controller.
+func (c *FakeClient) SetJITConfig(ip string, config string) {
+	c.jitConfigs[ip] = config
+}
+
+func (c *FakeClient) GetJITConfig(ip string) string {
+	return c.jitConfigs[ip]
+}
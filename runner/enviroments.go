@@ -4,31 +4,57 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	constants "github.com/cybozu-go/meows"
 )
 
+// defaultTerminationGracePeriod is the default of terminationGracePeriodSeconds of the pods.
+const defaultTerminationGracePeriod = 30 * time.Second
+
+// defaultMaxListenerRetries is the default number of times to re-launch the listener.
+const defaultMaxListenerRetries = 10
+
 // Omittable options
 type Option struct {
 	SetupCommand []string `json:"setup_command,omitempty"`
+	Labels       []string `json:"labels,omitempty"`
+	RunnerGroup  string   `json:"runner_group,omitempty"`
+	GitHubURL    string   `json:"github_url,omitempty"`
+	JITConfig    bool     `json:"jit_config,omitempty"`
+
+	// TerminationGracePeriodSeconds is the grace period of the runner pod to wait for the running job on termination.
+	TerminationGracePeriodSeconds *int64 `json:"termination_grace_period_seconds,omitempty"`
+
+	// MaxListenerRetries is the number of times to re-launch the listener before the runner pod fails.
+	MaxListenerRetries *int32 `json:"max_listener_retries,omitempty"`
 }
 
 type environments struct {
-	podName        string
-	podNamespace   string
-	runnerOrg      string
-	runnerRepo     string
-	runnerPoolName string
-	setupCommand   []string
+	podName          string
+	podNamespace     string
+	runnerOrg        string
+	runnerRepo       string
+	runnerEnterprise string
+	runnerPoolName   string
+	setupCommand     []string
+	labels           []string
+	runnerGroup      string
+	githubURL        string
+	jitConfig        bool
+	gracePeriod      time.Duration
+	maxRetries       int
 }
 
 func newRunnerEnvs() (*environments, error) {
 	envs := &environments{
-		podName:        os.Getenv(constants.PodNameEnvName),
-		podNamespace:   os.Getenv(constants.PodNamespaceEnvName),
-		runnerOrg:      os.Getenv(constants.RunnerOrgEnvName),
-		runnerRepo:     os.Getenv(constants.RunnerRepoEnvName),
-		runnerPoolName: os.Getenv(constants.RunnerPoolNameEnvName),
+		podName:          os.Getenv(constants.PodNameEnvName),
+		podNamespace:     os.Getenv(constants.PodNamespaceEnvName),
+		runnerOrg:        os.Getenv(constants.RunnerOrgEnvName),
+		runnerRepo:       os.Getenv(constants.RunnerRepoEnvName),
+		runnerEnterprise: os.Getenv(constants.RunnerEnterpriseEnvName),
+		runnerPoolName:   os.Getenv(constants.RunnerPoolNameEnvName),
 	}
 	if err := envs.validateRequiredEnvs(); err != nil {
 		return nil, err
@@ -40,6 +66,21 @@ func newRunnerEnvs() (*environments, error) {
 		return nil, fmt.Errorf("failed to unmarshal %s; %w", constants.RunnerOptionEnvName, err)
 	}
 	envs.setupCommand = opt.SetupCommand
+	envs.labels = opt.Labels
+	envs.runnerGroup = opt.RunnerGroup
+	envs.jitConfig = opt.JITConfig
+	envs.gracePeriod = defaultTerminationGracePeriod
+	if opt.TerminationGracePeriodSeconds != nil {
+		envs.gracePeriod = time.Duration(*opt.TerminationGracePeriodSeconds) * time.Second
+	}
+	envs.maxRetries = defaultMaxListenerRetries
+	if opt.MaxListenerRetries != nil {
+		envs.maxRetries = int(*opt.MaxListenerRetries)
+	}
+	envs.githubURL = constants.DefaultGitHubURL
+	if opt.GitHubURL != "" {
+		envs.githubURL = strings.TrimSuffix(opt.GitHubURL, "/")
+	}
 
 	return envs, nil
 }
@@ -54,8 +95,14 @@ func (e *environments) validateRequiredEnvs() error {
 	if len(e.runnerPoolName) == 0 {
 		return fmt.Errorf("%s must be set", constants.RunnerPoolNameEnvName)
 	}
-	if (len(e.runnerOrg) == 0 && len(e.runnerRepo) == 0) || (len(e.runnerOrg) != 0 && len(e.runnerRepo) != 0) {
-		return fmt.Errorf("either %s or %s must be set", constants.RunnerOrgEnvName, constants.RunnerRepoEnvName)
+	var scopes int
+	for _, v := range []string{e.runnerOrg, e.runnerRepo, e.runnerEnterprise} {
+		if len(v) != 0 {
+			scopes++
+		}
+	}
+	if scopes != 1 {
+		return fmt.Errorf("only one of %s, %s and %s must be set", constants.RunnerOrgEnvName, constants.RunnerRepoEnvName, constants.RunnerEnterpriseEnvName)
 	}
 	return nil
 }
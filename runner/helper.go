@@ -5,16 +5,25 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	constants "github.com/cybozu-go/meows"
 )
 
+// commandWaitDelay is the time to wait for the interrupted command to exit before killing it.
+const commandWaitDelay = 5 * time.Second
+
 func runCommand(ctx context.Context, workDir, commandStr string, args ...string) (int, error) {
 	command := exec.CommandContext(ctx, commandStr, args...)
 	command.Stdout = os.Stdout
 	command.Stderr = os.Stderr
 	command.Dir = workDir
 	command.Env = removedEnv()
+	// Interrupt the command on cancellation to let it stop gracefully, e.g. Runner.Listener deletes its session.
+	command.Cancel = func() error {
+		return command.Process.Signal(os.Interrupt)
+	}
+	command.WaitDelay = commandWaitDelay
 	err := command.Run()
 	return command.ProcessState.ExitCode(), err
 }
@@ -25,6 +34,7 @@ func removedEnv() []string {
 		constants.PodNamespaceEnvName,
 		constants.RunnerOrgEnvName,
 		constants.RunnerRepoEnvName,
+		constants.RunnerEnterpriseEnvName,
 		constants.RunnerPoolNameEnvName,
 		constants.RunnerOptionEnvName,
 	}
@@ -6,18 +6,30 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	constants "github.com/cybozu-go/meows"
 )
 
 // JobInfo represents information about a CI job.
 type JobInfo struct {
 	Actor          string `json:"actor,omitempty"`
+	CommitSHA      string `json:"sha,omitempty"`
+	EventName      string `json:"event_name,omitempty"`
 	GitRef         string `json:"git_ref,omitempty"`
 	JobID          string `json:"job_id,omitempty"`
 	PullRequestNum int    `json:"pull_request_number,omitempty"`
 	Repository     string `json:"repository,omitempty"`
+	RunAttempt     int    `json:"run_attempt,omitempty"`
 	RunID          int    `json:"run_id,omitempty"`
 	RunNumber      int    `json:"run_number,omitempty"`
+	RunnerName     string `json:"runner_name,omitempty"`
+	ServerURL      string `json:"server_url,omitempty"`
 	WorkflowName   string `json:"workflow_name,omitempty"`
+	WorkflowRef    string `json:"workflow_ref,omitempty"`
+
+	// JobURL is the HTML URL of the job.
+	// The runner pod cannot call the GitHub API, so this is resolved by the controller.
+	JobURL string `json:"job_url,omitempty"`
 }
 
 // GetJobInfo reads environment variables and creates JobInfo.
@@ -46,46 +58,82 @@ func GetJobInfoFromFile(file string) (*JobInfo, error) {
 	return &jobInfo, nil
 }
 
+// serverURL returns the URL of the GitHub server which runs the job.
+// The job info created by an old runner does not have the server URL, so it defaults to GitHub.com.
+func (info *JobInfo) serverURL() string {
+	if info.ServerURL == "" {
+		return constants.DefaultGitHubURL
+	}
+	return strings.TrimSuffix(info.ServerURL, "/")
+}
+
 func (info *JobInfo) RepositoryURL() string {
-	return fmt.Sprintf("https://github.com/%s", info.Repository)
+	return fmt.Sprintf("%s/%s", info.serverURL(), info.Repository)
 }
 
 func (info *JobInfo) WorkflowURL() string {
-	return fmt.Sprintf("https://github.com/%s/actions/runs/%d", info.Repository, info.RunID)
+	return fmt.Sprintf("%s/%s/actions/runs/%d", info.serverURL(), info.Repository, info.RunID)
+}
+
+// JobOrWorkflowURL returns the URL of the job if it is resolved, otherwise the URL of the workflow run.
+func (info *JobInfo) JobOrWorkflowURL() string {
+	if info.JobURL != "" {
+		return info.JobURL
+	}
+	return info.WorkflowURL()
+}
+
+func (info *JobInfo) CommitURL() string {
+	if info.CommitSHA == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/commit/%s", info.serverURL(), info.Repository, info.CommitSHA)
 }
 
 func (info *JobInfo) BranchTagURL() string {
-	return fmt.Sprintf("https://github.com/%s/tree/%s", info.Repository, info.GitRef)
+	return fmt.Sprintf("%s/%s/tree/%s", info.serverURL(), info.Repository, info.GitRef)
 }
 
 func (info *JobInfo) PullRequestURL() string {
 	if info.PullRequestNum == 0 {
 		return ""
 	}
-	return fmt.Sprintf("https://github.com/%s/pull/%d", info.Repository, info.PullRequestNum)
+	return fmt.Sprintf("%s/%s/pull/%d", info.serverURL(), info.Repository, info.PullRequestNum)
 }
 
 type inputEnv struct {
-	GITHUB_ACTOR      string
-	GITHUB_HEAD_REF   string
-	GITHUB_JOB        string
-	GITHUB_REF        string
-	GITHUB_REPOSITORY string
-	GITHUB_RUN_ID     string
-	GITHUB_RUN_NUMBER string
-	GITHUB_WORKFLOW   string
+	GITHUB_ACTOR        string
+	GITHUB_EVENT_NAME   string
+	GITHUB_HEAD_REF     string
+	GITHUB_JOB          string
+	GITHUB_REF          string
+	GITHUB_REPOSITORY   string
+	GITHUB_RUN_ATTEMPT  string
+	GITHUB_RUN_ID       string
+	GITHUB_RUN_NUMBER   string
+	GITHUB_SERVER_URL   string
+	GITHUB_SHA          string
+	GITHUB_WORKFLOW     string
+	GITHUB_WORKFLOW_REF string
+	RUNNER_NAME         string
 }
 
 func readEnv() *inputEnv {
 	return &inputEnv{
-		GITHUB_ACTOR:      os.Getenv("GITHUB_ACTOR"),
-		GITHUB_HEAD_REF:   os.Getenv("GITHUB_HEAD_REF"),
-		GITHUB_JOB:        os.Getenv("GITHUB_JOB"),
-		GITHUB_REF:        os.Getenv("GITHUB_REF"),
-		GITHUB_REPOSITORY: os.Getenv("GITHUB_REPOSITORY"),
-		GITHUB_RUN_ID:     os.Getenv("GITHUB_RUN_ID"),
-		GITHUB_RUN_NUMBER: os.Getenv("GITHUB_RUN_NUMBER"),
-		GITHUB_WORKFLOW:   os.Getenv("GITHUB_WORKFLOW"),
+		GITHUB_ACTOR:        os.Getenv("GITHUB_ACTOR"),
+		GITHUB_EVENT_NAME:   os.Getenv("GITHUB_EVENT_NAME"),
+		GITHUB_HEAD_REF:     os.Getenv("GITHUB_HEAD_REF"),
+		GITHUB_JOB:          os.Getenv("GITHUB_JOB"),
+		GITHUB_REF:          os.Getenv("GITHUB_REF"),
+		GITHUB_REPOSITORY:   os.Getenv("GITHUB_REPOSITORY"),
+		GITHUB_RUN_ATTEMPT:  os.Getenv("GITHUB_RUN_ATTEMPT"),
+		GITHUB_RUN_ID:       os.Getenv("GITHUB_RUN_ID"),
+		GITHUB_RUN_NUMBER:   os.Getenv("GITHUB_RUN_NUMBER"),
+		GITHUB_SERVER_URL:   os.Getenv("GITHUB_SERVER_URL"),
+		GITHUB_SHA:          os.Getenv("GITHUB_SHA"),
+		GITHUB_WORKFLOW:     os.Getenv("GITHUB_WORKFLOW"),
+		GITHUB_WORKFLOW_REF: os.Getenv("GITHUB_WORKFLOW_REF"),
+		RUNNER_NAME:         os.Getenv("RUNNER_NAME"),
 	}
 }
 
@@ -126,15 +174,29 @@ func envToJobInfo(env *inputEnv) (*JobInfo, error) {
 	if err != nil {
 		return nil, fmt.Errorf("invalid value: GITHUB_RUN_NUMBER = \"%s\", %w", env.GITHUB_RUN_NUMBER, err)
 	}
+	// GITHUB_RUN_ATTEMPT is not set by old runners.
+	var runAttempt int
+	if env.GITHUB_RUN_ATTEMPT != "" {
+		runAttempt, err = strconv.Atoi(env.GITHUB_RUN_ATTEMPT)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value: GITHUB_RUN_ATTEMPT = \"%s\", %w", env.GITHUB_RUN_ATTEMPT, err)
+		}
+	}
 
 	return &JobInfo{
 		Actor:          env.GITHUB_ACTOR,
+		CommitSHA:      env.GITHUB_SHA,
+		EventName:      env.GITHUB_EVENT_NAME,
 		GitRef:         gitRef,
 		JobID:          env.GITHUB_JOB,
 		PullRequestNum: pullRequestNumber,
 		Repository:     env.GITHUB_REPOSITORY,
+		RunAttempt:     runAttempt,
 		RunID:          runID,
 		RunNumber:      runNumber,
+		RunnerName:     env.RUNNER_NAME,
+		ServerURL:      env.GITHUB_SERVER_URL,
 		WorkflowName:   env.GITHUB_WORKFLOW,
+		WorkflowRef:    env.GITHUB_WORKFLOW_REF,
 	}, nil
 }
@@ -17,6 +17,7 @@ func TestJobInfo(t *testing.T) {
 		expectedWorkflowURL    string
 		expectedBranchTagURL   string
 		expectedPullRequestURL string
+		expectedCommitURL      string
 	}{
 		{
 			title: "branch-push",
@@ -99,6 +100,83 @@ func TestJobInfo(t *testing.T) {
 			expectedBranchTagURL:   "https://github.com/owner/repo/tree/branch-name",
 			expectedPullRequestURL: "https://github.com/owner/repo/pull/123",
 		},
+		{
+			title: "github-enterprise-server",
+			input: &inputEnv{
+				GITHUB_ACTOR:      "user",
+				GITHUB_HEAD_REF:   "branch-name", // branch name
+				GITHUB_JOB:        "job",
+				GITHUB_REF:        "refs/pull/123/merge", // refs/pull/<PR_NUM>/merge
+				GITHUB_REPOSITORY: "owner/repo",
+				GITHUB_RUN_ID:     "123456789",
+				GITHUB_RUN_NUMBER: "987",
+				GITHUB_SERVER_URL: "https://github.example.com",
+				GITHUB_WORKFLOW:   "Work flow",
+			},
+			expectedJobInfo: &JobInfo{
+				Actor:          "user",
+				GitRef:         "branch-name",
+				JobID:          "job",
+				PullRequestNum: 123,
+				Repository:     "owner/repo",
+				RunID:          123456789,
+				RunNumber:      987,
+				ServerURL:      "https://github.example.com",
+				WorkflowName:   "Work flow",
+			},
+			expectedRepositoryURL:  "https://github.example.com/owner/repo",
+			expectedWorkflowURL:    "https://github.example.com/owner/repo/actions/runs/123456789",
+			expectedBranchTagURL:   "https://github.example.com/owner/repo/tree/branch-name",
+			expectedPullRequestURL: "https://github.example.com/owner/repo/pull/123",
+		},
+		{
+			title: "all-fields",
+			input: &inputEnv{
+				GITHUB_ACTOR:        "user",
+				GITHUB_EVENT_NAME:   "push",
+				GITHUB_HEAD_REF:     "", // blank
+				GITHUB_JOB:          "job",
+				GITHUB_REF:          "refs/heads/main",
+				GITHUB_REPOSITORY:   "owner/repo",
+				GITHUB_RUN_ATTEMPT:  "2",
+				GITHUB_RUN_ID:       "123456789",
+				GITHUB_RUN_NUMBER:   "987",
+				GITHUB_SHA:          "0123456789abcdef0123456789abcdef01234567",
+				GITHUB_WORKFLOW:     "Work flow",
+				GITHUB_WORKFLOW_REF: "owner/repo/.github/workflows/ci.yaml@refs/heads/main",
+				RUNNER_NAME:         "runner-pod",
+			},
+			expectedJobInfo: &JobInfo{
+				Actor:          "user",
+				CommitSHA:      "0123456789abcdef0123456789abcdef01234567",
+				EventName:      "push",
+				GitRef:         "main",
+				JobID:          "job",
+				PullRequestNum: 0,
+				Repository:     "owner/repo",
+				RunAttempt:     2,
+				RunID:          123456789,
+				RunNumber:      987,
+				RunnerName:     "runner-pod",
+				WorkflowName:   "Work flow",
+				WorkflowRef:    "owner/repo/.github/workflows/ci.yaml@refs/heads/main",
+			},
+			expectedRepositoryURL:  "https://github.com/owner/repo",
+			expectedWorkflowURL:    "https://github.com/owner/repo/actions/runs/123456789",
+			expectedBranchTagURL:   "https://github.com/owner/repo/tree/main",
+			expectedPullRequestURL: "",
+			expectedCommitURL:      "https://github.com/owner/repo/commit/0123456789abcdef0123456789abcdef01234567",
+		},
+		{
+			title:     "invalid-run-attempt",
+			errorCase: true,
+			input: &inputEnv{
+				GITHUB_REF:         "refs/heads/main",
+				GITHUB_RUN_ATTEMPT: "first",
+				GITHUB_RUN_ID:      "123456789",
+				GITHUB_RUN_NUMBER:  "987",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -120,6 +198,9 @@ func TestJobInfo(t *testing.T) {
 				if tc.expectedPullRequestURL != actual.PullRequestURL() {
 					t.Error(tc.title, "| expected:", tc.expectedPullRequestURL, " actual:", actual.PullRequestURL())
 				}
+				if tc.expectedCommitURL != actual.CommitURL() {
+					t.Error(tc.title, "| expected:", tc.expectedCommitURL, " actual:", actual.CommitURL())
+				}
 				if err != nil {
 					t.Error(tc.title, "| got error", err)
 				}
@@ -0,0 +1,138 @@
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// JobResult is the result of a job read from the log of the runner worker.
+type JobResult struct {
+	Result      string   `json:"result,omitempty"`
+	FailedSteps []string `json:"failed_steps,omitempty"`
+}
+
+// The runner worker writes the following logs in `_diag/Worker_<timestamp>-utc.log`.
+// ref: https://github.com/actions/runner/blob/main/src/Runner.Worker/StepsRunner.cs
+// ref: https://github.com/actions/runner/blob/main/src/Runner.Worker/JobRunner.cs
+var (
+	workerStepRegexp       = regexp.MustCompile(`Processing step: DisplayName='(.*)'`)
+	workerStepResultRegexp = regexp.MustCompile(`Update job result with current step result '(\w+)'`)
+	workerJobResultRegexp  = regexp.MustCompile(`Job result after all job steps finish: (\w+)`)
+)
+
+// taskResults maps the TaskResult of the runner to the job result.
+var taskResults = map[string]string{
+	"Succeeded":           JobResultSuccess,
+	"SucceededWithIssues": JobResultSuccess,
+	"Skipped":             JobResultSuccess,
+	"Failed":              JobResultFailure,
+	"Abandoned":           JobResultFailure,
+	"Canceled":            JobResultCancelled,
+}
+
+// ParseWorkerLog reads the result of the job and the failed steps from the log of the runner worker.
+// If the job has not finished yet, the result is merged from the results of the finished steps.
+// The result is empty if no step has finished.
+func ParseWorkerLog(r io.Reader) (*JobResult, error) {
+	res := &JobResult{}
+	var step, stepResult, jobResult string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := workerStepRegexp.FindStringSubmatch(line); m != nil {
+			step = m[1]
+			continue
+		}
+		if m := workerStepResultRegexp.FindStringSubmatch(line); m != nil {
+			result := taskResults[m[1]]
+			if result == JobResultFailure && step != "" {
+				res.FailedSteps = append(res.FailedSteps, step)
+			}
+			stepResult = mergeJobResults(stepResult, result)
+			continue
+		}
+		if m := workerJobResultRegexp.FindStringSubmatch(line); m != nil {
+			jobResult = taskResults[m[1]]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	res.Result = stepResult
+	if jobResult != "" {
+		res.Result = jobResult
+	}
+	return res, nil
+}
+
+// mergeJobResults returns the worse result of the two.
+func mergeJobResults(a, b string) string {
+	for _, r := range []string{JobResultFailure, JobResultCancelled, JobResultSuccess} {
+		if a == r || b == r {
+			return r
+		}
+	}
+	return ""
+}
+
+// ReadWorkerLog parses the latest log of the runner worker in the diag directory.
+// It returns nil if there is no log.
+func ReadWorkerLog(diagDir string) (*JobResult, error) {
+	logs, err := filepath.Glob(filepath.Join(diagDir, "Worker_*.log"))
+	if err != nil {
+		return nil, err
+	}
+	if len(logs) == 0 {
+		return nil, nil
+	}
+	// The file names contain the UTC timestamps, so the last one is the latest.
+	sort.Strings(logs)
+	latest := logs[len(logs)-1]
+
+	f, err := os.Open(latest)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	res, err := ParseWorkerLog(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s; %w", latest, err)
+	}
+	return res, nil
+}
+
+// WriteJobResultFile writes the job result to the file.
+func WriteJobResultFile(file string, res *JobResult) error {
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0664)
+}
+
+// ReadJobResultFile reads the job result written by WriteJobResultFile.
+// It returns nil if the file does not exist.
+func ReadJobResultFile(file string) (*JobResult, error) {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var res JobResult
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,81 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testWorkerLog = `[2024-01-01 00:00:00Z INFO Worker] Waiting to receive the job message from the channel.
+[2024-01-01 00:00:01Z INFO StepsRunner] Processing step: DisplayName='Set up job'
+[2024-01-01 00:00:02Z INFO StepsRunner] Processing step: DisplayName='Run actions/checkout@v4'
+[2024-01-01 00:00:03Z INFO StepsRunner] Update job result with current step result 'Succeeded'.
+[2024-01-01 00:00:04Z INFO StepsRunner] Processing step: DisplayName='Run make test'
+[2024-01-01 00:00:05Z INFO StepsRunner] Update job result with current step result 'Failed'.
+[2024-01-01 00:00:06Z INFO StepsRunner] Processing step: DisplayName='Post Run actions/checkout@v4'
+[2024-01-01 00:00:07Z INFO StepsRunner] Update job result with current step result 'Succeeded'.
+[2024-01-01 00:00:08Z INFO JobRunner] Job result after all job steps finish: Failed
+`
+
+func TestParseWorkerLog(t *testing.T) {
+	testCases := []struct {
+		title    string
+		input    string
+		expected *JobResult
+	}{
+		{
+			title:    "empty",
+			input:    "",
+			expected: &JobResult{},
+		},
+		{
+			title: "success",
+			input: `[2024-01-01 00:00:02Z INFO StepsRunner] Processing step: DisplayName='Run make test'
+[2024-01-01 00:00:03Z INFO StepsRunner] Update job result with current step result 'Succeeded'.
+[2024-01-01 00:00:04Z INFO JobRunner] Job result after all job steps finish: Succeeded
+`,
+			expected: &JobResult{Result: JobResultSuccess},
+		},
+		{
+			title: "failure",
+			input: testWorkerLog,
+			expected: &JobResult{
+				Result:      JobResultFailure,
+				FailedSteps: []string{"Run make test"},
+			},
+		},
+		{
+			title: "cancelled",
+			input: `[2024-01-01 00:00:02Z INFO StepsRunner] Processing step: DisplayName='Run make test'
+[2024-01-01 00:00:03Z INFO StepsRunner] Update job result with current step result 'Canceled'.
+[2024-01-01 00:00:04Z INFO JobRunner] Job result after all job steps finish: Canceled
+`,
+			expected: &JobResult{Result: JobResultCancelled},
+		},
+		{
+			title: "job not finished",
+			input: `[2024-01-01 00:00:02Z INFO StepsRunner] Processing step: DisplayName='Run make lint'
+[2024-01-01 00:00:03Z INFO StepsRunner] Update job result with current step result 'Failed'.
+[2024-01-01 00:00:04Z INFO StepsRunner] Processing step: DisplayName='Run make test'
+[2024-01-01 00:00:05Z INFO StepsRunner] Update job result with current step result 'Succeeded'.
+`,
+			expected: &JobResult{
+				Result:      JobResultFailure,
+				FailedSteps: []string{"Run make lint"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			actual, err := ParseWorkerLog(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tc.expected, actual) {
+				t.Error(cmp.Diff(tc.expected, actual))
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"os/exec"
 	"path/filepath"
 	"time"
@@ -14,7 +16,7 @@ import (
 
 type Listener interface {
 	configure(ctx context.Context, configArgs []string) error
-	listen(ctx context.Context) error
+	listen(ctx context.Context, jitConfig string) error
 }
 
 type listenerImpl struct {
@@ -36,36 +38,66 @@ func (l *listenerImpl) configure(ctx context.Context, configArgs []string) error
 	return err
 }
 
-func (l *listenerImpl) listen(ctx context.Context) error {
+// errRetryableExit is wrapped by the error returned by listen when Runner.Listener should be re-launched.
+var errRetryableExit = errors.New("runner listener exited with retryable error")
+
+// listen runs Runner.Listener once.
+// If the listener should be re-launched, it returns an error wrapping errRetryableExit.
+func (l *listenerImpl) listen(ctx context.Context, jitConfig string) error {
 	logger := log.FromContext(ctx)
-	for {
-		code, err := runCommand(ctx, l.runnerDir, l.listenerCommand, "run", "--startuptype", "service")
-		if _, ok := err.(*exec.ExitError); !ok {
-			return err
-		}
+	args := []string{"run", "--startuptype", "service"}
+	if jitConfig != "" {
+		args = []string{"run", "--jitconfig", jitConfig}
+	}
+	code, err := runCommand(ctx, l.runnerDir, l.listenerCommand, args...)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if _, ok := err.(*exec.ExitError); !ok {
+		return err
+	}
+
+	// This logic is based on the following code.
+	// ref: https://github.com/actions/runner/blob/v2.309.0/src/Misc/layoutbin/RunnerService.js
+	logger.Info("Runner listener exited with error", "code", code)
+	switch code {
+	case 0:
+		logger.Info("Runner listener exit with 0 return code, stop the service, no retry needed.")
+		return nil
+	case 1:
+		logger.Info("Runner listener exit with terminated error, stop the service, no retry needed.")
+		return fmt.Errorf("runner listener exit with terminated error: %v", err)
+	case 2:
+		logger.Info("Runner listener exit with retryable error, re-launch runner.")
+		metrics.IncrementListenerExitState(constants.ListenerExitStateRetryableError)
+	case 3, 4:
+		logger.Info("Runner listener exit because of updating, re-launch runner.")
+		metrics.IncrementListenerExitState(constants.ListenerExitStateUpdating)
+	default:
+		logger.Info("Runner listener exit with undefined return code, re-launch runner.")
+		metrics.IncrementListenerExitState(constants.ListenerExitStateUndefined)
+	}
+	return fmt.Errorf("%w: code %d", errRetryableExit, code)
+}
 
-		// This logic is based on the following code.
-		// ref: https://github.com/actions/runner/blob/v2.309.0/src/Misc/layoutbin/RunnerService.js
-		logger.Info("Runner listener exited with error", "code", code)
-		switch code {
-		case 0:
-			logger.Info("Runner listener exit with 0 return code, stop the service, no retry needed.")
-			return nil
-		case 1:
-			logger.Info("Runner listener exit with terminated error, stop the service, no retry needed.")
-			return fmt.Errorf("runner listener exit with terminated error: %v", err)
-		case 2:
-			logger.Info("Runner listener exit with retryable error, re-launch runner in 10 seconds.")
-			metrics.IncrementListenerExitState(constants.ListenerExitStateRetryableError)
-		case 3, 4:
-			logger.Info("Runner listener exit because of updating, re-launch runner in 10 seconds.")
-			metrics.IncrementListenerExitState(constants.ListenerExitStateUpdating)
-		default:
-			logger.Info("Runner listener exit with undefined return code, re-launch runner in 10 seconds.")
-			metrics.IncrementListenerExitState(constants.ListenerExitStateUndefined)
-		}
+// restartBackoff is the exponential backoff to re-launch Runner.Listener.
+type restartBackoff struct {
+	initialDelay time.Duration
+	maxDelay     time.Duration
+}
+
+// The first delay is long enough to wait for the update process to finish.
+var defaultRestartBackoff = restartBackoff{
+	initialDelay: 10 * time.Second,
+	maxDelay:     5 * time.Minute,
+}
 
-		// Sleep 10 seconds to wait for the update process finish.
-		time.Sleep(10 * time.Second)
+// delay returns the delay before the n-th restart, which starts at 1.
+// It adds a jitter of up to the half of the delay, so that the pods failed at once do not restart at once.
+func (b restartBackoff) delay(n int) time.Duration {
+	d := b.maxDelay
+	if n <= 30 {
+		d = min(b.initialDelay<<(n-1), b.maxDelay)
 	}
+	return d + rand.N(d/2+1)
 }
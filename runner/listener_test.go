@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRestartBackoffDelay(t *testing.T) {
+	b := restartBackoff{
+		initialDelay: 10 * time.Second,
+		maxDelay:     5 * time.Minute,
+	}
+	testCases := []struct {
+		title string
+		n     int
+		base  time.Duration
+	}{
+		{
+			title: "first",
+			n:     1,
+			base:  10 * time.Second,
+		},
+		{
+			title: "doubled",
+			n:     3,
+			base:  40 * time.Second,
+		},
+		{
+			title: "capped",
+			n:     10,
+			base:  5 * time.Minute,
+		},
+		{
+			title: "not overflowed",
+			n:     100,
+			base:  5 * time.Minute,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.title, func(t *testing.T) {
+			for range 100 {
+				d := b.delay(tt.n)
+				if d < tt.base || d > tt.base+tt.base/2 {
+					t.Fatalf("delay(%d) = %s, want in [%s, %s]", tt.n, d, tt.base, tt.base+tt.base/2)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package runner
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -21,6 +22,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// terminationMargin is the time reserved to stop the listener before the pod is killed.
+const terminationMargin = 5 * time.Second
+
+// errListenerFailed is returned when the listener keeps exiting with retryable errors.
+var errListenerFailed = errors.New("runner listener failed")
+
 const (
 	JobResultSuccess   = "success"
 	JobResultFailure   = "failure"
@@ -33,6 +40,9 @@ type Runner struct {
 	listenAddr string
 	listener   Listener
 
+	// restartBackoff is the backoff to re-launch the listener.
+	restartBackoff restartBackoff
+
 	// Status
 	mu           sync.Mutex
 	state        string
@@ -40,15 +50,31 @@ type Runner struct {
 	finishedAt   *time.Time
 	deletionTime *time.Time
 	extend       *bool
+	failedSteps  []string
 	jobInfo      *JobInfo
 	slackChannel string
 
+	// listenerAttempts is the number of times the listener has been launched.
+	listenerAttempts int
+
+	// Just-in-time runner configuration
+	jitConfigCh       chan string
+	jitConfigReceived bool
+
+	// Graceful termination
+	stopListener   context.CancelFunc
+	jobFinishedCh  chan struct{}
+	resultReadCh   chan struct{}
+	resultReadOnce sync.Once
+
 	// Directory/File Paths
 	runnerDir         string
 	workDir           string
 	tokenPath         string
+	apiTokenPath      string
 	jobInfoFile       string
 	slackChannelFile  string
+	jobResultFile     string
 	startedFlagFile   string
 	extendFlagFile    string
 	failureFlagFile   string
@@ -62,14 +88,25 @@ type Status struct {
 	FinishedAt   *time.Time `json:"finished_at,omitempty"`
 	DeletionTime *time.Time `json:"deletion_time,omitempty"`
 	Extend       *bool      `json:"extend,omitempty"`
+	FailedSteps  []string   `json:"failed_steps,omitempty"`
 	JobInfo      *JobInfo   `json:"job_info,omitempty"`
 	SlackChannel string     `json:"slack_channel,omitempty"`
+
+	// JITConfigRequired is true while the runner waits for the just-in-time runner configuration.
+	JITConfigRequired bool `json:"jit_config_required,omitempty"`
+
+	// ListenerAttempts is the number of times the listener has been launched, including the restarts.
+	ListenerAttempts int `json:"listener_attempts,omitempty"`
 }
 
 type DeletionTimePayload struct {
 	DeletionTime time.Time `json:"deletion_time"`
 }
 
+type JITConfigPayload struct {
+	EncodedJITConfig string `json:"encoded_jit_config"`
+}
+
 func NewRunner(listener Listener, listenAddr, runnerDir, workDir, varDir string) (*Runner, error) {
 	envs, err := newRunnerEnvs()
 	if err != nil {
@@ -80,11 +117,17 @@ func NewRunner(listener Listener, listenAddr, runnerDir, workDir, varDir string)
 		envs:              envs,
 		listenAddr:        listenAddr,
 		listener:          listener,
+		restartBackoff:    defaultRestartBackoff,
+		jitConfigCh:       make(chan string, 1),
+		jobFinishedCh:     make(chan struct{}),
+		resultReadCh:      make(chan struct{}),
 		runnerDir:         runnerDir,
 		workDir:           workDir,
 		tokenPath:         filepath.Join(varDir, constants.SecretsDirName, constants.RunnerTokenFileName),
+		apiTokenPath:      filepath.Join(varDir, constants.APITokenDirName, constants.APITokenFileName),
 		jobInfoFile:       filepath.Join(varDir, "github.env"),
 		slackChannelFile:  filepath.Join(varDir, "slack_channel"),
+		jobResultFile:     filepath.Join(varDir, "job_result.json"),
 		startedFlagFile:   filepath.Join(varDir, "started"),
 		extendFlagFile:    filepath.Join(varDir, "extend"),
 		failureFlagFile:   filepath.Join(varDir, "failure"),
@@ -103,8 +146,9 @@ func (r *Runner) Run(ctx context.Context) error {
 
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.InstrumentMetricHandler(registry, promhttp.HandlerFor(registry, promhttp.HandlerOpts{})))
-	mux.Handle("/"+constants.DeletionTimeEndpoint, http.HandlerFunc(r.deletionTimeHandler))
-	mux.Handle("/"+constants.StatusEndPoint, http.HandlerFunc(r.statusHandler))
+	mux.Handle("/"+constants.DeletionTimeEndpoint, r.authenticate(r.deletionTimeHandler))
+	mux.Handle("/"+constants.StatusEndPoint, r.authenticate(r.statusHandler))
+	mux.Handle("/"+constants.JITConfigEndpoint, r.authenticateStrictly(r.jitConfigHandler))
 	serv := &well.HTTPServer{
 		Env: env,
 		Server: &http.Server{
@@ -123,7 +167,6 @@ func (r *Runner) Run(ctx context.Context) error {
 func (r *Runner) runListener(ctx context.Context) error {
 	logger := log.FromContext(ctx)
 	if isFileExists(r.startedFlagFile) {
-		metrics.UpdateRunnerPodState(constants.RunnerPodStateStale)
 		logger.Info("Pod is stale; waiting for deletion")
 		r.updateState(constants.RunnerPodStateStale)
 		<-ctx.Done()
@@ -133,7 +176,42 @@ func (r *Runner) runListener(ctx context.Context) error {
 		return err
 	}
 
-	metrics.UpdateRunnerPodState(constants.RunnerPodStateInitializing)
+	// The listener can be stopped by Terminate while the API server keeps running.
+	listenerCtx, stopListener := context.WithCancel(ctx)
+	defer stopListener()
+	r.mu.Lock()
+	r.stopListener = stopListener
+	r.mu.Unlock()
+
+	err := r.startListener(listenerCtx)
+	if listenerCtx.Err() != nil {
+		// The listener is stopped by Terminate, or the runner is stopping.
+		logger.Info("stopped runner listener")
+		<-ctx.Done()
+		return nil
+	}
+	if errors.Is(err, errListenerFailed) {
+		// Keep the pod until the controller deletes it, instead of restarting the container to be stale.
+		logger.Error(err, "runner pod failed; waiting for deletion")
+		r.updateState(constants.RunnerPodStateFailed)
+		<-ctx.Done()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	metrics.UpdateRunnerPodState(constants.RunnerPodStateDebugging)
+	r.updateToDebuggingState(logger)
+	close(r.jobFinishedCh)
+
+	<-ctx.Done()
+	return nil
+}
+
+// startListener runs the listener until it finishes a job.
+func (r *Runner) startListener(ctx context.Context) error {
+	logger := log.FromContext(ctx)
 	r.updateState(constants.RunnerPodStateInitializing)
 	if len(r.envs.setupCommand) != 0 {
 		if _, err := runCommand(ctx, r.runnerDir, r.envs.setupCommand[0], r.envs.setupCommand[1:]...); err != nil {
@@ -141,15 +219,112 @@ func (r *Runner) runListener(ctx context.Context) error {
 		}
 	}
 
+	var jitConfig string
+	if r.envs.jitConfig {
+		// The controller registers a runner for this pod and delivers the config.
+		logger.Info("waiting for jit config")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case jitConfig = <-r.jitConfigCh:
+		}
+	} else if err := r.configure(ctx); err != nil {
+		return err
+	}
+
+	r.updateState(constants.RunnerPodStateRunning)
+	return r.listen(ctx, jitConfig)
+}
+
+// listen runs the listener, and re-launches it with the backoff while it exits with retryable errors.
+// It returns errListenerFailed if the listener still exits after the max retries.
+func (r *Runner) listen(ctx context.Context, jitConfig string) error {
+	logger := log.FromContext(ctx)
+	for attempt := 1; ; attempt++ {
+		r.mu.Lock()
+		r.listenerAttempts = attempt
+		r.mu.Unlock()
+
+		err := r.listener.listen(ctx, jitConfig)
+		if !errors.Is(err, errRetryableExit) {
+			return err
+		}
+		if attempt > r.envs.maxRetries {
+			return fmt.Errorf("%w after %d attempts; %v", errListenerFailed, attempt, err)
+		}
+
+		delay := r.restartBackoff.delay(attempt)
+		logger.Info("re-launching runner listener", "attempt", attempt+1, "delay", delay.String())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// Terminate stops the runner gracefully before the pod is killed.
+// If the runner is running a job, it waits for the job to finish within the grace period,
+// and then for the controller to read the result to notify it.
+// Otherwise, it stops the listener at once not to take a new job.
+func (r *Runner) Terminate(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	r.mu.Lock()
+	state := r.state
+	if state == constants.RunnerPodStateInitializing || state == constants.RunnerPodStateRunning {
+		r.state = constants.RunnerPodStateTerminating
+	}
+	stopListener := r.stopListener
+	r.mu.Unlock()
+	if state != constants.RunnerPodStateInitializing && state != constants.RunnerPodStateRunning {
+		return
+	}
+	metrics.UpdateRunnerPodState(constants.RunnerPodStateTerminating)
+
+	if state == constants.RunnerPodStateInitializing || !r.isJobStarted() {
+		logger.Info("stopping runner listener not running a job")
+		stopListener()
+		return
+	}
+
+	gracePeriod := max(r.envs.gracePeriod-terminationMargin, 0)
+	logger.Info("waiting for the job to finish", "grace_period", gracePeriod.String())
+	timer := time.NewTimer(gracePeriod)
+	defer timer.Stop()
+	select {
+	case <-r.jobFinishedCh:
+		logger.Info("job finished; waiting for the result to be read")
+		select {
+		case <-r.resultReadCh:
+		case <-timer.C:
+		}
+	case <-timer.C:
+		logger.Info("grace period expired; stopping runner listener")
+		stopListener()
+	}
+}
+
+// isJobStarted returns true if the runner worker has started a job.
+func (r *Runner) isJobStarted() bool {
+	logs, _ := filepath.Glob(filepath.Join(r.runnerDir, "_diag", "Worker_*.log"))
+	return len(logs) != 0
+}
+
+// configure registers the runner with the registration token shared in the RunnerPool.
+func (r *Runner) configure(ctx context.Context) error {
 	b, err := os.ReadFile(r.tokenPath)
 	if err != nil {
 		return fmt.Errorf("failed load %s; %w", r.tokenPath, err)
 	}
 
-	configURL := "https://github.com/"
-	if r.envs.runnerOrg != "" {
+	configURL := r.envs.githubURL + "/"
+	switch {
+	case r.envs.runnerEnterprise != "":
+		configURL = configURL + "enterprises/" + r.envs.runnerEnterprise
+	case r.envs.runnerOrg != "":
 		configURL = configURL + r.envs.runnerOrg
-	} else {
+	default:
 		configURL = configURL + r.envs.runnerRepo
 	}
 
@@ -157,49 +332,52 @@ func (r *Runner) runListener(ctx context.Context) error {
 		"--unattended",
 		"--replace",
 		"--name", r.envs.podName,
-		"--labels", r.envs.podNamespace + "/" + r.envs.runnerPoolName,
+		"--labels", strings.Join(append([]string{r.envs.podNamespace + "/" + r.envs.runnerPoolName}, r.envs.labels...), ","),
 		"--url", configURL,
 		"--token", string(b),
 		"--work", r.workDir,
 		"--ephemeral",
 		"--disableupdate",
 	}
-	if err := r.listener.configure(ctx, configArgs); err != nil {
-		return err
-	}
-
-	metrics.UpdateRunnerPodState(constants.RunnerPodStateRunning)
-	r.updateState(constants.RunnerPodStateRunning)
-	if err := r.listener.listen(ctx); err != nil {
-		return err
+	if r.envs.runnerGroup != "" {
+		configArgs = append(configArgs, "--runnergroup", r.envs.runnerGroup)
 	}
-
-	metrics.UpdateRunnerPodState(constants.RunnerPodStateDebugging)
-	r.updateToDebuggingState(logger)
-
-	<-ctx.Done()
-	return nil
+	return r.listener.configure(ctx, configArgs)
 }
 
+// updateState updates the state of the runner pod unless it is terminating.
 func (r *Runner) updateState(state string) {
 	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.state == constants.RunnerPodStateTerminating {
+		return
+	}
 	r.state = state
-	r.mu.Unlock()
+	metrics.UpdateRunnerPodState(state)
 }
 
 func (r *Runner) updateToDebuggingState(logger logr.Logger) {
 	var result string
-	switch {
-	case isFileExists(r.failureFlagFile):
-		result = JobResultFailure
-	case isFileExists(r.cancelledFlagFile):
-		result = JobResultCancelled
-	case isFileExists(r.successFlagFile):
-		result = JobResultSuccess
-	default:
-		result = JobResultUnknown
-	}
+	var failedSteps []string
 	extend := isFileExists(r.extendFlagFile)
+	if res := r.readJobResult(logger); res != nil {
+		result = res.Result
+		failedSteps = res.FailedSteps
+		// Extend the failed jobs as `job-failure` does.
+		extend = extend || result == JobResultFailure
+	} else {
+		// Fall back to the flag files written by the workflow.
+		switch {
+		case isFileExists(r.failureFlagFile):
+			result = JobResultFailure
+		case isFileExists(r.cancelledFlagFile):
+			result = JobResultCancelled
+		case isFileExists(r.successFlagFile):
+			result = JobResultSuccess
+		default:
+			result = JobResultUnknown
+		}
+	}
 
 	finishedAt := time.Now().UTC()
 
@@ -208,6 +386,10 @@ func (r *Runner) updateToDebuggingState(logger logr.Logger) {
 		logger.Error(err, "failed to read job info")
 	}
 
+	if jobInfo != nil {
+		metrics.SetRunnerJobInfo(jobInfo.Repository, jobInfo.EventName, result)
+	}
+
 	slackChannel, err := r.readSlackChannel()
 	if err != nil {
 		logger.Error(err, "failed to read file for slack channel")
@@ -218,11 +400,34 @@ func (r *Runner) updateToDebuggingState(logger logr.Logger) {
 	r.result = result
 	r.finishedAt = &finishedAt
 	r.extend = &extend
+	r.failedSteps = failedSteps
 	r.jobInfo = jobInfo
 	r.slackChannel = slackChannel
 	r.mu.Unlock()
 }
 
+// readJobResult reads the result of the job from the log of the runner worker.
+// The log is complete after the listener exits, so it is preferred to the result written by the job-completed hook.
+// It returns nil if neither is available.
+func (r *Runner) readJobResult(logger logr.Logger) *JobResult {
+	res, err := ReadWorkerLog(filepath.Join(r.runnerDir, "_diag"))
+	if err != nil {
+		logger.Error(err, "failed to read worker log")
+	}
+	if res != nil && res.Result != "" {
+		return res
+	}
+
+	res, err = ReadJobResultFile(r.jobResultFile)
+	if err != nil {
+		logger.Error(err, "failed to read job result file")
+	}
+	if res != nil && res.Result != "" {
+		return res
+	}
+	return nil
+}
+
 func (r *Runner) readSlackChannel() (string, error) {
 	file, err := os.Open(r.slackChannelFile)
 	if err != nil {
@@ -275,9 +480,16 @@ func (r *Runner) statusHandler(w http.ResponseWriter, req *http.Request) {
 	st.FinishedAt = r.finishedAt
 	st.DeletionTime = r.deletionTime
 	st.Extend = r.extend
+	st.FailedSteps = r.failedSteps
 	st.JobInfo = r.jobInfo
 	st.SlackChannel = r.slackChannel
+	st.JITConfigRequired = r.envs.jitConfig && !r.jitConfigReceived && r.state == constants.RunnerPodStateInitializing
+	st.ListenerAttempts = r.listenerAttempts
 	r.mu.Unlock()
+	if st.State == constants.RunnerPodStateDebugging {
+		// Let Terminate know that the result is passed to the controller.
+		r.resultReadOnce.Do(func() { close(r.resultReadCh) })
+	}
 
 	res, err := json.Marshal(st)
 	if err != nil {
@@ -289,3 +501,41 @@ func (r *Runner) statusHandler(w http.ResponseWriter, req *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write(res)
 }
+
+func (r *Runner) jitConfigHandler(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !r.envs.jitConfig {
+		http.Error(w, "jit config is not enabled", http.StatusNotFound)
+		return
+	}
+
+	var payload JITConfigPayload
+	if req.Header.Get("Content-Type") != "application/json" {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+	err := json.NewDecoder(req.Body).Decode(&payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if payload.EncodedJITConfig == "" {
+		http.Error(w, "encoded_jit_config is empty", http.StatusBadRequest)
+		return
+	}
+
+	// The config can be used only once, so accept only the first one.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.jitConfigReceived {
+		http.Error(w, "jit config is already delivered", http.StatusConflict)
+		return
+	}
+	r.jitConfigReceived = true
+	r.jitConfigCh <- payload.EncodedJITConfig
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -15,6 +15,8 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 	gomegatypes "github.com/onsi/gomega/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 )
@@ -40,13 +42,16 @@ var _ = Describe("Runner", func() {
 		By("checking initializing state")
 		flagFileShouldExist("started")
 		statusShouldHaveValue(PointTo(MatchAllFields(Fields{
-			"State":        Equal("initializing"),
-			"Result":       BeEmpty(),
-			"FinishedAt":   BeNil(),
-			"DeletionTime": BeNil(),
-			"Extend":       BeNil(),
-			"JobInfo":      BeNil(),
-			"SlackChannel": BeEmpty(),
+			"State":             Equal("initializing"),
+			"Result":            BeEmpty(),
+			"FinishedAt":        BeNil(),
+			"DeletionTime":      BeNil(),
+			"Extend":            BeNil(),
+			"FailedSteps":       BeEmpty(),
+			"JobInfo":           BeNil(),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  BeZero(),
 		})))
 		metricsShouldHaveValue("meows_runner_pod_state",
 			MatchAllElementsWithIndex(IndexIdentity, Elements{
@@ -55,17 +60,25 @@ var _ = Describe("Runner", func() {
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"1": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("failed")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
+				"2": PointTo(MatchAllFields(Fields{
 					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("initializing")}),
 					"Value": BeNumerically("==", 1.0),
 				})),
-				"2": PointTo(MatchAllFields(Fields{
+				"3": PointTo(MatchAllFields(Fields{
 					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("running")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
-				"3": PointTo(MatchAllFields(Fields{
+				"4": PointTo(MatchAllFields(Fields{
 					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("stale")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
+				"5": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("terminating")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
 			}),
 		)
 		metricsShouldNotExist("meows_runner_listener_exit_state")
@@ -77,13 +90,16 @@ var _ = Describe("Runner", func() {
 
 		flagFileShouldExist("started")
 		statusShouldHaveValue(PointTo(MatchAllFields(Fields{
-			"State":        Equal("running"),
-			"Result":       BeEmpty(),
-			"FinishedAt":   BeNil(),
-			"DeletionTime": BeNil(),
-			"Extend":       BeNil(),
-			"JobInfo":      BeNil(),
-			"SlackChannel": BeEmpty(),
+			"State":             Equal("running"),
+			"Result":            BeEmpty(),
+			"FinishedAt":        BeNil(),
+			"DeletionTime":      BeNil(),
+			"Extend":            BeNil(),
+			"FailedSteps":       BeEmpty(),
+			"JobInfo":           BeNil(),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  Equal(1),
 		})))
 		metricsShouldHaveValue("meows_runner_pod_state",
 			MatchAllElementsWithIndex(IndexIdentity, Elements{
@@ -92,17 +108,25 @@ var _ = Describe("Runner", func() {
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"1": PointTo(MatchAllFields(Fields{
-					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("initializing")}),
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("failed")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"2": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("initializing")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
+				"3": PointTo(MatchAllFields(Fields{
 					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("running")}),
 					"Value": BeNumerically("==", 1.0),
 				})),
-				"3": PointTo(MatchAllFields(Fields{
+				"4": PointTo(MatchAllFields(Fields{
 					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("stale")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
+				"5": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("terminating")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
 			}),
 		)
 		metricsShouldNotExist("meows_runner_listener_exit_state")
@@ -123,12 +147,15 @@ var _ = Describe("Runner", func() {
 			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
 			"DeletionTime": BeNil(),
 			"Extend":       PointTo(BeFalse()),
+			"FailedSteps":  BeEmpty(),
 			"JobInfo": PointTo(MatchFields(IgnoreExtras, Fields{
 				"Actor":      Equal("actor"),
 				"Repository": Equal("meows"),
 				"GitRef":     Equal("branch"),
 			})),
-			"SlackChannel": BeEmpty(),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  Equal(1),
 		})))
 		metricsShouldHaveValue("meows_runner_pod_state",
 			MatchAllElementsWithIndex(IndexIdentity, Elements{
@@ -137,17 +164,38 @@ var _ = Describe("Runner", func() {
 					"Value": BeNumerically("==", 1.0),
 				})),
 				"1": PointTo(MatchAllFields(Fields{
-					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("initializing")}),
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("failed")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"2": PointTo(MatchAllFields(Fields{
-					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("running")}),
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("initializing")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"3": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("running")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
+				"4": PointTo(MatchAllFields(Fields{
 					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("stale")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
+				"5": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("terminating")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
+			}),
+		)
+		metricsShouldHaveValue("meows_runner_job_info",
+			MatchAllElementsWithIndex(IndexIdentity, Elements{
+				"0": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{
+						"runnerpool": Equal("fake-pod-ns/fake-runnerpool"),
+						"repository": Equal("meows"),
+						"event":      BeEmpty(),
+						"result":     Equal("unknown"),
+					}),
+					"Value": BeNumerically("==", 1.0),
+				})),
 			}),
 		)
 		metricsShouldNotExist("meows_runner_listener_exit_state")
@@ -166,13 +214,16 @@ var _ = Describe("Runner", func() {
 
 		By("checking outputs")
 		statusShouldHaveValue(PointTo(MatchAllFields(Fields{
-			"State":        Equal("debugging"),
-			"Result":       Equal("unknown"),
-			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
-			"DeletionTime": BeNil(),
-			"Extend":       PointTo(BeTrue()),
-			"JobInfo":      BeNil(),
-			"SlackChannel": BeEmpty(),
+			"State":             Equal("debugging"),
+			"Result":            Equal("unknown"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
+			"DeletionTime":      BeNil(),
+			"Extend":            PointTo(BeTrue()),
+			"FailedSteps":       BeEmpty(),
+			"JobInfo":           BeNil(),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  Equal(1),
 		})))
 		metricsShouldHaveValue("meows_runner_pod_state",
 			MatchAllElementsWithIndex(IndexIdentity, Elements{
@@ -181,17 +232,25 @@ var _ = Describe("Runner", func() {
 					"Value": BeNumerically("==", 1.0),
 				})),
 				"1": PointTo(MatchAllFields(Fields{
-					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("initializing")}),
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("failed")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"2": PointTo(MatchAllFields(Fields{
-					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("running")}),
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("initializing")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"3": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("running")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
+				"4": PointTo(MatchAllFields(Fields{
 					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("stale")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
+				"5": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("terminating")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
 			}),
 		)
 		metricsShouldNotExist("meows_runner_listener_exit_state")
@@ -210,13 +269,16 @@ var _ = Describe("Runner", func() {
 
 		By("checking outputs")
 		statusShouldHaveValue(PointTo(MatchAllFields(Fields{
-			"State":        Equal("debugging"),
-			"Result":       Equal("failure"),
-			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
-			"DeletionTime": BeNil(),
-			"Extend":       PointTo(BeTrue()),
-			"JobInfo":      BeNil(),
-			"SlackChannel": BeEmpty(),
+			"State":             Equal("debugging"),
+			"Result":            Equal("failure"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
+			"DeletionTime":      BeNil(),
+			"Extend":            PointTo(BeTrue()),
+			"FailedSteps":       BeEmpty(),
+			"JobInfo":           BeNil(),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  Equal(1),
 		})))
 		metricsShouldHaveValue("meows_runner_pod_state",
 			MatchAllElementsWithIndex(IndexIdentity, Elements{
@@ -225,34 +287,45 @@ var _ = Describe("Runner", func() {
 					"Value": BeNumerically("==", 1.0),
 				})),
 				"1": PointTo(MatchAllFields(Fields{
-					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("initializing")}),
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("failed")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"2": PointTo(MatchAllFields(Fields{
-					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("running")}),
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("initializing")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"3": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("running")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
+				"4": PointTo(MatchAllFields(Fields{
 					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("stale")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
+				"5": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("terminating")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
 			}),
 		)
 		metricsShouldNotExist("meows_runner_listener_exit_state")
 
 		By("requesting API")
 		extendTo := time.Now().Add(2 * time.Hour)
-		NewClient().PutDeletionTime(context.Background(), "localhost", extendTo)
+		NewClient(nil).PutDeletionTime(context.Background(), testPod(), extendTo)
 
 		By("checking outputs")
 		statusShouldHaveValue(PointTo(MatchAllFields(Fields{
-			"State":        Equal("debugging"),
-			"Result":       Equal("failure"),
-			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
-			"DeletionTime": PointTo(BeTemporally("~", extendTo, 500*time.Millisecond)),
-			"Extend":       PointTo(BeTrue()),
-			"JobInfo":      BeNil(),
-			"SlackChannel": BeEmpty(),
+			"State":             Equal("debugging"),
+			"Result":            Equal("failure"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
+			"DeletionTime":      PointTo(BeTemporally("~", extendTo, 500*time.Millisecond)),
+			"Extend":            PointTo(BeTrue()),
+			"FailedSteps":       BeEmpty(),
+			"JobInfo":           BeNil(),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  Equal(1),
 		})))
 		metricsShouldHaveValue("meows_runner_pod_state",
 			MatchAllElementsWithIndex(IndexIdentity, Elements{
@@ -261,17 +334,25 @@ var _ = Describe("Runner", func() {
 					"Value": BeNumerically("==", 1.0),
 				})),
 				"1": PointTo(MatchAllFields(Fields{
-					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("initializing")}),
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("failed")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"2": PointTo(MatchAllFields(Fields{
-					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("running")}),
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("initializing")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"3": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("running")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
+				"4": PointTo(MatchAllFields(Fields{
 					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("stale")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
+				"5": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("terminating")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
 			}),
 		)
 		metricsShouldNotExist("meows_runner_listener_exit_state")
@@ -287,13 +368,16 @@ var _ = Describe("Runner", func() {
 
 		By("checking outputs")
 		statusShouldHaveValue(PointTo(MatchAllFields(Fields{
-			"State":        Equal("stale"),
-			"Result":       BeEmpty(),
-			"FinishedAt":   BeNil(),
-			"DeletionTime": BeNil(),
-			"Extend":       BeNil(),
-			"JobInfo":      BeNil(),
-			"SlackChannel": BeEmpty(),
+			"State":             Equal("stale"),
+			"Result":            BeEmpty(),
+			"FinishedAt":        BeNil(),
+			"DeletionTime":      BeNil(),
+			"Extend":            BeNil(),
+			"FailedSteps":       BeEmpty(),
+			"JobInfo":           BeNil(),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  BeZero(),
 		})))
 		metricsShouldHaveValue("meows_runner_pod_state",
 			MatchAllElementsWithIndex(IndexIdentity, Elements{
@@ -302,22 +386,320 @@ var _ = Describe("Runner", func() {
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"1": PointTo(MatchAllFields(Fields{
-					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("initializing")}),
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("failed")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"2": PointTo(MatchAllFields(Fields{
-					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("running")}),
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("initializing")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"3": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("running")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
+				"4": PointTo(MatchAllFields(Fields{
 					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("stale")}),
 					"Value": BeNumerically("==", 1.0),
 				})),
+				"5": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("terminating")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
 			}),
 		)
 		metricsShouldNotExist("meows_runner_listener_exit_state")
 	})
 
+	It("should stop the listener at once on termination when no job is running", func() {
+		By("starting runner")
+		resetEnv(false)
+		listener := newListenerMock()
+		r, cancel := startTestRunner(listener)
+		defer cancel()
+		listener.configureCh <- nil
+		time.Sleep(time.Second)
+
+		By("terminating runner")
+		done := make(chan struct{})
+		go func() {
+			r.Terminate(context.Background())
+			close(done)
+		}()
+		Eventually(done).Should(BeClosed())
+
+		By("checking outputs")
+		statusShouldHaveValue(PointTo(MatchFields(IgnoreExtras, Fields{
+			"State":  Equal("terminating"),
+			"Result": BeEmpty(),
+		})))
+		metricsShouldHaveValue("meows_runner_pod_state",
+			ContainElement(PointTo(MatchAllFields(Fields{
+				"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("terminating")}),
+				"Value": BeNumerically("==", 1.0),
+			}))),
+		)
+	})
+
+	It("should wait for the running job to finish on termination", func() {
+		By("starting runner")
+		resetEnv(false)
+		listener := newListenerMock()
+		r, cancel := startTestRunner(listener)
+		defer cancel()
+		listener.configureCh <- nil
+		createWorkerLog()
+		time.Sleep(time.Second)
+
+		By("terminating runner")
+		done := make(chan struct{})
+		go func() {
+			r.Terminate(context.Background())
+			close(done)
+		}()
+		Consistently(done, time.Second).ShouldNot(BeClosed())
+		statusShouldHaveValue(PointTo(MatchFields(IgnoreExtras, Fields{
+			"State": Equal("terminating"),
+		})))
+
+		By("finishing the job")
+		listener.listenCh <- nil
+		Consistently(done, time.Second).ShouldNot(BeClosed())
+
+		By("reading the result")
+		statusShouldHaveValue(PointTo(MatchFields(IgnoreExtras, Fields{
+			"State":       Equal("debugging"),
+			"Result":      Equal("failure"),
+			"FailedSteps": Equal([]string{"Run make test"}),
+		})))
+		Eventually(done).Should(BeClosed())
+	})
+
+	It("should stop the listener when the grace period expires", func() {
+		By("starting runner with the grace period")
+		resetEnv(false)
+		os.Setenv(constants.RunnerOptionEnvName, `{"termination_grace_period_seconds": 7}`)
+		listener := newListenerMock()
+		r, cancel := startTestRunner(listener)
+		defer cancel()
+		listener.configureCh <- nil
+		createWorkerLog()
+		time.Sleep(time.Second)
+
+		By("terminating runner")
+		done := make(chan struct{})
+		go func() {
+			r.Terminate(context.Background())
+			close(done)
+		}()
+		Consistently(done, time.Second).ShouldNot(BeClosed())
+		Eventually(done, 3*time.Second).Should(BeClosed())
+
+		By("checking outputs")
+		statusShouldHaveValue(PointTo(MatchFields(IgnoreExtras, Fields{
+			"State":  Equal("terminating"),
+			"Result": BeEmpty(),
+		})))
+	})
+
+	It("should re-launch the listener exited with retryable errors", func() {
+		By("starting runner with max retries")
+		resetEnv(false)
+		os.Setenv(constants.RunnerOptionEnvName, `{"max_listener_retries": 2}`)
+		listener := newListenerMock()
+		cancel := startRunner(listener)
+		defer cancel()
+		listener.configureCh <- nil
+
+		By("exiting the listener with retryable errors")
+		listener.listenCh <- fmt.Errorf("%w: code 2", errRetryableExit)
+		listener.listenCh <- fmt.Errorf("%w: code 3", errRetryableExit)
+		time.Sleep(time.Second)
+		statusShouldHaveValue(PointTo(MatchFields(IgnoreExtras, Fields{
+			"State":            Equal("running"),
+			"ListenerAttempts": Equal(3),
+		})))
+
+		By("finishing the job")
+		listener.listenCh <- nil
+		time.Sleep(time.Second)
+		statusShouldHaveValue(PointTo(MatchFields(IgnoreExtras, Fields{
+			"State":            Equal("debugging"),
+			"Result":           Equal("unknown"),
+			"ListenerAttempts": Equal(3),
+		})))
+	})
+
+	It("should fail when the listener exceeds max retries", func() {
+		By("starting runner with max retries")
+		resetEnv(false)
+		os.Setenv(constants.RunnerOptionEnvName, `{"max_listener_retries": 1}`)
+		listener := newListenerMock()
+		cancel := startRunner(listener)
+		defer cancel()
+		listener.configureCh <- nil
+
+		By("exiting the listener with retryable errors")
+		listener.listenCh <- fmt.Errorf("%w: code 2", errRetryableExit)
+		listener.listenCh <- fmt.Errorf("%w: code 2", errRetryableExit)
+		time.Sleep(time.Second)
+
+		By("checking outputs")
+		statusShouldHaveValue(PointTo(MatchFields(IgnoreExtras, Fields{
+			"State":            Equal("failed"),
+			"Result":           BeEmpty(),
+			"ListenerAttempts": Equal(2),
+		})))
+		metricsShouldHaveValue("meows_runner_pod_state",
+			ContainElement(PointTo(MatchAllFields(Fields{
+				"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("failed")}),
+				"Value": BeNumerically("==", 1.0),
+			}))),
+		)
+	})
+
+	It("should register runner with custom labels", func() {
+		By("starting runner with labels")
+		resetEnv(false)
+		opt, err := json.Marshal(&Option{
+			Labels: []string{"large", "ubuntu-22.04"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		os.Setenv(constants.RunnerOptionEnvName, string(opt))
+
+		listener := newListenerMock()
+		cancel := startRunner(listener)
+		defer cancel()
+
+		By("checking config arguments")
+		listener.configureCh <- nil
+		Expect(listener.configArgs).To(ContainElements("--labels", "fake-pod-ns/fake-runnerpool,large,ubuntu-22.04"))
+	})
+
+	It("should register runner in runner group", func() {
+		By("starting runner with runner group")
+		resetEnv(true)
+		opt, err := json.Marshal(&Option{
+			RunnerGroup: "fake-group",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		os.Setenv(constants.RunnerOptionEnvName, string(opt))
+
+		listener := newListenerMock()
+		cancel := startRunner(listener)
+		defer cancel()
+
+		By("checking config arguments")
+		listener.configureCh <- nil
+		Expect(listener.configArgs).To(ContainElements("--runnergroup", "fake-group"))
+	})
+
+	It("should register runner to GitHub Enterprise Server", func() {
+		By("starting runner with GitHub URL")
+		resetEnv(false)
+		opt, err := json.Marshal(&Option{
+			GitHubURL: "https://github.example.com/",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		os.Setenv(constants.RunnerOptionEnvName, string(opt))
+
+		listener := newListenerMock()
+		cancel := startRunner(listener)
+		defer cancel()
+
+		By("checking config arguments")
+		listener.configureCh <- nil
+		Expect(listener.configArgs).To(ContainElements("--url", "https://github.example.com/fake-org/fake-repo"))
+	})
+
+	It("should register runner to enterprise", func() {
+		By("starting enterprise-level runner")
+		resetEnv(false)
+		os.Unsetenv(constants.RunnerRepoEnvName)
+		os.Setenv(constants.RunnerEnterpriseEnvName, "fake-enterprise")
+
+		listener := newListenerMock()
+		cancel := startRunner(listener)
+		defer cancel()
+
+		By("checking config arguments")
+		listener.configureCh <- nil
+		Expect(listener.configArgs).To(ContainElements("--url", "https://github.com/enterprises/fake-enterprise"))
+	})
+
+	It("should run listener with jit config", func() {
+		By("starting runner with jit config enabled")
+		resetEnv(false)
+		opt, err := json.Marshal(&Option{
+			JITConfig: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		os.Setenv(constants.RunnerOptionEnvName, string(opt))
+
+		listener := newListenerMock()
+		cancel := startRunner(listener)
+		defer cancel()
+
+		By("checking the runner waits for jit config")
+		statusShouldHaveValue(PointTo(MatchFields(IgnoreExtras, Fields{
+			"State":             Equal("initializing"),
+			"JITConfigRequired": BeTrue(),
+			"ListenerAttempts":  BeZero(),
+		})))
+
+		By("mounting the api token")
+		key := []byte("0123456789abcdef0123456789abcdef")
+		tokenDir := filepath.Join(testVarDir, constants.APITokenDirName)
+		Expect(os.MkdirAll(tokenDir, 0755)).To(Succeed())
+		token := APIToken(key, "fake-pod-ns", "fake-runnerpool")
+		Expect(os.WriteFile(filepath.Join(tokenDir, constants.APITokenFileName), []byte(token), 0644)).To(Succeed())
+
+		By("delivering jit config without the token")
+		Expect(NewClient(nil).PutJITConfig(context.Background(), testPod(), "malicious-jit-config")).NotTo(Succeed())
+
+		By("delivering jit config")
+		runnerClient := NewClient(key)
+		Expect(runnerClient.PutJITConfig(context.Background(), testPod(), "fake-jit-config")).To(Succeed())
+		Expect(runnerClient.PutJITConfig(context.Background(), testPod(), "fake-jit-config")).To(MatchError(ErrJITConfigConflict))
+		Expect(os.RemoveAll(tokenDir)).To(Succeed())
+		time.Sleep(time.Second)
+
+		statusShouldHaveValue(PointTo(MatchFields(IgnoreExtras, Fields{
+			"State":             Equal("running"),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  Equal(1),
+		})))
+		Expect(listener.configArgs).To(BeNil())
+
+		By("finishing the job")
+		listener.listenCh <- nil
+		time.Sleep(time.Second)
+		Expect(listener.jitConfig).To(Equal("fake-jit-config"))
+		statusShouldHaveValue(PointTo(MatchFields(IgnoreExtras, Fields{
+			"State": Equal("debugging"),
+		})))
+	})
+
+	It("should not accept jit config without the api token", func() {
+		By("starting runner with jit config enabled")
+		resetEnv(false)
+		opt, err := json.Marshal(&Option{
+			JITConfig: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		os.Setenv(constants.RunnerOptionEnvName, string(opt))
+
+		listener := newListenerMock()
+		cancel := startRunner(listener)
+		defer cancel()
+
+		By("delivering jit config")
+		Expect(NewClient(nil).PutJITConfig(context.Background(), testPod(), "malicious-jit-config")).NotTo(Succeed())
+		statusShouldHaveValue(PointTo(MatchFields(IgnoreExtras, Fields{
+			"State":             Equal("initializing"),
+			"JITConfigRequired": BeTrue(),
+		})))
+	})
+
 	It("should run setup command", func() {
 		By("starting runner with setup command")
 		resetEnv(false)
@@ -337,13 +719,16 @@ var _ = Describe("Runner", func() {
 
 		flagFileShouldExist("started")
 		statusShouldHaveValue(PointTo(MatchAllFields(Fields{
-			"State":        Equal("initializing"),
-			"Result":       BeEmpty(),
-			"FinishedAt":   BeNil(),
-			"DeletionTime": BeNil(),
-			"Extend":       BeNil(),
-			"JobInfo":      BeNil(),
-			"SlackChannel": BeEmpty(),
+			"State":             Equal("initializing"),
+			"Result":            BeEmpty(),
+			"FinishedAt":        BeNil(),
+			"DeletionTime":      BeNil(),
+			"Extend":            BeNil(),
+			"FailedSteps":       BeEmpty(),
+			"JobInfo":           BeNil(),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  BeZero(),
 		})))
 		metricsShouldHaveValue("meows_runner_pod_state",
 			MatchAllElementsWithIndex(IndexIdentity, Elements{
@@ -352,17 +737,25 @@ var _ = Describe("Runner", func() {
 					"Value": BeNumerically("==", 0.0),
 				})),
 				"1": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("failed")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
+				"2": PointTo(MatchAllFields(Fields{
 					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("initializing")}),
 					"Value": BeNumerically("==", 1.0),
 				})),
-				"2": PointTo(MatchAllFields(Fields{
+				"3": PointTo(MatchAllFields(Fields{
 					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("running")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
-				"3": PointTo(MatchAllFields(Fields{
+				"4": PointTo(MatchAllFields(Fields{
 					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("stale")}),
 					"Value": BeNumerically("==", 0.0),
 				})),
+				"5": PointTo(MatchAllFields(Fields{
+					"Label": MatchAllKeys(Keys{"runnerpool": Equal("fake-pod-ns/fake-runnerpool"), "state": Equal("terminating")}),
+					"Value": BeNumerically("==", 0.0),
+				})),
 			}),
 		)
 		metricsShouldNotExist("meows_runner_listener_exit_state")
@@ -381,13 +774,16 @@ var _ = Describe("Runner", func() {
 
 		By("checking outputs")
 		statusShouldHaveValue(PointTo(MatchAllFields(Fields{
-			"State":        Equal("debugging"),
-			"Result":       Equal("success"),
-			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
-			"DeletionTime": BeNil(),
-			"Extend":       PointTo(BeFalse()),
-			"JobInfo":      BeNil(),
-			"SlackChannel": BeEmpty(),
+			"State":             Equal("debugging"),
+			"Result":            Equal("success"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
+			"DeletionTime":      BeNil(),
+			"Extend":            PointTo(BeFalse()),
+			"FailedSteps":       BeEmpty(),
+			"JobInfo":           BeNil(),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  Equal(1),
 		})))
 	})
 
@@ -404,13 +800,16 @@ var _ = Describe("Runner", func() {
 
 		By("checking outputs")
 		statusShouldHaveValue(PointTo(MatchAllFields(Fields{
-			"State":        Equal("debugging"),
-			"Result":       Equal("failure"),
-			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
-			"DeletionTime": BeNil(),
-			"Extend":       PointTo(BeFalse()),
-			"JobInfo":      BeNil(),
-			"SlackChannel": BeEmpty(),
+			"State":             Equal("debugging"),
+			"Result":            Equal("failure"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
+			"DeletionTime":      BeNil(),
+			"Extend":            PointTo(BeFalse()),
+			"FailedSteps":       BeEmpty(),
+			"JobInfo":           BeNil(),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  Equal(1),
 		})))
 	})
 
@@ -427,13 +826,78 @@ var _ = Describe("Runner", func() {
 
 		By("checking outputs")
 		statusShouldHaveValue(PointTo(MatchAllFields(Fields{
-			"State":        Equal("debugging"),
-			"Result":       Equal("cancelled"),
-			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
-			"DeletionTime": BeNil(),
-			"Extend":       PointTo(BeFalse()),
-			"JobInfo":      BeNil(),
-			"SlackChannel": BeEmpty(),
+			"State":             Equal("debugging"),
+			"Result":            Equal("cancelled"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
+			"DeletionTime":      BeNil(),
+			"Extend":            PointTo(BeFalse()),
+			"FailedSteps":       BeEmpty(),
+			"JobInfo":           BeNil(),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  Equal(1),
+		})))
+	})
+
+	It("should read the job result from the worker log", func() {
+		By("starting runner with creating success file")
+		resetEnv(false)
+		listener := newListenerMock("success")
+		cancel := startRunner(listener)
+		defer cancel()
+
+		createWorkerLog()
+
+		listener.configureCh <- nil
+		listener.listenCh <- nil
+		finishedAt := time.Now()
+		time.Sleep(time.Second)
+
+		By("checking outputs")
+		statusShouldHaveValue(PointTo(MatchAllFields(Fields{
+			"State":             Equal("debugging"),
+			"Result":            Equal("failure"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
+			"DeletionTime":      BeNil(),
+			"Extend":            PointTo(BeTrue()),
+			"FailedSteps":       Equal([]string{"Run make test"}),
+			"JobInfo":           BeNil(),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  Equal(1),
+		})))
+	})
+
+	It("should read the job result written by the job-completed hook", func() {
+		By("starting runner with creating success file")
+		resetEnv(false)
+		listener := newListenerMock("success")
+		cancel := startRunner(listener)
+		defer cancel()
+
+		err := WriteJobResultFile(filepath.Join(testVarDir, "job_result.json"), &JobResult{
+			Result:      JobResultCancelled,
+			FailedSteps: []string{"Run make lint"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		listener.configureCh <- nil
+		listener.listenCh <- nil
+		finishedAt := time.Now()
+		time.Sleep(time.Second)
+
+		By("checking outputs")
+		statusShouldHaveValue(PointTo(MatchAllFields(Fields{
+			"State":             Equal("debugging"),
+			"Result":            Equal("cancelled"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
+			"DeletionTime":      BeNil(),
+			"Extend":            PointTo(BeFalse()),
+			"FailedSteps":       Equal([]string{"Run make lint"}),
+			"JobInfo":           BeNil(),
+			"SlackChannel":      BeEmpty(),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  Equal(1),
 		})))
 	})
 
@@ -456,21 +920,85 @@ var _ = Describe("Runner", func() {
 
 		By("checking outputs")
 		statusShouldHaveValue(PointTo(MatchAllFields(Fields{
-			"State":        Equal("debugging"),
-			"Result":       Equal("success"),
-			"FinishedAt":   PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
-			"DeletionTime": BeNil(),
-			"Extend":       PointTo(BeFalse()),
-			"JobInfo":      BeNil(),
-			"SlackChannel": Equal("#test1"),
+			"State":             Equal("debugging"),
+			"Result":            Equal("success"),
+			"FinishedAt":        PointTo(BeTemporally("~", finishedAt, 500*time.Millisecond)),
+			"DeletionTime":      BeNil(),
+			"Extend":            PointTo(BeFalse()),
+			"FailedSteps":       BeEmpty(),
+			"JobInfo":           BeNil(),
+			"SlackChannel":      Equal("#test1"),
+			"JITConfigRequired": BeFalse(),
+			"ListenerAttempts":  Equal(1),
 		})))
 
 		By("remove slack_channel file")
 		err = os.Remove(slackChannelFile)
 		Expect(err).ToNot(HaveOccurred())
 	})
+
+	It("should authenticate the requests with the api token", func() {
+		By("starting runner with the api token")
+		resetEnv(false)
+		key := []byte("0123456789abcdef0123456789abcdef")
+		tokenDir := filepath.Join(testVarDir, constants.APITokenDirName)
+		Expect(os.MkdirAll(tokenDir, 0755)).To(Succeed())
+		token := APIToken(key, "fake-pod-ns", "fake-runnerpool")
+		Expect(os.WriteFile(filepath.Join(tokenDir, constants.APITokenFileName), []byte(token), 0644)).To(Succeed())
+
+		listener := newListenerMock()
+		cancel := startRunner(listener)
+		defer cancel()
+
+		By("requesting without the token")
+		_, err := NewClient(nil).GetStatus(context.Background(), testPod())
+		Expect(err).To(HaveOccurred())
+		Expect(NewClient(nil).PutDeletionTime(context.Background(), testPod(), time.Now())).NotTo(Succeed())
+
+		By("requesting with the token of another RunnerPool")
+		po := testPod()
+		po.Labels[constants.AppInstanceLabelKey] = "other-runnerpool"
+		_, err = NewClient(key).GetStatus(context.Background(), po)
+		Expect(err).To(HaveOccurred())
+
+		By("requesting with the token derived from another key")
+		_, err = NewClient([]byte("fedcba9876543210fedcba9876543210")).GetStatus(context.Background(), testPod())
+		Expect(err).To(HaveOccurred())
+
+		By("requesting with the token")
+		st, err := NewClient(key).GetStatus(context.Background(), testPod())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(st.State).To(Equal("initializing"))
+		deletionTime := time.Now().Add(time.Hour)
+		Expect(NewClient(key).PutDeletionTime(context.Background(), testPod(), deletionTime)).To(Succeed())
+		st, err = NewClient(key).GetStatus(context.Background(), testPod())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(st.DeletionTime).To(PointTo(BeTemporally("~", deletionTime, 500*time.Millisecond)))
+
+		By("serving the metrics without the token")
+		metricsShouldHaveValue("meows_runner_pod_state", Not(BeEmpty()))
+
+		listener.configureCh <- nil
+		listener.listenCh <- nil
+		time.Sleep(time.Second)
+	})
 })
 
+func testPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fake-pod-name",
+			Namespace: "fake-pod-ns",
+			Labels: map[string]string{
+				constants.AppInstanceLabelKey: "fake-runnerpool",
+			},
+		},
+		Status: corev1.PodStatus{
+			PodIP: "localhost",
+		},
+	}
+}
+
 func TestRunner(t *testing.T) {
 	RegisterFailHandler(Fail)
 
@@ -484,6 +1012,8 @@ func TestRunner(t *testing.T) {
 
 type listenerMock struct {
 	flagFiles   []string
+	configArgs  []string
+	jitConfig   string
 	configureCh chan error
 	listenCh    chan error
 }
@@ -498,11 +1028,23 @@ func newListenerMock(flagFiles ...string) *listenerMock {
 
 func (l *listenerMock) configure(ctx context.Context, configArgs []string) error {
 	fmt.Println(configArgs)
-	return <-l.configureCh
+	l.configArgs = configArgs
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-l.configureCh:
+		return err
+	}
 }
 
-func (l *listenerMock) listen(ctx context.Context) error {
-	ret := <-l.listenCh
+func (l *listenerMock) listen(ctx context.Context, jitConfig string) error {
+	l.jitConfig = jitConfig
+	var ret error
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case ret = <-l.listenCh:
+	}
 	for _, file := range l.flagFiles {
 		createFlagFile(file)
 	}
@@ -522,6 +1064,7 @@ func resetEnv(orgRunner bool) {
 	os.Setenv(constants.PodNamespaceEnvName, "fake-pod-ns")
 	os.Setenv(constants.RunnerPoolNameEnvName, "fake-runnerpool")
 	os.Setenv(constants.RunnerOptionEnvName, "{}")
+	os.Unsetenv(constants.RunnerEnterpriseEnvName)
 	if orgRunner {
 		os.Setenv(constants.RunnerOrgEnvName, "fake-org")
 		os.Unsetenv(constants.RunnerRepoEnvName)
@@ -532,8 +1075,14 @@ func resetEnv(orgRunner bool) {
 }
 
 func startRunner(listener Listener) context.CancelFunc {
+	_, cancel := startTestRunner(listener)
+	return cancel
+}
+
+func startTestRunner(listener Listener) (*Runner, context.CancelFunc) {
 	r, err := NewRunner(listener, fmt.Sprintf(":%d", constants.RunnerListenPort), testRunnerDir, testWorkDir, testVarDir)
 	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+	r.restartBackoff = restartBackoff{initialDelay: 100 * time.Millisecond, maxDelay: time.Second}
 	ctx, cancel := context.WithCancel(context.Background())
 	logger := zap.New()
 	ctx = log.IntoContext(ctx, logger)
@@ -542,7 +1091,7 @@ func startRunner(listener Listener) context.CancelFunc {
 		Expect(r.Run(ctx)).To(Succeed())
 	}()
 	time.Sleep(2 * time.Second) // delay
-	return cancel
+	return r, cancel
 }
 
 func createFakeTokenFile() {
@@ -551,6 +1100,13 @@ func createFakeTokenFile() {
 	ExpectWithOffset(1, err).ToNot(HaveOccurred())
 }
 
+func createWorkerLog() {
+	diagDir := filepath.Join(testRunnerDir, "_diag")
+	ExpectWithOffset(1, os.MkdirAll(diagDir, 0755)).To(Succeed())
+	err := os.WriteFile(filepath.Join(diagDir, "Worker_20240101-000000-utc.log"), []byte(testWorkerLog), 0644)
+	ExpectWithOffset(1, err).ToNot(HaveOccurred())
+}
+
 func createJobInfoFile() {
 	jobInfo := &JobInfo{
 		Actor:      "actor",
@@ -579,8 +1135,8 @@ func flagFileShouldNotExist(filename string) {
 }
 
 func statusShouldHaveValue(matcher gomegatypes.GomegaMatcher) {
-	runnerClient := NewClient()
-	st, err := runnerClient.GetStatus(context.Background(), "localhost")
+	runnerClient := NewClient(nil)
+	st, err := runnerClient.GetStatus(context.Background(), testPod())
 	ExpectWithOffset(1, err).ShouldNot(HaveOccurred())
 	ExpectWithOffset(1, st).To(matcher)
 }
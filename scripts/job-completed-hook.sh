@@ -0,0 +1,5 @@
+#!/bin/sh
+
+# This script is run by the runner as the job-completed hook (ACTIONS_RUNNER_HOOK_JOB_COMPLETED).
+# Never fail the job even if the result cannot be recorded.
+job-completed || true